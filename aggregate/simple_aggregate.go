@@ -1,6 +1,8 @@
 package aggregate
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -22,6 +24,17 @@ func NewSimpleAggregateMetric(metrics ...stream.SimpleMetric) *SimpleAggregateMe
 	return &SimpleAggregateMetric{metrics: metrics}
 }
 
+// String returns a string representation of the metric,
+// which includes the string representations of the metrics it wraps.
+func (s *SimpleAggregateMetric) String() string {
+	name := "aggregate.SimpleAggregateMetric"
+	metrics := make([]string, len(s.metrics))
+	for i, metric := range s.metrics {
+		metrics[i] = metric.String()
+	}
+	return fmt.Sprintf("%s_{metrics:[%s]}", name, strings.Join(metrics, " "))
+}
+
 // Push adds a new value for the metrics to consume.
 func (s *SimpleAggregateMetric) Push(x float64) error {
 	s.mux.Lock()
@@ -50,6 +50,12 @@ func TestNewSimpleAggregateMetric(t *testing.T) {
 	assert.Equal(t, []stream.SimpleMetric{metric1, metric2}, metric.metrics)
 }
 
+func TestSimpleAggregateMetricString(t *testing.T) {
+	metric := NewSimpleAggregateMetric(&mockMetric{val: 1}, &mockMetric{val: 2})
+	expectedString := "aggregate.SimpleAggregateMetric_{metrics:[mockMetric_val:1.000000 mockMetric_val:2.000000]}"
+	assert.Equal(t, expectedString, metric.String())
+}
+
 func TestSimpleAggregateMetricPush(t *testing.T) {
 	t.Run("pass: pushes value to each metric", func(t *testing.T) {
 		metric1 := &mockMetric{}
@@ -1,6 +1,8 @@
 package aggregate
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/K4Mobility/stream"
@@ -21,6 +23,17 @@ func NewSimpleJointAggregateMetric(metrics ...stream.SimpleJointMetric) *SimpleJ
 	return &SimpleJointAggregateMetric{metrics: metrics}
 }
 
+// String returns a string representation of the metric,
+// which includes the string representations of the metrics it wraps.
+func (s *SimpleJointAggregateMetric) String() string {
+	name := "aggregate.SimpleJointAggregateMetric"
+	metrics := make([]string, len(s.metrics))
+	for i, metric := range s.metrics {
+		metrics[i] = metric.String()
+	}
+	return fmt.Sprintf("%s_{metrics:[%s]}", name, strings.Join(metrics, " "))
+}
+
 // Push adds a new value for the metrics to consume.
 func (s *SimpleJointAggregateMetric) Push(xs ...float64) error {
 	s.mux.Lock()
@@ -50,6 +50,12 @@ func TestNewSimpleJointAggregateMetric(t *testing.T) {
 	assert.Equal(t, []stream.SimpleJointMetric{metric1, metric2}, metric.metrics)
 }
 
+func TestSimpleJointAggregateMetricString(t *testing.T) {
+	metric := NewSimpleJointAggregateMetric(&mockJointMetric{val: 1}, &mockJointMetric{val: 2})
+	expectedString := "aggregate.SimpleJointAggregateMetric_{metrics:[mockJointMetric_val:1.000000 mockJointMetric_val:2.000000]}"
+	assert.Equal(t, expectedString, metric.String())
+}
+
 func TestSimpleJointAggregateMetricPush(t *testing.T) {
 	t.Run("pass: pushes value to each metric", func(t *testing.T) {
 		metric1 := &mockJointMetric{}
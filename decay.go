@@ -0,0 +1,32 @@
+package stream
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+/* These helpers convert the usual parameterizations of an exponentially
+ * weighted moving average into the decay factor taken by the metrics and
+ * configs in this library, i.e. the weight given to the newest value, so that
+ * a value of age k is weighted in proportion to (1-decay)^k.
+ */
+
+// DecayFromHalfLife returns the decay factor under which the weight of a value
+// halves every n values, i.e. 1 - 2^(-1/n); n must be positive.
+func DecayFromHalfLife(n int) (float64, error) {
+	if n <= 0 {
+		return 0, errors.Errorf("half-life %d is not positive", n)
+	}
+	return 1 - math.Exp2(-1/float64(n)), nil
+}
+
+// DecayFromSpan returns the decay factor of an exponentially weighted moving average
+// with a span of n values, i.e. 2/(n+1); n must be greater than 1, since a span of 1
+// would give all of the weight to the newest value.
+func DecayFromSpan(n int) (float64, error) {
+	if n <= 1 {
+		return 0, errors.Errorf("span %d is not greater than 1", n)
+	}
+	return 2 / float64(n+1), nil
+}
@@ -0,0 +1,49 @@
+package stream
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecayFromHalfLife(t *testing.T) {
+	t.Run("pass: weight halves every half-life", func(t *testing.T) {
+		for _, n := range []int{1, 2, 10, 100} {
+			decay, err := DecayFromHalfLife(n)
+			require.NoError(t, err)
+			assert.True(t, decay > 0 && decay < 1)
+			assert.InDelta(t, 0.5, math.Pow(1-decay, float64(n)), 1e-12, "n = %d", n)
+		}
+
+		decay, err := DecayFromHalfLife(1)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, decay)
+	})
+
+	t.Run("fail: non-positive half-life is invalid", func(t *testing.T) {
+		for _, n := range []int{0, -1} {
+			_, err := DecayFromHalfLife(n)
+			assert.EqualError(t, err, fmt.Sprintf("half-life %d is not positive", n))
+		}
+	})
+}
+
+func TestDecayFromSpan(t *testing.T) {
+	t.Run("pass: decay is 2/(n+1)", func(t *testing.T) {
+		for n, expected := range map[int]float64{2: 2. / 3, 3: 0.5, 19: 0.1, 199: 0.01} {
+			decay, err := DecayFromSpan(n)
+			require.NoError(t, err)
+			assert.InDelta(t, expected, decay, 1e-12, "n = %d", n)
+		}
+	})
+
+	t.Run("fail: span of at most 1 is invalid", func(t *testing.T) {
+		for _, n := range []int{1, 0, -1} {
+			_, err := DecayFromSpan(n)
+			assert.EqualError(t, err, fmt.Sprintf("span %d is not greater than 1", n))
+		}
+	})
+}
@@ -3,7 +3,6 @@ module github.com/K4Mobility/stream
 go 1.18
 
 require (
-	github.com/Workiva/go-datastructures v1.1.5
 	github.com/gammazero/deque v0.2.1
 	github.com/hashicorp/go-multierror v1.0.0
 	github.com/pkg/errors v0.8.1
@@ -20,5 +19,3 @@ require (
 	golang.org/x/sys v0.0.0-20200930185726-fdedc70b468f // indirect
 	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
-
-replace github.com/Workiva/go-datastructures => github.com/K4Mobility/go-datastructures v1.1.5-kog-v0.0.1
@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/Workiva/go-datastructures/queue"
 	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream/util/ring"
 )
 
 // Autocorr is a metric that tracks the sample autocorrelation.
@@ -14,7 +15,7 @@ import (
 // variable.
 type Autocorr struct {
 	lag   int
-	queue *queue.RingBuffer
+	queue ring.Queue[float64]
 	corr  *Corr
 	core  *Core
 }
@@ -27,7 +28,7 @@ func NewAutocorr(lag int, window int) (*Autocorr, error) {
 
 	return &Autocorr{
 		lag:   lag,
-		queue: queue.NewRingBuffer(uint64(lag)),
+		queue: ring.New[float64](uint64(lag)),
 		corr:  NewCorr(window),
 	}, nil
 }
@@ -54,6 +55,11 @@ func (a *Autocorr) Config() *CoreConfig {
 	return a.corr.Config()
 }
 
+// QueriedSums returns the sums queried from the Core.
+func (a *Autocorr) QueriedSums() SumsConfig {
+	return a.corr.QueriedSums()
+}
+
 // String returns a string representation of the metric.
 func (a *Autocorr) String() string {
 	name := "joint.Autocorr"
@@ -69,7 +75,7 @@ func (a *Autocorr) String() string {
 // the lagged correlation of a series of data against itself.
 func (a *Autocorr) Push(x float64) error {
 	if !a.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
 	a.core.Lock()
@@ -89,8 +95,7 @@ func (a *Autocorr) Push(x float64) error {
 			return errors.Wrap(err, "error popping item from lag queue")
 		}
 
-		val := tail.(float64)
-		err = a.core.UnsafePush(x, val)
+		err = a.core.UnsafePush(x, tail)
 		if err != nil {
 			return errors.Wrap(err, "error pushing to core")
 		}
@@ -107,7 +112,7 @@ func (a *Autocorr) Push(x float64) error {
 // Value returns the value of the sample autocorrelation.
 func (a *Autocorr) Value() (float64, error) {
 	if !a.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	} else if a.corr.core.Count() == 0 {
 		return 0, errors.Errorf(
 			"Not enough values seen; at least %d observations must be made",
@@ -124,7 +129,6 @@ func (a *Autocorr) Clear() {
 		a.corr.core.Lock()
 		defer a.corr.core.Unlock()
 		a.corr.core.UnsafeClear()
-		a.queue.Dispose()
-		a.queue = queue.NewRingBuffer(uint64(a.lag))
+		a.queue.Reset()
 	}
 }
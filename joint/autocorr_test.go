@@ -3,6 +3,7 @@ package joint
 import (
 	"fmt"
 	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -171,6 +172,42 @@ func (s *AutocorrValueSuite) TestValueSuccessForLag0() {
 	testutil.Approx(s.T(), 1., value)
 }
 
+func (s *AutocorrValueSuite) TestValueSuccessForAR1() {
+	// for an AR(1) process x_t = phi * x_{t-1} + e_t, the theoretical
+	// autocorrelation at lag l is phi^l
+	phi := 0.7
+	r := rand.New(rand.NewSource(1))
+
+	autocorr1, err := NewGlobalAutocorr(1)
+	s.Require().NoError(err)
+	err = Init(autocorr1)
+	s.Require().NoError(err)
+
+	autocorr2, err := NewGlobalAutocorr(2)
+	s.Require().NoError(err)
+	err = Init(autocorr2)
+	s.Require().NoError(err)
+
+	x := 0.
+	for i := 0; i < 20000; i++ {
+		x = phi*x + r.NormFloat64()
+
+		err := autocorr1.Push(x)
+		s.Require().NoError(err)
+
+		err = autocorr2.Push(x)
+		s.Require().NoError(err)
+	}
+
+	value, err := autocorr1.Value()
+	s.Require().NoError(err)
+	s.InDelta(phi, value, 0.02)
+
+	value, err = autocorr2.Value()
+	s.Require().NoError(err)
+	s.InDelta(phi*phi, value, 0.02)
+}
+
 func (s *AutocorrValueSuite) TestValueFailOnNullCore() {
 	autocorr, err := NewAutocorr(1, 3)
 	s.Require().NoError(err)
@@ -216,8 +253,8 @@ func TestAutocorrClear(t *testing.T) {
 		32: 0.,
 		62: 0.,
 	}
-	assert.Equal(t, expectedSums, autocorr.core.sums)
-	assert.Equal(t, expectedSums, autocorr.core.newSums)
+	assert.Equal(t, expectedSums, byHash(autocorr.core, autocorr.core.sums))
+	assert.Equal(t, expectedSums, byHash(autocorr.core, autocorr.core.newSums))
 	assert.Equal(t, 0, autocorr.core.count)
 	assert.Equal(t, uint64(0), autocorr.core.queue.Len())
 	assert.Equal(t, uint64(0), autocorr.queue.Len())
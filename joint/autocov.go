@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/Workiva/go-datastructures/queue"
 	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream/util/ring"
 )
 
 // Autocov is a metric that tracks the sample autocovariance.
@@ -14,7 +15,7 @@ import (
 // variable.
 type Autocov struct {
 	lag   int
-	queue *queue.RingBuffer
+	queue ring.Queue[float64]
 	cov   *Cov
 	core  *Core
 }
@@ -27,7 +28,7 @@ func NewAutocov(lag int, window int) (*Autocov, error) {
 
 	return &Autocov{
 		lag:   lag,
-		queue: queue.NewRingBuffer(uint64(lag)),
+		queue: ring.New[float64](uint64(lag)),
 		cov:   NewCov(window),
 	}, nil
 }
@@ -54,6 +55,11 @@ func (a *Autocov) Config() *CoreConfig {
 	return a.cov.Config()
 }
 
+// QueriedSums returns the sums queried from the Core.
+func (a *Autocov) QueriedSums() SumsConfig {
+	return a.cov.QueriedSums()
+}
+
 // String returns a string representation of the metric.
 func (a *Autocov) String() string {
 	name := "joint.Autocov"
@@ -69,7 +75,7 @@ func (a *Autocov) String() string {
 // the lagged covelation of a series of data against itself.
 func (a *Autocov) Push(x float64) error {
 	if !a.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
 	a.core.Lock()
@@ -89,8 +95,7 @@ func (a *Autocov) Push(x float64) error {
 			return errors.Wrap(err, "error popping item from lag queue")
 		}
 
-		val := tail.(float64)
-		err = a.core.UnsafePush(x, val)
+		err = a.core.UnsafePush(x, tail)
 		if err != nil {
 			return errors.Wrap(err, "error pushing to core")
 		}
@@ -107,7 +112,7 @@ func (a *Autocov) Push(x float64) error {
 // Value returns the value of the sample autocovelation.
 func (a *Autocov) Value() (float64, error) {
 	if !a.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	} else if a.cov.core.Count() == 0 {
 		return 0, errors.Errorf(
 			"Not enough values seen; at least %d observations must be made",
@@ -124,7 +129,6 @@ func (a *Autocov) Clear() {
 		a.cov.core.Lock()
 		defer a.cov.core.Unlock()
 		a.cov.core.UnsafeClear()
-		a.queue.Dispose()
-		a.queue = queue.NewRingBuffer(uint64(a.lag))
+		a.queue.Reset()
 	}
 }
@@ -213,8 +213,8 @@ func TestAutocovClear(t *testing.T) {
 		31: 0.,
 		32: 0.,
 	}
-	assert.Equal(t, expectedSums, autocov.core.sums)
-	assert.Equal(t, expectedSums, autocov.core.newSums)
+	assert.Equal(t, expectedSums, byHash(autocov.core, autocov.core.sums))
+	assert.Equal(t, expectedSums, byHash(autocov.core, autocov.core.newSums))
 	assert.Equal(t, 0, autocov.core.count)
 	assert.Equal(t, uint64(0), autocov.core.queue.Len())
 	assert.Equal(t, uint64(0), autocov.queue.Len())
@@ -0,0 +1,127 @@
+package joint
+
+import (
+	"fmt"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// Composite is a wrapper metric that tracks multiple joint metrics with a single
+// shared Core, whose config is the merge of the configs of the metrics (see MergeConfigs).
+// Unlike aggregate.SimpleJointAggregateMetric, each value is only pushed to the Core
+// once. Metrics that keep state outside of their Core (e.g. Autocorr, Autocov and
+// HeteroskedasticityScore, which transform the values they are pushed) cannot be
+// tracked by a Composite, since they are never pushed to directly.
+type Composite struct {
+	metrics []Metric
+	config  *CoreConfig
+	core    *Core
+}
+
+// NewComposite instantiates a Composite struct, returning an error if
+// the configs of the metrics conflict, e.g. if they have differing windows.
+func NewComposite(metrics ...Metric) (*Composite, error) {
+	configs := make([]*CoreConfig, len(metrics))
+	for i, metric := range metrics {
+		configs[i] = metric.Config()
+	}
+
+	config, err := MergeConfigs(configs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error merging configs")
+	}
+
+	return &Composite{
+		metrics: metrics,
+		config:  config,
+	}, nil
+}
+
+// SetCore sets the Core, which is shared by all of the metrics.
+func (c *Composite) SetCore(core *Core) {
+	for _, metric := range c.metrics {
+		metric.SetCore(core)
+	}
+	c.core = core
+}
+
+// IsSetCore returns if the core has been set.
+func (c *Composite) IsSetCore() bool {
+	return c.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (c *Composite) Config() *CoreConfig {
+	return c.config
+}
+
+// QueriedSums returns the sums queried from the Core by any of
+// the metrics that declare them (i.e. that are SumsQueriers).
+func (c *Composite) QueriedSums() SumsConfig {
+	sums := SumsConfig{}
+	for _, metric := range c.metrics {
+		if querier, ok := metric.(SumsQuerier); ok {
+			sums.add(querier.QueriedSums())
+		}
+	}
+	return sums
+}
+
+// String returns a string representation of the metric,
+// which includes the string representations of the metrics it wraps.
+func (c *Composite) String() string {
+	name := "joint.Composite"
+	metrics := make([]string, len(c.metrics))
+	for i, metric := range c.metrics {
+		metrics[i] = metric.String()
+	}
+	return fmt.Sprintf("%s_{metrics:[%s]}", name, strings.Join(metrics, " "))
+}
+
+// Push adds a new tuple of values for all of the metrics to consume.
+func (c *Composite) Push(xs ...float64) error {
+	if !c.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := c.core.Push(xs...)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Values returns the values of the metrics; in particular, it returns
+// a map of strings to values, where the strings are the string
+// representations of each metric (i.e. the result of calling String()).
+func (c *Composite) Values() (map[string]float64, error) {
+	if !c.IsSetCore() {
+		return nil, ErrorCoreNotSet
+	}
+
+	values := map[string]float64{}
+	var result *multierror.Error
+	for _, metric := range c.metrics {
+		val, err := metric.Value()
+		if err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		values[metric.String()] = val
+	}
+
+	err := result.ErrorOrNil()
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving values from metrics")
+	}
+	return values, nil
+}
+
+// Clear resets the metric.
+func (c *Composite) Clear() {
+	if c.IsSetCore() {
+		c.core.Clear()
+	}
+}
@@ -0,0 +1,161 @@
+package joint
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+// coskewness is a minimal metric tracking the (unnormalized) coskewness sum
+// of two variables, for testing metrics that share a Core with others.
+type coskewness struct {
+	window int
+	core   *Core
+}
+
+func (c *coskewness) SetCore(core *Core) {
+	c.core = core
+}
+
+func (c *coskewness) Config() *CoreConfig {
+	return &CoreConfig{Sums: SumsConfig{{2, 1}}, Window: &c.window}
+}
+
+func (c *coskewness) String() string {
+	return fmt.Sprintf("coskewness_{window:%v}", c.window)
+}
+
+func (c *coskewness) Push(xs ...float64) error {
+	return c.core.Push(xs...)
+}
+
+func (c *coskewness) Value() (float64, error) {
+	return c.core.Sum(2, 1)
+}
+
+func (c *coskewness) Clear() {
+	c.core.Clear()
+}
+
+// misconfigured is a coskewness that declares a sum it does not configure.
+type misconfigured struct {
+	coskewness
+}
+
+func (m *misconfigured) QueriedSums() SumsConfig {
+	return SumsConfig{{0, 4}}
+}
+
+func TestNewComposite(t *testing.T) {
+	t.Run("pass: configs are merged", func(t *testing.T) {
+		c, err := NewComposite(NewCorr(3), &coskewness{window: 3})
+		require.NoError(t, err)
+		assert.Equal(t, 3, *c.Config().Window)
+		assert.Equal(t, SumsConfig{{1, 1}, {2, 0}, {0, 2}, {2, 1}}, c.Config().Sums)
+	})
+
+	t.Run("fail: differing windows are invalid", func(t *testing.T) {
+		_, err := NewComposite(NewCov(3), NewCorr(4))
+		testutil.ContainsError(t, err, "configs have differing windows")
+	})
+
+	t.Run("fail: differing decays are invalid", func(t *testing.T) {
+		_, err := NewComposite(NewEWMCov(0.3), NewEWMCorr(0.5))
+		testutil.ContainsError(t, err, "configs have differing decays")
+	})
+
+	t.Run("fail: no metrics is invalid", func(t *testing.T) {
+		_, err := NewComposite()
+		testutil.ContainsError(t, err, "no configs available to merge")
+	})
+}
+
+func TestCompositeQueriedSums(t *testing.T) {
+	t.Run("pass: queried sums of the metrics are merged", func(t *testing.T) {
+		c, err := NewComposite(NewCov(3), NewLinReg(3), &coskewness{window: 3})
+		require.NoError(t, err)
+		assert.Equal(t, SumsConfig{{1, 1}, {1, 1}, {2, 0}}, c.QueriedSums())
+		require.NoError(t, Init(c))
+	})
+
+	t.Run("fail: misconfigured metric fails at setup", func(t *testing.T) {
+		c, err := NewComposite(NewCov(3), &misconfigured{coskewness{window: 3}})
+		require.NoError(t, err)
+
+		err = Init(c)
+		assert.True(t, errors.Is(err, ErrorNotTracked))
+		assert.False(t, c.IsSetCore())
+	})
+}
+
+func TestCompositeString(t *testing.T) {
+	c, err := NewComposite(NewCov(3), NewCorr(3))
+	require.NoError(t, err)
+	expectedString := "joint.Composite_{metrics:[joint.Cov_{window:3} joint.Corr_{window:3}]}"
+	assert.Equal(t, expectedString, c.String())
+}
+
+func TestCompositeSharesCore(t *testing.T) {
+	xs := [][]float64{{1, 2}, {3, -1}, {4, 4}, {-2, 0.5}, {0, 3}, {5, 1}}
+
+	for _, window := range []int{0, 3} {
+		t.Run(fmt.Sprintf("pass: window %d matches separate metrics", window), func(t *testing.T) {
+			corr, cosk := NewCorr(window), &coskewness{window: window}
+			c, err := NewComposite(corr, cosk)
+			require.NoError(t, err)
+			err = Init(c)
+			require.NoError(t, err)
+			assert.Same(t, c.core, corr.core)
+			assert.Same(t, c.core, cosk.core)
+
+			separateCorr, separateCosk := NewCorr(window), &coskewness{window: window}
+			for _, metric := range []Metric{separateCorr, separateCosk} {
+				err := Init(metric)
+				require.NoError(t, err)
+			}
+
+			for _, x := range xs {
+				err := c.Push(x...)
+				require.NoError(t, err)
+				for _, metric := range []Metric{separateCorr, separateCosk} {
+					err := metric.Push(x...)
+					require.NoError(t, err)
+				}
+			}
+
+			values, err := c.Values()
+			require.NoError(t, err)
+			for _, metric := range []Metric{separateCorr, separateCosk} {
+				expected, err := metric.Value()
+				require.NoError(t, err)
+				testutil.Approx(t, expected, values[metric.String()])
+			}
+		})
+	}
+
+	t.Run("fail: null Core is an error", func(t *testing.T) {
+		c, err := NewComposite(NewCov(3))
+		require.NoError(t, err)
+		err = c.Push(1, 2)
+		assert.ErrorIs(t, err, ErrorCoreNotSet)
+		_, err = c.Values()
+		assert.ErrorIs(t, err, ErrorCoreNotSet)
+	})
+}
+
+func TestCompositeClear(t *testing.T) {
+	c, err := NewComposite(NewCov(3), NewCorr(3))
+	require.NoError(t, err)
+	err = Init(c)
+	require.NoError(t, err)
+
+	err = c.Push(1, 2)
+	require.NoError(t, err)
+	c.Clear()
+	assert.Equal(t, 0, c.core.count)
+}
@@ -2,6 +2,8 @@ package joint
 
 import (
 	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream"
 )
 
 // SumsConfig is an alias for a slice of Tuples; this configures
@@ -14,20 +16,94 @@ func (s1 *SumsConfig) add(s2 SumsConfig) {
 	}
 }
 
+// SumsBuilder builds a SumsConfig over a fixed number of variables, validating
+// each Tuple as it is added and tracking repeated Tuples once; for example,
+// NewSumsBuilder(3).Add(2, 0, 0).Add(1, 1, 0).Build(). The first invalid Tuple
+// added is reported by Build.
+type SumsBuilder struct {
+	vars int
+	sums SumsConfig
+	seen map[uint64]bool
+	err  error
+}
+
+// NewSumsBuilder instantiates a SumsBuilder for Tuples over vars variables.
+func NewSumsBuilder(vars int) *SumsBuilder {
+	b := &SumsBuilder{vars: vars, sums: SumsConfig{}, seen: map[uint64]bool{}}
+	if vars < 2 {
+		b.err = errors.Errorf("cannot build sums over %d < 2 vars", vars)
+	}
+	return b
+}
+
+// Add adds the Tuple of the given exponents, one per variable.
+func (b *SumsBuilder) Add(exponents ...int) *SumsBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	tuple := Tuple(append([]int{}, exponents...))
+	if len(tuple) != b.vars {
+		b.err = errors.Errorf("Tuple %v has length %d but vars = %d", tuple, len(tuple), b.vars)
+		return b
+	}
+	for _, k := range tuple {
+		if k < 0 {
+			b.err = errors.Errorf("Tuple %v has a negative exponent of %d", tuple, k)
+			return b
+		}
+	}
+	if tuple.abs() == 0 {
+		b.err = errors.Errorf("Tuple %v has no positive exponent", tuple)
+		return b
+	}
+
+	if !b.seen[tuple.hash()] {
+		b.seen[tuple.hash()] = true
+		b.sums = append(b.sums, tuple)
+	}
+	return b
+}
+
+// Build returns the SumsConfig of the Tuples added, in the order they were first
+// added, or the error for the first invalid Tuple added.
+func (b *SumsBuilder) Build() (SumsConfig, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.sums, nil
+}
+
+// PairOrders returns a SumsConfig over two variables, with a Tuple for each pair of
+// exponents, e.g. PairOrders([2]int{1, 1}, [2]int{2, 0}, [2]int{0, 2}) for the sums
+// needed by Corr; see SumsBuilder for the validation done.
+func PairOrders(pairs ...[2]int) (SumsConfig, error) {
+	b := NewSumsBuilder(2)
+	for _, pair := range pairs {
+		b.Add(pair[0], pair[1])
+	}
+	return b.Build()
+}
+
 // CoreConfig is the struct containing configuration options for
 // instantiating a Core object.
 type CoreConfig struct {
 	Sums   SumsConfig // sums tracked must be positive, and must track > 1 variables
-	Window *int       // must be 0 if decay is set, must be nonnegative in general
+	Window *int       // must be nonnegative
 	Vars   *int       // must be inferrable from Sums if not set; otherwise must be > 1
-	Decay  *float64   // optional, must lie in the interval (0, 1)
+	// optional, must lie in the interval (0, 1); with a window, the values in the
+	// window are weighted in proportion to (1-decay)^age, and their weights sum to 1
+	Decay *float64
+	// optional, defaults to stream.NonFiniteError; configures how non-finite values pushed are handled
+	NonFinite *stream.NonFiniteMode
 }
 
 var defaultConfig = &CoreConfig{
-	Sums:   SumsConfig{},
-	Window: nil,
-	Vars:   nil,
-	Decay:  nil,
+	Sums:      SumsConfig{},
+	Window:    nil,
+	Vars:      nil,
+	Decay:     nil,
+	NonFinite: nil,
 }
 
 // MergeConfigs merges CoreConfig objects.
@@ -39,9 +115,10 @@ func MergeConfigs(configs ...*CoreConfig) (*CoreConfig, error) {
 		return configs[0], nil
 	default:
 		var (
-			window *int
-			vars   *int
-			decay  *float64
+			window    *int
+			vars      *int
+			decay     *float64
+			nonFinite *stream.NonFiniteMode
 		)
 		mergedConfig := &CoreConfig{
 			Sums: SumsConfig{},
@@ -75,12 +152,21 @@ func MergeConfigs(configs ...*CoreConfig) (*CoreConfig, error) {
 					return nil, errors.New("configs have differing decays")
 				}
 			}
+
+			if config.NonFinite != nil {
+				if nonFinite == nil {
+					nonFinite = config.NonFinite
+				} else if *nonFinite != *config.NonFinite {
+					return nil, errors.New("configs have differing non-finite modes")
+				}
+			}
 		}
 
 		mergedConfig.Sums = simplifySums(mergedConfig.Sums)
 		mergedConfig.Window = window
 		mergedConfig.Vars = vars
 		mergedConfig.Decay = decay
+		mergedConfig.NonFinite = nonFinite
 		return mergedConfig, nil
 	}
 }
@@ -139,10 +225,16 @@ func validateConfig(config *CoreConfig) error {
 	}
 
 	if config.Decay != nil {
-		if *config.Decay <= 0 || *config.Decay >= 1 {
+		if !(*config.Decay > 0 && *config.Decay < 1) {
 			return errors.Errorf("config has a decay of %f, which is not in (0, 1)", *config.Decay)
-		} else if *config.Window > 0 {
-			return errors.New("config cannot have Decay set with a nonzero window")
+		}
+	}
+
+	if config.NonFinite != nil {
+		switch *config.NonFinite {
+		case stream.NonFiniteError, stream.NonFiniteSkip, stream.NonFinitePropagate:
+		default:
+			return errors.Errorf("config has an unknown non-finite mode of %d", *config.NonFinite)
 		}
 	}
 
@@ -213,5 +305,9 @@ func setConfigDefaults(config *CoreConfig) *CoreConfig {
 		config.Decay = defaultConfig.Decay
 	}
 
+	if config.NonFinite == nil {
+		config.NonFinite = defaultConfig.NonFinite
+	}
+
 	return config
 }
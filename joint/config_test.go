@@ -2,6 +2,7 @@ package joint
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -36,16 +37,26 @@ func TestValidateConfig(t *testing.T) {
 		}
 		err = validateConfig(config)
 		assert.EqualError(t, err, fmt.Sprintf("config has a decay of %f, which is not in (0, 1)", 1.))
+
+		for _, decay := range []float64{-0.2, 1.5, math.NaN()} {
+			config = &CoreConfig{
+				Window: stream.IntPtr(3),
+				Decay:  stream.FloatPtr(decay),
+				Vars:   stream.IntPtr(2),
+			}
+			err = validateConfig(config)
+			assert.EqualError(t, err, fmt.Sprintf("config has a decay of %f, which is not in (0, 1)", decay))
+		}
 	})
 
-	t.Run("fail: config with a set decay and nonzero window is invalid", func(t *testing.T) {
+	t.Run("pass: config with a set decay and nonzero window is valid", func(t *testing.T) {
 		config := &CoreConfig{
 			Window: stream.IntPtr(3),
 			Decay:  stream.FloatPtr(0.3),
 			Vars:   stream.IntPtr(2),
 		}
 		err := validateConfig(config)
-		assert.EqualError(t, err, "config cannot have Decay set with a nonzero window")
+		assert.NoError(t, err)
 	})
 
 	t.Run("fail: config with less than 2 vars is invalid", func(t *testing.T) {
@@ -159,6 +170,16 @@ func TestValidateConfig(t *testing.T) {
 		err = validateConfig(config)
 		assert.NoError(t, err)
 	})
+
+	t.Run("fail: config with an unknown non-finite mode is invalid", func(t *testing.T) {
+		config := &CoreConfig{
+			Window:    stream.IntPtr(3),
+			Vars:      stream.IntPtr(2),
+			NonFinite: stream.NonFiniteModePtr(3),
+		}
+		err := validateConfig(config)
+		assert.EqualError(t, err, "config has an unknown non-finite mode of 3")
+	})
 }
 
 func TestSetConfigDefaults(t *testing.T) {
@@ -191,6 +212,54 @@ func TestSetConfigDefaults(t *testing.T) {
 	})
 }
 
+func TestSumsBuilder(t *testing.T) {
+	t.Run("pass: matches the sums used by metrics", func(t *testing.T) {
+		for _, metric := range []Metric{NewCorr(3), NewRSquared(3), NewLinReg(3), NewCov(3)} {
+			queried := metric.(SumsQuerier).QueriedSums()
+			pairs := make([][2]int, len(queried))
+			for i, tuple := range queried {
+				pairs[i] = [2]int{tuple[0], tuple[1]}
+			}
+
+			sums, err := PairOrders(pairs...)
+			require.NoError(t, err)
+			assert.Equal(t, metric.Config().Sums, sums, metric.String())
+		}
+
+		sums, err := NewSumsBuilder(3).Add(2, 0, 0).Add(1, 1, 0).Add(0, 2, 0).Build()
+		require.NoError(t, err)
+		assert.Equal(t, SumsConfig{{2, 0, 0}, {1, 1, 0}, {0, 2, 0}}, sums)
+	})
+
+	t.Run("pass: repeated Tuples are tracked once", func(t *testing.T) {
+		sums, err := PairOrders([2]int{1, 1}, [2]int{2, 0}, [2]int{1, 1})
+		require.NoError(t, err)
+		assert.Equal(t, SumsConfig{{1, 1}, {2, 0}}, sums)
+	})
+
+	t.Run("pass: built sums make a valid Core", func(t *testing.T) {
+		sums, err := NewSumsBuilder(3).Add(2, 1, 1).Add(0, 0, 2).Build()
+		require.NoError(t, err)
+		_, err = NewCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(0)})
+		assert.NoError(t, err)
+	})
+
+	t.Run("fail: invalid Tuples are reported by Build", func(t *testing.T) {
+		_, err := NewSumsBuilder(1).Add(1).Build()
+		assert.EqualError(t, err, "cannot build sums over 1 < 2 vars")
+
+		_, err = NewSumsBuilder(2).Add(1, 1).Add(1, 1, 0).Build()
+		assert.EqualError(t, err, "Tuple [1 1 0] has length 3 but vars = 2")
+
+		_, err = PairOrders([2]int{2, -1})
+		assert.EqualError(t, err, "Tuple [2 -1] has a negative exponent of -1")
+
+		// the first error is kept
+		_, err = PairOrders([2]int{0, 0}, [2]int{2, -1})
+		assert.EqualError(t, err, "Tuple [0 0] has no positive exponent")
+	})
+}
+
 func TestMergeConfigs(t *testing.T) {
 	t.Run("fail: no configs passed is invalid", func(t *testing.T) {
 		_, err := MergeConfigs()
@@ -291,4 +360,21 @@ func TestMergeConfigs(t *testing.T) {
 		_, err := MergeConfigs(config1, config2)
 		assert.EqualError(t, err, "configs have differing decays")
 	})
+
+	t.Run("fail: multiple configs passed fails if non-finite modes are not compatible", func(t *testing.T) {
+		config1 := &CoreConfig{
+			NonFinite: stream.NonFiniteModePtr(stream.NonFiniteSkip),
+		}
+		config2 := &CoreConfig{
+			NonFinite: stream.NonFiniteModePtr(stream.NonFinitePropagate),
+		}
+
+		_, err := MergeConfigs(config1, config2)
+		assert.EqualError(t, err, "configs have differing non-finite modes")
+
+		config3 := &CoreConfig{}
+		mergedConfig, err := MergeConfigs(config1, config3)
+		require.NoError(t, err)
+		assert.Equal(t, stream.NonFiniteSkip, *mergedConfig.NonFinite)
+	})
 }
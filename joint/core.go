@@ -1,30 +1,101 @@
 package joint
 
 import (
+	"fmt"
 	"math"
 	"sync"
 
-	"github.com/Workiva/go-datastructures/queue"
 	"github.com/pkg/errors"
 
 	"github.com/K4Mobility/stream"
 	mathutil "github.com/K4Mobility/stream/util/math"
+	"github.com/K4Mobility/stream/util/ring"
+)
+
+// Sentinel errors returned by the Core and the metrics wrapping it (possibly wrapped),
+// which can be matched with errors.Is.
+var (
+	ErrorNoValuesSeen       = errors.New("no values seen yet")
+	ErrorNotTracked         = errors.New("not a tracked power sum")
+	ErrorNotTrackedVariable = errors.New("not a tracked variable")
+	ErrorCoreNotSet         = errors.New("Core is not set")
 )
 
 // Core is a struct that stores fundamental information for multivariate moments of a stream.
 type Core struct {
-	mux     sync.RWMutex
-	means   []float64
-	tuples  []Tuple
-	sums    map[uint64]float64
-	newSums map[uint64]float64
+	mux    sync.RWMutex
+	means  []float64
+	tuples []Tuple
+	// updates[i] holds the precomputed updates for the Tuples <= tuples[i]
+	updates [][]sumUpdate
+	// sums are stored densely; index maps the hash of each Tuple
+	// tracked to the position of its sum in sums and newSums
+	index   map[uint64]int
+	sums    []float64
+	newSums []float64
 	count   int
 	window  int
 	decay   *float64
-	queue   *queue.RingBuffer
+	queue   ring.Queue[[]float64]
+	// How non-finite values pushed are handled
+	nonFinite stream.NonFiniteMode
+}
+
+// sumUpdate describes how the sum for a Tuple a is updated
+// on each push, as a series of terms over the Tuples b <= a.
+type sumUpdate struct {
+	idx   int
+	terms []sumTerm
 }
 
-// Init sets a CoreWrapper up with a core for consuming.
+// sumTerm is a single term b of a sumUpdate, with the parts that
+// depend only on the Tuples (and not on the values pushed) computed.
+type sumTerm struct {
+	b    Tuple
+	abs  int
+	self bool // whether b == a
+	// index of the sum for a - b, and the multinomial
+	// coefficient of a and b times the sign of abs
+	diff  int
+	coeff float64
+}
+
+// newSumUpdates precomputes the sumUpdates for each Tuple <= tuple,
+// in the increasing order of iter.
+func newSumUpdates(tuple Tuple, index map[uint64]int) ([]sumUpdate, error) {
+	var updates []sumUpdate
+	err := iter(tuple, false, func(xs ...int) error {
+		a := Tuple(append([]int{}, xs...))
+		update := sumUpdate{idx: index[a.hash()]}
+		err := iter(a, false, func(xs ...int) error {
+			b := Tuple(append([]int{}, xs...))
+			term := sumTerm{b: b, abs: b.abs(), self: b.eq(a)}
+			if term.abs != 0 && !term.self {
+				multinomial, err := multinom(a, b)
+				if err != nil {
+					return err
+				}
+
+				diff, err := sub(a, b)
+				if err != nil {
+					return err
+				}
+
+				term.diff = index[diff.hash()]
+				term.coeff = multinomial * float64(mathutil.Sign(term.abs))
+			}
+			update.terms = append(update.terms, term)
+			return nil
+		})
+		updates = append(updates, update)
+		return err
+	})
+	return updates, err
+}
+
+// Init sets a CoreWrapper up with a core for consuming. If the wrapper is a
+// SumsQuerier, this also fails if any sum it queries is not tracked by the Core,
+// rather than leaving the error to be returned by the first call to Value.
 func Init(wrapper CoreWrapper) error {
 	config := wrapper.Config()
 	core, err := NewCore(config)
@@ -32,6 +103,14 @@ func Init(wrapper CoreWrapper) error {
 		return errors.Wrap(err, "error creating Core")
 	}
 
+	if querier, ok := wrapper.(SumsQuerier); ok {
+		for _, tuple := range querier.QueriedSums() {
+			if _, ok := core.index[tuple.hash()]; !ok {
+				return fmt.Errorf("error validating queried sums: %v is %w", tuple, ErrorNotTracked)
+			}
+		}
+	}
+
 	wrapper.SetCore(core)
 	return nil
 }
@@ -55,23 +134,34 @@ func NewCore(config *CoreConfig) (*Core, error) {
 	c := &Core{}
 	c.window = *config.Window
 	c.decay = config.Decay
+	if config.NonFinite != nil {
+		c.nonFinite = *config.NonFinite
+	}
 
-	c.sums = map[uint64]float64{}
-	c.newSums = map[uint64]float64{}
+	c.index = map[uint64]int{}
 	for _, tuple := range config.Sums {
 		_ = iter(tuple, false, func(xs ...int) error {
-			c.sums[Tuple(xs).hash()] = 0
-			return nil
-		})
-		_ = iter(tuple, false, func(xs ...int) error {
-			c.newSums[Tuple(xs).hash()] = 0
+			hash := Tuple(xs).hash()
+			if _, ok := c.index[hash]; !ok {
+				c.index[hash] = len(c.index)
+			}
 			return nil
 		})
 	}
+	c.sums = make([]float64, len(c.index))
+	c.newSums = make([]float64, len(c.index))
+
+	c.updates = make([][]sumUpdate, len(config.Sums))
+	for i, tuple := range config.Sums {
+		c.updates[i], err = newSumUpdates(tuple, c.index)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error precomputing updates for tuple %v", tuple)
+		}
+	}
 
 	c.tuples = config.Sums
 	c.means = make([]float64, *config.Vars)
-	c.queue = queue.NewRingBuffer(uint64(c.window))
+	c.queue = ring.New[[]float64](uint64(c.window))
 
 	return c, nil
 }
@@ -95,6 +185,14 @@ func (c *Core) UnsafePush(xs ...float64) error {
 		)
 	}
 
+	// check the values before any state is mutated
+	ok, err := c.nonFinite.Admit(xs...)
+	if err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
 	if c.window != 0 {
 		if c.queue.Len() == uint64(c.window) {
 			tail, err := c.queue.Get()
@@ -102,7 +200,11 @@ func (c *Core) UnsafePush(xs ...float64) error {
 				return errors.Wrap(err, "error popping item from queue")
 			}
 
-			err = c.remove(tail.([]float64)...)
+			if c.decay == nil {
+				err = c.remove(tail...)
+			} else {
+				err = c.removeDecay(tail...)
+			}
 			if err != nil {
 				return errors.Wrapf(err, "error removing %v from sums", xs)
 			}
@@ -114,7 +216,6 @@ func (c *Core) UnsafePush(xs ...float64) error {
 		}
 	}
 
-	var err error
 	if c.decay == nil {
 		err = c.add(xs...)
 	} else {
@@ -143,52 +244,32 @@ func (c *Core) add(xs ...float64) error {
 		c.means[i] += delta[i] / count
 	}
 
-	for _, tuple := range c.tuples {
-		err := iter(tuple, true, func(xs ...int) error {
-			a := Tuple(xs)
-			hash := a.hash()
-			c.newSums[hash] = 0
-			return iter(a, false, func(xs ...int) error {
-				b := Tuple(xs)
-
-				deltaPow, err := pow(delta, b)
+	for i, updates := range c.updates {
+		for j := len(updates) - 1; j >= 0; j-- {
+			idx := updates[j].idx
+			c.newSums[idx] = 0
+			for _, term := range updates[j].terms {
+				deltaPow, err := pow(delta, term.b)
 				if err != nil {
-					return err
+					return errors.Wrapf(err, "error adding %v to sums for tuple %v", xs, c.tuples[i])
 				}
 
-				abs := b.abs()
-				if abs == 0 {
-					c.newSums[hash] += c.sums[hash]
-				} else if b.eq(a) {
-					coeff := (count - 1) / math.Pow(count, float64(abs)) *
-						(math.Pow(count-1, float64(abs-1)) + float64(mathutil.Sign(abs)))
-					c.newSums[hash] += coeff * deltaPow
+				abs := float64(term.abs)
+				if term.abs == 0 {
+					c.newSums[idx] += c.sums[idx]
+				} else if term.self {
+					coeff := (count - 1) / math.Pow(count, abs) *
+						(math.Pow(count-1, abs-1) + float64(mathutil.Sign(term.abs)))
+					c.newSums[idx] += coeff * deltaPow
 				} else {
-					multinomial, err := multinom(a, b)
-					if err != nil {
-						return err
-					}
-
-					diff, err := sub(a, b)
-					if err != nil {
-						return err
-					}
-
-					c.newSums[hash] += float64(multinomial*mathutil.Sign(abs)) /
-						math.Pow(count, float64(abs)) * deltaPow * c.sums[diff.hash()]
+					c.newSums[idx] += term.coeff /
+						math.Pow(count, abs) * deltaPow * c.sums[term.diff]
 				}
-
-				return nil
-			})
-		})
-		if err != nil {
-			return errors.Wrapf(err, "error adding %v to sums for tuple %v", xs, tuple)
+			}
 		}
 	}
 
-	for hash, sum := range c.newSums {
-		c.sums[hash] = sum
-	}
+	copy(c.sums, c.newSums)
 
 	return nil
 }
@@ -205,6 +286,8 @@ func (c *Core) addDecay(xs ...float64) error {
 	var decay float64
 	if c.count == 1 {
 		decay = 1
+	} else if c.window != 0 {
+		decay = windowDecay(*c.decay, c.count)
 	} else {
 		decay = *c.decay
 	}
@@ -215,52 +298,32 @@ func (c *Core) addDecay(xs ...float64) error {
 		c.means[i] += decay * delta[i]
 	}
 
-	for _, tuple := range c.tuples {
-		err := iter(tuple, true, func(xs ...int) error {
-			a := Tuple(xs)
-			hash := a.hash()
-			c.newSums[hash] = 0
-			return iter(a, false, func(xs ...int) error {
-				b := Tuple(xs)
-
-				deltaPow, err := pow(delta, b)
+	for i, updates := range c.updates {
+		for j := len(updates) - 1; j >= 0; j-- {
+			idx := updates[j].idx
+			c.newSums[idx] = 0
+			for _, term := range updates[j].terms {
+				deltaPow, err := pow(delta, term.b)
 				if err != nil {
-					return err
+					return errors.Wrapf(err, "error adding %v to sums for tuple %v", xs, c.tuples[i])
 				}
 
-				abs := b.abs()
-				if abs == 0 {
-					c.newSums[hash] += (1 - decay) * c.sums[hash]
-				} else if b.eq(a) {
-					coeff := (1-decay)*math.Pow(-decay, float64(abs)) + decay*math.Pow(1-decay, float64(abs))
-					c.newSums[hash] += coeff * deltaPow
+				abs := float64(term.abs)
+				if term.abs == 0 {
+					c.newSums[idx] += (1 - decay) * c.sums[idx]
+				} else if term.self {
+					coeff := (1-decay)*math.Pow(-decay, abs) + decay*math.Pow(1-decay, abs)
+					c.newSums[idx] += coeff * deltaPow
 				} else {
-					multinomial, err := multinom(a, b)
-					if err != nil {
-						return err
-					}
-
-					diff, err := sub(a, b)
-					if err != nil {
-						return err
-					}
-
-					c.newSums[hash] += float64(multinomial*mathutil.Sign(abs)) *
-						math.Pow(decay, float64(abs)) * deltaPow *
-						(1 - decay) * c.sums[diff.hash()]
+					c.newSums[idx] += term.coeff *
+						math.Pow(decay, abs) * deltaPow *
+						(1 - decay) * c.sums[term.diff]
 				}
-
-				return nil
-			})
-		})
-		if err != nil {
-			return errors.Wrapf(err, "error adding %v to sums for tuple %v", xs, tuple)
+			}
 		}
 	}
 
-	for hash, sum := range c.newSums {
-		c.sums[hash] = sum
-	}
+	copy(c.sums, c.newSums)
 
 	return nil
 }
@@ -279,75 +342,340 @@ func (c *Core) remove(xs ...float64) error {
 			delta[i] = x - c.means[i]
 		}
 
-		for _, tuple := range c.tuples {
-			err := iter(tuple, false, func(xs ...int) error {
-				a := Tuple(xs)
-				hash := a.hash()
-				c.newSums[hash] = 0
-				return iter(a, false, func(xs ...int) error {
-					b := Tuple(xs)
-
-					deltaPow, err := pow(delta, b)
+		// the sums are only updated once all of the new sums are computed,
+		// so Tuples shared by several tracked Tuples are removed once
+		for i, updates := range c.updates {
+			for _, update := range updates {
+				idx := update.idx
+				c.newSums[idx] = 0
+				for _, term := range update.terms {
+					deltaPow, err := pow(delta, term.b)
 					if err != nil {
-						return err
+						return errors.Wrapf(err, "error removing %v from sums for tuple %v", xs, c.tuples[i])
 					}
 
-					abs := b.abs()
-					if abs == 0 {
-						c.newSums[hash] += c.sums[hash]
-					} else if b.eq(a) {
-						coeff := count / math.Pow(count+1, float64(abs)) *
-							(math.Pow(count, float64(abs-1)) + float64(mathutil.Sign(abs)))
-						c.newSums[hash] -= coeff * deltaPow
+					abs := float64(term.abs)
+					if term.abs == 0 {
+						c.newSums[idx] += c.sums[idx]
+					} else if term.self {
+						coeff := count / math.Pow(count+1, abs) *
+							(math.Pow(count, abs-1) + float64(mathutil.Sign(term.abs)))
+						c.newSums[idx] -= coeff * deltaPow
 					} else {
-						multinomial, err := multinom(a, b)
-						if err != nil {
-							return err
-						}
-
-						diff, err := sub(a, b)
-						if err != nil {
-							return err
-						}
-
-						c.newSums[hash] -= float64(multinomial*mathutil.Sign(abs)) /
-							math.Pow(count+1, float64(abs)) * deltaPow * c.newSums[diff.hash()]
+						// unlike in add, the lower-order sums are those with the
+						// value already removed, i.e. the ones just computed
+						c.newSums[idx] -= term.coeff /
+							math.Pow(count+1, abs) * deltaPow * c.newSums[term.diff]
 					}
-
-					return nil
-				})
-			})
-			if err != nil {
-				return errors.Wrapf(err, "error removing %v from sums for tuple %v", xs, tuple)
+				}
 			}
+		}
+
+		copy(c.sums, c.newSums)
+	} else {
+		for i := range c.means {
+			c.means[i] = 0
+		}
+		for i := range c.sums {
+			c.sums[i] = 0
+			c.newSums[i] = 0
+		}
+	}
+
+	return nil
+}
 
-			for hash, sum := range c.newSums {
-				c.sums[hash] = sum
+// windowDecay returns the decay factor to use when pushing the nth value in the
+// window of a Core with decay, so that the values in the window are weighted in
+// proportion to (1-decay)^age, with weights that sum to 1. The denominator
+// 1-(1-decay)^n is computed via Expm1 and Log1p, since it would otherwise round
+// to 0 for tiny decays and turn the sums into NaNs.
+func windowDecay(decay float64, n int) float64 {
+	return decay / -math.Expm1(float64(n)*math.Log1p(-decay))
+}
+
+// removeDecay undoes the contribution of the oldest value in the window of a
+// Core with decay, whose weight is determined by its age. It inverts the update
+// formulas for combining weighted sums from the paper cited in addDecay() to
+// split the value off, and then renormalizes the sums of the remaining values
+// so that their weights sum to 1.
+func (c *Core) removeDecay(xs ...float64) error {
+	n := c.count
+	c.count--
+	if c.count > 0 {
+		w := windowDecay(*c.decay, n) * math.Pow(1-*c.decay, float64(n-1))
+
+		delta := make([]float64, len(c.means))
+		for i, x := range xs {
+			// equivalent to (mean - w*x) / (1 - w), but leaves the mean
+			// exactly unchanged if x is equal to it
+			c.means[i] += w * (c.means[i] - x) / (1 - w)
+			delta[i] = x - c.means[i]
+		}
+
+		// the sums are only updated once all of the new sums are computed,
+		// so Tuples shared by several tracked Tuples are computed identically
+		for i, updates := range c.updates {
+			for _, update := range updates {
+				idx := update.idx
+				c.newSums[idx] = 0
+				for _, term := range update.terms {
+					deltaPow, err := pow(delta, term.b)
+					if err != nil {
+						return errors.Wrapf(err, "error removing %v from sums for tuple %v", xs, c.tuples[i])
+					}
+
+					abs := float64(term.abs)
+					if term.abs == 0 {
+						c.newSums[idx] += c.sums[idx]
+					} else if term.self {
+						coeff := (1-w)*math.Pow(-w, abs) + w*math.Pow(1-w, abs)
+						c.newSums[idx] -= coeff * deltaPow
+					} else {
+						c.newSums[idx] -= term.coeff *
+							math.Pow(w, abs) * deltaPow * c.newSums[term.diff]
+					}
+				}
 			}
 		}
+
+		for i := range c.sums {
+			c.sums[i] = c.newSums[i] / (1 - w)
+			c.newSums[i] = c.sums[i]
+		}
 	} else {
 		for i := range c.means {
 			c.means[i] = 0
 		}
-		for hash := range c.sums {
-			c.sums[hash] = 0
+		for i := range c.sums {
+			c.sums[i] = 0
+			c.newSums[i] = 0
 		}
-		for hash := range c.newSums {
-			c.newSums[hash] = 0
+	}
+
+	return nil
+}
+
+// Merge combines the stats of another Core into this one, as if this Core had
+// also consumed all of the tuples consumed by the other Core, using the parallel
+// formulas from the paper cited by addDecay. Both Cores must track the same
+// variables and sums, and neither may have a window or decay set, since the
+// tuples within a window cannot be recovered from the sums. The other Core is
+// left untouched.
+func (c *Core) Merge(other *Core) error {
+	if c == other {
+		return errors.New("cannot merge a Core with itself")
+	}
+
+	// the other Core is copied before this one is locked, rather than holding both
+	// locks, so that merges in opposite directions cannot deadlock
+	other = other.snapshot()
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.window != 0 || other.window != 0 {
+		return errors.New("cannot merge Cores with a window")
+	}
+	if c.decay != nil || other.decay != nil {
+		return errors.New("cannot merge Cores with decay")
+	}
+	if len(c.means) != len(other.means) {
+		return errors.Errorf(
+			"cannot merge Cores tracking %d and %d variables",
+			len(c.means),
+			len(other.means),
+		)
+	}
+	if len(c.index) != len(other.index) {
+		return errors.New("cannot merge Cores tracking different sums")
+	}
+	for hash := range c.index {
+		if _, ok := other.index[hash]; !ok {
+			return errors.New("cannot merge Cores tracking different sums")
 		}
 	}
 
+	return c.merge(other)
+}
+
+// snapshot returns a copy of the stats of the Core under the read lock, for
+// comparing or merging it with another Core without holding both locks; the
+// copy has no window contents and is not meant to be pushed to.
+func (c *Core) snapshot() *Core {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	snapshot := &Core{
+		means:  make([]float64, len(c.means)),
+		tuples: c.tuples,
+		index:  c.index,
+		sums:   make([]float64, len(c.sums)),
+		count:  c.count,
+		window: c.window,
+		decay:  c.decay,
+	}
+	copy(snapshot.means, c.means)
+	copy(snapshot.sums, c.sums)
+	return snapshot
+}
+
+// merge combines the stats of another Core into this one, but does not lock;
+// the Cores must track the same sums. For each Tuple a, the sums of each side
+// are shifted to the combined mean and added, i.e. S_a is the sum over b <= a
+// of C(a, b) * (S_{A, a-b} * shiftA^b + S_{B, a-b} * shiftB^b), where C(a, b) is
+// the multinomial coefficient, and the sums for the zero Tuple and the unit
+// Tuples are the count and zero, respectively.
+func (c *Core) merge(other *Core) error {
+	if other.count == 0 {
+		return nil
+	}
+
+	countA := float64(c.count)
+	countB := float64(other.count)
+	count := countA + countB
+
+	delta := make([]float64, len(c.means))
+	shiftA := make([]float64, len(c.means))
+	shiftB := make([]float64, len(c.means))
+	for i := range c.means {
+		delta[i] = other.means[i] - c.means[i]
+		shiftA[i] = -countB * delta[i] / count
+		shiftB[i] = countA * delta[i] / count
+	}
+
+	// align the other Core's sums with this Core's index, and fill in the count
+	sumsA := make([]float64, len(c.sums))
+	sumsB := make([]float64, len(c.sums))
+	copy(sumsA, c.sums)
+	for hash, idx := range c.index {
+		sumsB[idx] = other.sums[other.index[hash]]
+	}
+	if idx, ok := c.index[Tuple(make([]int, len(c.means))).hash()]; ok {
+		sumsA[idx] = countA
+		sumsB[idx] = countB
+	}
+
+	merged := make([]float64, len(c.sums))
+	for _, tuple := range c.tuples {
+		err := iter(tuple, false, func(xs ...int) error {
+			a := Tuple(append([]int{}, xs...))
+			// the sums for the zero and unit Tuples are always zero
+			if a.abs() <= 1 {
+				return nil
+			}
+
+			sum := 0.
+			err := iter(a, false, func(xs ...int) error {
+				b := Tuple(append([]int{}, xs...))
+				multinomial, err := multinom(a, b)
+				if err != nil {
+					return err
+				}
+
+				diff, err := sub(a, b)
+				if err != nil {
+					return err
+				}
+
+				powA, err := pow(shiftA, b)
+				if err != nil {
+					return err
+				}
+
+				powB, err := pow(shiftB, b)
+				if err != nil {
+					return err
+				}
+
+				idx := c.index[diff.hash()]
+				sum += multinomial * (sumsA[idx]*powA + sumsB[idx]*powB)
+				return nil
+			})
+			merged[c.index[a.hash()]] = sum
+			return err
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error merging sums for tuple %v", tuple)
+		}
+	}
+
+	copy(c.sums, merged)
+	c.count += other.count
+	for i := range c.means {
+		c.means[i] += countB * delta[i] / count
+	}
 	return nil
 }
 
-// Count returns the number of values seen seen globally.
+// Clone returns an independent snapshot of the Core, including the tuples
+// currently in the window (if one is set), which can be read without blocking
+// the original.
+func (c *Core) Clone() *Core {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	clone := &Core{
+		means:     make([]float64, len(c.means)),
+		tuples:    make([]Tuple, len(c.tuples)),
+		updates:   c.updates,
+		index:     c.index,
+		sums:      make([]float64, len(c.sums)),
+		newSums:   make([]float64, len(c.newSums)),
+		count:     c.count,
+		window:    c.window,
+		queue:     ring.New[[]float64](uint64(c.window)),
+		nonFinite: c.nonFinite,
+	}
+	copy(clone.means, c.means)
+	copy(clone.tuples, c.tuples)
+	copy(clone.sums, c.sums)
+	copy(clone.newSums, c.newSums)
+
+	if c.decay != nil {
+		decay := *c.decay
+		clone.decay = &decay
+	}
+
+	// the clone's queue is as large as this one's, so it has room for every tuple
+	for _, xs := range c.queued() {
+		_ = clone.queue.Put(xs)
+	}
+
+	return clone
+}
+
+// Window returns a copy of the tuples currently in the window, from oldest
+// to newest; for a global Core, there are none.
+func (c *Core) Window() [][]float64 {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return c.queued()
+}
+
+// queued returns a copy of the tuples in the window's queue, from oldest to
+// newest; the Core must be locked, at least for reading.
+func (c *Core) queued() [][]float64 {
+	tuples := make([][]float64, 0, c.queue.Len())
+	c.queue.Do(func(xs []float64) {
+		ys := make([]float64, len(xs))
+		copy(ys, xs)
+		tuples = append(tuples, ys)
+	})
+	return tuples
+}
+
+// Count returns the number of values currently in the window, if one is set,
+// or else the number of values seen globally; this is the count used by the
+// sample corrections of the metrics backed by the Core.
 func (c *Core) Count() int {
 	c.mux.RLock()
 	defer c.mux.RUnlock()
 	return c.UnsafeCount()
 }
 
-// UnsafeCount returns the number of values seen seen globally,
+// UnsafeCount returns the number of values currently in the window (or seen globally),
 // but does not lock. This should only be used if the user
 // plans to make use of the [R]Lock()/[R]Unlock() Core methods.
 func (c *Core) UnsafeCount() int {
@@ -366,11 +694,11 @@ func (c *Core) Mean(i int) (float64, error) {
 // plans to make use of the [R]Lock()/[R]Unlock() Core methods.
 func (c *Core) UnsafeMean(i int) (float64, error) {
 	if c.count == 0 {
-		return 0, errors.New("no values seen yet")
+		return 0, ErrorNoValuesSeen
 	}
 
 	if i < 0 || i >= len(c.means) {
-		return 0, errors.Errorf("%d is not a tracked variable", i)
+		return 0, fmt.Errorf("%d is %w", i, ErrorNotTrackedVariable)
 	}
 
 	return c.means[i], nil
@@ -391,15 +719,55 @@ func (c *Core) Sum(xs ...int) (float64, error) {
 // plans to make use of the [R]Lock()/[R]Unlock() Core methods.
 func (c *Core) UnsafeSum(xs ...int) (float64, error) {
 	if c.count == 0 {
-		return 0, errors.New("no values seen yet")
+		return 0, ErrorNoValuesSeen
 	}
 
-	sum, ok := c.sums[Tuple(xs).hash()]
+	idx, ok := c.index[Tuple(xs).hash()]
 	if !ok {
-		return 0, errors.Errorf("%v is not a tracked power sum", xs)
+		return 0, fmt.Errorf("%v is %w", xs, ErrorNotTracked)
+	}
+
+	return c.sums[idx], nil
+}
+
+// ApproxEqual returns whether the Core has the same count as another Core, and
+// whether their means and joint centralized sums agree to the given number of
+// decimal places (see mathutil.ApproxEqual); this is useful for comparing Cores
+// after a serialization or merge round trip. Cores tracking different variables
+// or sums are never equal.
+func (c *Core) ApproxEqual(other *Core, digits int) bool {
+	if c == other {
+		return true
+	}
+
+	// the other Core is copied before this one is locked, since holding both read
+	// locks can deadlock with a comparison in the opposite direction and a waiting writer
+	other = other.snapshot()
+
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if c.count != other.count ||
+		len(c.means) != len(other.means) ||
+		len(c.index) != len(other.index) {
+		return false
+	}
+
+	for i := range c.means {
+		if !mathutil.ApproxEqual(c.means[i], other.means[i], digits) {
+			return false
+		}
 	}
 
-	return sum, nil
+	// the sums are compared by Tuple, since Cores tracking the same
+	// sums may have been configured with them in a different order
+	for hash, idx := range c.index {
+		otherIdx, ok := other.index[hash]
+		if !ok || !mathutil.ApproxEqual(c.sums[idx], other.sums[otherIdx], digits) {
+			return false
+		}
+	}
+	return true
 }
 
 // Clear clears all stats being tracked.
@@ -416,16 +784,14 @@ func (c *Core) UnsafeClear() {
 	for i := range c.means {
 		c.means[i] = 0
 	}
-	for hash := range c.sums {
-		c.sums[hash] = 0
-	}
-	for hash := range c.newSums {
-		c.newSums[hash] = 0
+	for i := range c.sums {
+		c.sums[i] = 0
+		c.newSums[i] = 0
 	}
 
 	c.count = 0
 	c.queue.Dispose()
-	c.queue = queue.NewRingBuffer(uint64(c.window))
+	c.queue = ring.New[[]float64](uint64(c.window))
 }
 
 // RLock locks the Core internals for reading.
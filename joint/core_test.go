@@ -1,8 +1,14 @@
 package joint
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -44,6 +50,25 @@ func (w *invalidWrapper) Config() *CoreConfig {
 	return &CoreConfig{Vars: stream.IntPtr(-1)}
 }
 
+// queryingWrapper is a mockWrapper that declares the sums it queries.
+type queryingWrapper struct {
+	mockWrapper
+	queried SumsConfig
+}
+
+func (w *queryingWrapper) QueriedSums() SumsConfig {
+	return w.queried
+}
+
+// byHash returns sums keyed by the hashes of the Tuples they are stored for.
+func byHash(c *Core, sums []float64) map[uint64]float64 {
+	m := map[uint64]float64{}
+	for hash, idx := range c.index {
+		m[hash] = sums[idx]
+	}
+	return m
+}
+
 func TestNewCore(t *testing.T) {
 	t.Run("fail: invalid config returns error", func(t *testing.T) {
 		_, err := NewCore(&CoreConfig{Vars: stream.IntPtr(-1)})
@@ -68,13 +93,13 @@ func TestNewCore(t *testing.T) {
 
 		for _, tuple := range config.Sums {
 			_ = iter(tuple, false, func(xs ...int) error {
-				assert.Equal(t, 0., core.sums[Tuple(xs).hash()])
+				assert.Equal(t, 0., core.sums[core.index[Tuple(xs).hash()]])
 				return nil
 			})
 			require.NoError(t, err)
 
 			_ = iter(tuple, false, func(xs ...int) error {
-				assert.Equal(t, 0., core.newSums[Tuple(xs).hash()])
+				assert.Equal(t, 0., core.newSums[core.index[Tuple(xs).hash()]])
 				return nil
 			})
 			require.NoError(t, err)
@@ -98,11 +123,11 @@ func TestNewCore(t *testing.T) {
 
 		for _, tuple := range config.Sums {
 			_ = iter(tuple, false, func(xs ...int) error {
-				assert.Equal(t, 0., core.sums[Tuple(xs).hash()])
+				assert.Equal(t, 0., core.sums[core.index[Tuple(xs).hash()]])
 				return nil
 			})
 			_ = iter(tuple, false, func(xs ...int) error {
-				assert.Equal(t, 0., core.newSums[Tuple(xs).hash()])
+				assert.Equal(t, 0., core.newSums[core.index[Tuple(xs).hash()]])
 				return nil
 			})
 		}
@@ -132,6 +157,28 @@ func TestInit(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, wrapper.core)
 	})
+
+	t.Run("pass: queried sums implied by the config are valid", func(t *testing.T) {
+		// tracking {2, 2} also tracks the lower order sums it is updated with
+		wrapper := &queryingWrapper{
+			mockWrapper: mockWrapper{window: stream.IntPtr(3)},
+			queried:     SumsConfig{{2, 2}, {1, 1}, {2, 0}},
+		}
+		err := Init(wrapper)
+		require.NoError(t, err)
+		assert.NotNil(t, wrapper.core)
+	})
+
+	t.Run("fail: queried sums missing from the config fail at setup", func(t *testing.T) {
+		wrapper := &queryingWrapper{
+			mockWrapper: mockWrapper{window: stream.IntPtr(3)},
+			queried:     SumsConfig{{1, 1}, {3, 0}},
+		}
+		err := Init(wrapper)
+		assert.True(t, errors.Is(err, ErrorNotTracked))
+		assert.EqualError(t, err, "error validating queried sums: [3 0] is not a tracked power sum")
+		assert.Nil(t, wrapper.core)
+	})
 }
 
 type CorePushSuite struct {
@@ -187,7 +234,7 @@ func (s *CorePushSuite) TestPushSuccess() {
 
 		s.Equal(len(expectedSums), len(s.wrapper.core.sums))
 		for hash, expectedSum := range expectedSums {
-			actualSum := s.wrapper.core.sums[hash]
+			actualSum := byHash(s.wrapper.core, s.wrapper.core.sums)[hash]
 			testutil.Approx(s.T(), expectedSum, actualSum)
 		}
 	})
@@ -209,7 +256,7 @@ func (s *CorePushSuite) TestPushSuccess() {
 
 		s.Equal(len(expectedSums), len(s.decayWrapper.core.sums))
 		for hash, expectedSum := range expectedSums {
-			actualSum := s.decayWrapper.core.sums[hash]
+			actualSum := byHash(s.decayWrapper.core, s.decayWrapper.core.sums)[hash]
 			testutil.Approx(s.T(), expectedSum, actualSum)
 		}
 	})
@@ -246,7 +293,7 @@ func (s *CorePushSuite) TestPushSuccessForWindow1() {
 
 	s.Equal(len(expectedSums), len(core.sums))
 	for hash, expectedSum := range expectedSums {
-		actualSum := core.sums[hash]
+		actualSum := byHash(core, core.sums)[hash]
 		testutil.Approx(s.T(), expectedSum, actualSum)
 	}
 }
@@ -308,8 +355,8 @@ func TestClear(t *testing.T) {
 		63: 0.,
 		64: 0.,
 	}
-	assert.Equal(t, expectedSums, wrapper.core.sums)
-	assert.Equal(t, expectedSums, wrapper.core.newSums)
+	assert.Equal(t, expectedSums, byHash(wrapper.core, wrapper.core.sums))
+	assert.Equal(t, expectedSums, byHash(wrapper.core, wrapper.core.newSums))
 
 	expectedMeans := []float64{0, 0}
 	assert.Equal(t, expectedMeans, wrapper.core.means)
@@ -463,9 +510,9 @@ func TestLock(t *testing.T) {
 		done <- true
 	}()
 
-	// Read the sum; note that Sum also uses the RLock/RUnlock of the lock internally,
-	// and this should not be blocked by the earlier RLock call
-	sum, err := wrapper.core.Sum(2, 0)
+	// Read the sum while holding the read lock; UnsafeSum must be used here, since
+	// acquiring the read lock again via Sum can deadlock once the writer is waiting
+	sum, err := wrapper.core.UnsafeSum(2, 0)
 	require.NoError(t, err)
 	testutil.Approx(t, 14., sum)
 
@@ -478,3 +525,787 @@ func TestLock(t *testing.T) {
 	require.NoError(t, err)
 	testutil.Approx(t, 26./3., sum)
 }
+
+func TestMerge(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	xs := make([][]float64, 100)
+	for i := range xs {
+		x := 10*r.Float64() - 5
+		xs[i] = []float64{x, 2*x + r.NormFloat64() + 3}
+	}
+
+	t.Run("pass: merged shards match a single core", func(t *testing.T) {
+		sums := SumsConfig{{1, 1}, {2, 0}, {0, 2}, {2, 2}, {3, 1}}
+		single, err := NewCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+		for _, x := range xs {
+			require.NoError(t, single.Push(x...))
+		}
+
+		// shards of uneven sizes, including an empty one
+		bounds := []int{0, 7, 7, 40, 100}
+		shards := make([]*Core, len(bounds)-1)
+		for i := range shards {
+			shards[i], err = NewCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(0)})
+			require.NoError(t, err)
+			for _, x := range xs[bounds[i]:bounds[i+1]] {
+				require.NoError(t, shards[i].Push(x...))
+			}
+		}
+		for _, shard := range shards[1:] {
+			require.NoError(t, shards[0].Merge(shard))
+		}
+		merged := shards[0]
+
+		assert.Equal(t, single.Count(), merged.Count())
+		for i := 0; i < 2; i++ {
+			expected, err := single.Mean(i)
+			require.NoError(t, err)
+			actual, err := merged.Mean(i)
+			require.NoError(t, err)
+			testutil.Approx(t, expected, actual)
+		}
+		for _, tuple := range sums {
+			expected, err := single.Sum(tuple...)
+			require.NoError(t, err)
+			actual, err := merged.Sum(tuple...)
+			require.NoError(t, err)
+			assert.InEpsilon(t, expected, actual, 1e-9, "tuple %v", tuple)
+		}
+
+		// merging leaves the other core untouched
+		assert.Equal(t, 60, shards[3].Count())
+	})
+
+	t.Run("pass: merged shards give the same correlation", func(t *testing.T) {
+		single := NewGlobalCorr()
+		require.NoError(t, Init(single))
+		for _, x := range xs {
+			require.NoError(t, single.Push(x...))
+		}
+
+		merged := NewGlobalCorr()
+		require.NoError(t, Init(merged))
+		other := NewGlobalCorr()
+		require.NoError(t, Init(other))
+		for i, x := range xs {
+			if i%3 == 0 {
+				require.NoError(t, merged.Push(x...))
+			} else {
+				require.NoError(t, other.Push(x...))
+			}
+		}
+		require.NoError(t, merged.core.Merge(other.core))
+
+		expected, err := single.core.Sum(1, 1)
+		require.NoError(t, err)
+		actual, err := merged.core.Sum(1, 1)
+		require.NoError(t, err)
+		testutil.Approx(t, expected, actual)
+
+		expected, err = single.Value()
+		require.NoError(t, err)
+		actual, err = merged.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, expected, actual)
+	})
+
+	t.Run("pass: concurrent merges in opposite directions do not deadlock", func(t *testing.T) {
+		config := func() *CoreConfig {
+			return &CoreConfig{Sums: SumsConfig{{1, 1}}, Window: stream.IntPtr(0)}
+		}
+		a, err := NewCore(config())
+		require.NoError(t, err)
+		b, err := NewCore(config())
+		require.NoError(t, err)
+
+		// the cores are left empty, so that they can be merged any number of times;
+		// the merges need to run in parallel to interleave, even on a single CPU
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+		var wg sync.WaitGroup
+		for _, pair := range [][2]*Core{{a, b}, {b, a}} {
+			wg.Add(1)
+			go func(core *Core, other *Core) {
+				defer wg.Done()
+				for i := 0; i < 100000; i++ {
+					assert.NoError(t, core.Merge(other))
+				}
+			}(pair[0], pair[1])
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("concurrent merges deadlocked")
+		}
+	})
+
+	t.Run("fail: invalid merges are rejected", func(t *testing.T) {
+		newCore := func(config *CoreConfig) *Core {
+			core, err := NewCore(config)
+			require.NoError(t, err)
+			return core
+		}
+		sums := SumsConfig{{1, 1}}
+		core := newCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(0)})
+
+		err := core.Merge(core)
+		testutil.ContainsError(t, err, "cannot merge a Core with itself")
+
+		err = core.Merge(newCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(3)}))
+		testutil.ContainsError(t, err, "cannot merge Cores with a window")
+
+		err = core.Merge(newCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(0), Decay: stream.FloatPtr(0.3)}))
+		testutil.ContainsError(t, err, "cannot merge Cores with decay")
+
+		err = core.Merge(newCore(&CoreConfig{Sums: SumsConfig{{1, 1, 0}}, Window: stream.IntPtr(0)}))
+		testutil.ContainsError(t, err, "cannot merge Cores tracking 2 and 3 variables")
+
+		err = core.Merge(newCore(&CoreConfig{Sums: SumsConfig{{2, 0}}, Window: stream.IntPtr(0)}))
+		testutil.ContainsError(t, err, "cannot merge Cores tracking different sums")
+	})
+}
+
+func TestClone(t *testing.T) {
+	t.Run("pass: clone is unaffected by later pushes to the original", func(t *testing.T) {
+		wrapper := &mockWrapper{window: stream.IntPtr(3)}
+		err := Init(wrapper)
+		require.NoError(t, err)
+
+		xs := []float64{1, 2, 3, 4}
+		for _, x := range xs {
+			err := wrapper.core.Push(x, x*x)
+			require.NoError(t, err)
+		}
+
+		clone := wrapper.core.Clone()
+
+		expected, err := clone.Sum(2, 0)
+		require.NoError(t, err)
+		testutil.Approx(t, 2., expected)
+
+		for _, x := range []float64{8, 16} {
+			err := wrapper.core.Push(x, x*x)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, clone.Count())
+		mean, err := clone.Mean(0)
+		require.NoError(t, err)
+		testutil.Approx(t, 3., mean)
+		sum, err := clone.Sum(2, 0)
+		require.NoError(t, err)
+		testutil.Approx(t, expected, sum)
+
+		// the clone's window holds its own copy of the values, so pushing the same
+		// values to it should bring it back in line with the original
+		for _, x := range []float64{8, 16} {
+			err := clone.Push(x, x*x)
+			require.NoError(t, err)
+		}
+
+		for _, tuple := range []Tuple{{2, 0}, {1, 1}, {2, 2}} {
+			expected, err := wrapper.core.Sum(tuple...)
+			require.NoError(t, err)
+			actual, err := clone.Sum(tuple...)
+			require.NoError(t, err)
+			testutil.Approx(t, expected, actual)
+		}
+	})
+
+	t.Run("pass: clone does not share decay with the original", func(t *testing.T) {
+		wrapper := &mockWrapper{window: stream.IntPtr(0), decay: stream.FloatPtr(0.3)}
+		err := Init(wrapper)
+		require.NoError(t, err)
+
+		err = wrapper.core.Push(1, 2)
+		require.NoError(t, err)
+
+		clone := wrapper.core.Clone()
+		assert.Equal(t, *wrapper.core.decay, *clone.decay)
+		assert.NotSame(t, wrapper.core.decay, clone.decay)
+	})
+}
+
+func TestApproxEqual(t *testing.T) {
+	newCore := func(sums SumsConfig, xs ...float64) *Core {
+		core, err := NewCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+		for _, x := range xs {
+			err := core.Push(x, x*x)
+			require.NoError(t, err)
+		}
+		return core
+	}
+	sums := SumsConfig{{1, 1}, {2, 0}, {0, 2}}
+
+	t.Run("pass: core equals itself and its clone", func(t *testing.T) {
+		core := newCore(sums, 1, 2, 3, 4)
+		assert.True(t, core.ApproxEqual(core, 10))
+
+		clone := core.Clone()
+		assert.True(t, core.ApproxEqual(clone, 10))
+		assert.True(t, clone.ApproxEqual(core, 10))
+	})
+
+	t.Run("pass: order of the configured sums does not matter", func(t *testing.T) {
+		core := newCore(sums, 1, 2, 3)
+		other := newCore(SumsConfig{{0, 2}, {2, 0}, {1, 1}}, 1, 2, 3)
+		assert.True(t, core.ApproxEqual(other, 10))
+	})
+
+	t.Run("pass: tolerance follows the number of digits", func(t *testing.T) {
+		core := newCore(sums, 1, 2, 3)
+		other, err := NewCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+		for _, x := range []float64{1, 2, 3} {
+			err := other.Push(x+0.001, x*x)
+			require.NoError(t, err)
+		}
+		// the means of x differ by 0.001, while the centralized sums are equal
+		assert.True(t, core.ApproxEqual(other, 2))
+		assert.False(t, core.ApproxEqual(other, 3))
+	})
+
+	t.Run("pass: concurrent comparisons and pushes do not deadlock", func(t *testing.T) {
+		a, err := NewCore(&CoreConfig{Sums: SumsConfig{{1, 1}}, Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+		b, err := NewCore(&CoreConfig{Sums: SumsConfig{{1, 1}}, Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+		var wg sync.WaitGroup
+		for _, pair := range [][2]*Core{{a, b}, {b, a}} {
+			wg.Add(2)
+			go func(core *Core, other *Core) {
+				defer wg.Done()
+				for i := 0; i < 100000; i++ {
+					core.ApproxEqual(other, 10)
+				}
+			}(pair[0], pair[1])
+			go func(core *Core) {
+				defer wg.Done()
+				for i := 0; i < 100000; i++ {
+					assert.NoError(t, core.Push(1, 2))
+				}
+			}(pair[0])
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("concurrent comparisons deadlocked")
+		}
+	})
+
+	t.Run("fail: differing counts are not equal", func(t *testing.T) {
+		core := newCore(sums, 2, 2)
+		other := newCore(sums, 2, 2, 2)
+		assert.False(t, core.ApproxEqual(other, 0))
+	})
+
+	t.Run("fail: differing sums are not equal", func(t *testing.T) {
+		core := newCore(sums, 1, 2, 3)
+		other := newCore(SumsConfig{{1, 1}, {2, 0}, {0, 3}}, 1, 2, 3)
+		assert.False(t, core.ApproxEqual(other, 0))
+		assert.False(t, other.ApproxEqual(core, 0))
+	})
+}
+
+func TestNonFinite(t *testing.T) {
+	newCore := func(mode *stream.NonFiniteMode) *Core {
+		core, err := NewCore(&CoreConfig{
+			Sums:      SumsConfig{{1, 1}, {2, 0}},
+			Window:    stream.IntPtr(3),
+			NonFinite: mode,
+		})
+		require.NoError(t, err)
+
+		for _, x := range []float64{1, 2, 3} {
+			err := core.Push(x, 2*x)
+			require.NoError(t, err)
+		}
+		return core
+	}
+
+	t.Run("fail: non-finite values return error by default", func(t *testing.T) {
+		for _, mode := range []*stream.NonFiniteMode{nil, stream.NonFiniteModePtr(stream.NonFiniteError)} {
+			core := newCore(mode)
+			err := core.Push(4, math.NaN())
+			assert.EqualError(t, err, "NaN is not a finite value")
+
+			// the window is left untouched
+			assert.Equal(t, 3, core.Count())
+			mean, err := core.Mean(1)
+			require.NoError(t, err)
+			testutil.Approx(t, 4, mean)
+		}
+	})
+
+	t.Run("pass: skip mode leaves count and sums unchanged", func(t *testing.T) {
+		core := newCore(stream.NonFiniteModePtr(stream.NonFiniteSkip))
+		for _, x := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+			err := core.Push(x, 1)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, core.Count())
+		assert.Equal(t, uint64(3), core.queue.Len())
+		mean, err := core.Mean(0)
+		require.NoError(t, err)
+		testutil.Approx(t, 2, mean)
+		sum, err := core.Sum(1, 1)
+		require.NoError(t, err)
+		testutil.Approx(t, 4, sum)
+
+		// finite values are still consumed, evicting the oldest values
+		err = core.Push(4, 8)
+		require.NoError(t, err)
+		mean, err = core.Mean(0)
+		require.NoError(t, err)
+		testutil.Approx(t, 3, mean)
+	})
+
+	t.Run("pass: propagate mode consumes non-finite values", func(t *testing.T) {
+		core := newCore(stream.NonFiniteModePtr(stream.NonFinitePropagate))
+		err := core.Push(math.Inf(1), 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, core.Count())
+		mean, err := core.Mean(0)
+		require.NoError(t, err)
+		assert.True(t, math.IsInf(mean, 1))
+	})
+}
+
+func TestWindow(t *testing.T) {
+	t.Run("pass: returns the tuples in the window in order after evictions", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{{1, 1}},
+			Window: stream.IntPtr(2),
+		})
+		require.NoError(t, err)
+
+		for x := 1.; x <= 4; x++ {
+			err := core.Push(x, -x)
+			require.NoError(t, err)
+		}
+
+		window := core.Window()
+		assert.Equal(t, [][]float64{{3, -3}, {4, -4}}, window)
+
+		// the returned tuples are copies of those in the window
+		window[0][0] = 100
+		err = core.Push(5, -5)
+		require.NoError(t, err)
+
+		window = core.Window()
+		assert.Equal(t, [][]float64{{4, -4}, {5, -5}}, window)
+		mean, err := core.Mean(0)
+		require.NoError(t, err)
+		testutil.Approx(t, 4.5, mean)
+	})
+
+	t.Run("pass: returns an empty slice for a global core", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{{1, 1}},
+			Window: stream.IntPtr(0),
+		})
+		require.NoError(t, err)
+
+		err = core.Push(1, 2)
+		require.NoError(t, err)
+
+		window := core.Window()
+		assert.Equal(t, [][]float64{}, window)
+	})
+}
+
+func TestSentinelErrors(t *testing.T) {
+	t.Run("pass: untracked sums and variables match their sentinels", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{{1, 1}},
+			Window: stream.IntPtr(0),
+		})
+		require.NoError(t, err)
+		err = core.Push(1, 2)
+		require.NoError(t, err)
+
+		_, err = core.Sum(3, 0)
+		assert.True(t, errors.Is(err, ErrorNotTracked))
+		assert.EqualError(t, err, "[3 0] is not a tracked power sum")
+
+		_, err = core.Mean(2)
+		assert.True(t, errors.Is(err, ErrorNotTrackedVariable))
+		assert.EqualError(t, err, "2 is not a tracked variable")
+	})
+
+	t.Run("pass: metrics without values match ErrorNoValuesSeen", func(t *testing.T) {
+		for _, metric := range []Metric{NewGlobalCov(), NewGlobalCorr(), NewGlobalLinReg()} {
+			err := Init(metric)
+			require.NoError(t, err)
+
+			_, err = metric.Value()
+			assert.True(t, errors.Is(err, ErrorNoValuesSeen), metric.String())
+		}
+	})
+}
+
+func TestSumsMatchDirectComputation(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	vals := make([][]float64, 50)
+	for i := range vals {
+		vals[i] = []float64{r.NormFloat64(), 3 * r.Float64(), r.ExpFloat64()}
+	}
+
+	// direct computes the centralized sum for a Tuple over the given values;
+	// the Core keeps no sum for the zero Tuple, so that is always 0
+	direct := func(vals [][]float64, tuple Tuple) float64 {
+		if tuple.abs() == 0 {
+			return 0
+		}
+
+		means := make([]float64, len(tuple))
+		for _, xs := range vals {
+			for i, x := range xs {
+				means[i] += x / float64(len(vals))
+			}
+		}
+
+		sum := 0.
+		for _, xs := range vals {
+			prod := 1.
+			for i, x := range xs {
+				prod *= math.Pow(x-means[i], float64(tuple[i]))
+			}
+			sum += prod
+		}
+		return sum
+	}
+
+	fourthOrder := SumsConfig{
+		{4, 0, 0},
+		{0, 4, 0},
+		{0, 0, 4},
+		{2, 1, 1},
+		{1, 2, 1},
+		{1, 1, 2},
+	}
+	for _, tc := range []struct {
+		config SumsConfig
+		window int
+	}{
+		{fourthOrder, 0},
+		{fourthOrder, 1},
+		{fourthOrder, 3},
+		{SumsConfig{{2, 1, 1}}, 7},
+		{SumsConfig{{1, 1, 2}}, 7},
+		// Tuples sharing lower-order sums, as for a Composite
+		{SumsConfig{{2, 1, 1}, {1, 1, 2}}, 7},
+		{SumsConfig{{1, 1, 0}, {2, 0, 0}, {0, 2, 0}, {0, 1, 1}}, 3},
+	} {
+		t.Run(fmt.Sprintf("pass: %v with window of %d", tc.config, tc.window), func(t *testing.T) {
+			core, err := NewCore(&CoreConfig{
+				Sums:   tc.config,
+				Window: stream.IntPtr(tc.window),
+			})
+			require.NoError(t, err)
+
+			for i, xs := range vals {
+				err := core.Push(xs...)
+				require.NoError(t, err)
+
+				seen := vals[:i+1]
+				if tc.window > 0 && len(seen) > tc.window {
+					seen = seen[len(seen)-tc.window:]
+				}
+				for _, tuple := range tc.config {
+					_ = iter(tuple, false, func(xs ...int) error {
+						sum, err := core.Sum(xs...)
+						require.NoError(t, err)
+						testutil.Approx(t, direct(seen, Tuple(xs)), sum)
+						return nil
+					})
+				}
+			}
+		})
+	}
+}
+
+func TestHighOrderSums(t *testing.T) {
+	// multinomial coefficients for orders this high overflow an int
+	tuple := Tuple{22, 1}
+	core, err := NewCore(&CoreConfig{
+		Sums:   SumsConfig{tuple},
+		Window: stream.IntPtr(0),
+	})
+	require.NoError(t, err)
+
+	vals := [][]float64{{1, 2}, {2, 5}, {4, 1}, {0, 3}, {5, 2}, {-1, 1}}
+	for _, xs := range vals {
+		err := core.Push(xs...)
+		require.NoError(t, err)
+	}
+
+	means := []float64{11. / 6., 14. / 6.}
+	expected, scale := 0., 0.
+	for _, xs := range vals {
+		term := math.Pow(xs[0]-means[0], 22) * (xs[1] - means[1])
+		expected += term
+		scale += math.Abs(term)
+	}
+
+	sum, err := core.Sum(tuple...)
+	require.NoError(t, err)
+	assert.InDelta(t, expected, sum, 1e-9*scale)
+}
+
+func TestDecayWindow(t *testing.T) {
+	config := SumsConfig{
+		{4, 0, 0},
+		{0, 4, 0},
+		{0, 0, 4},
+		{2, 1, 1},
+		{1, 2, 1},
+		{1, 1, 2},
+	}
+	decay := 0.3
+
+	r := rand.New(rand.NewSource(1))
+	vals := make([][]float64, 20)
+	for i := range vals {
+		vals[i] = []float64{r.NormFloat64(), 3 * r.Float64(), r.ExpFloat64()}
+	}
+
+	for _, window := range []int{1, 4} {
+		t.Run(fmt.Sprintf("pass: matches sums weighted by age within a window of %d", window), func(t *testing.T) {
+			core, err := NewCore(&CoreConfig{
+				Sums:   config,
+				Window: stream.IntPtr(window),
+				Decay:  stream.FloatPtr(decay),
+			})
+			require.NoError(t, err)
+
+			for i, xs := range vals {
+				err := core.Push(xs...)
+				require.NoError(t, err)
+
+				seen := vals[:i+1]
+				if len(seen) > window {
+					seen = seen[len(seen)-window:]
+				}
+				weights := make([]float64, len(seen))
+				total := 0.
+				for j := range seen {
+					weights[j] = math.Pow(1-decay, float64(len(seen)-1-j))
+					total += weights[j]
+				}
+				means := make([]float64, 3)
+				for j, ys := range seen {
+					for v, y := range ys {
+						means[v] += weights[j] / total * y
+					}
+				}
+
+				for v := range means {
+					mean, err := core.Mean(v)
+					require.NoError(t, err)
+					testutil.Approx(t, means[v], mean)
+				}
+				for _, tuple := range config {
+					_ = iter(tuple, false, func(xs ...int) error {
+						if Tuple(xs).abs() == 0 {
+							return nil
+						}
+
+						expected := 0.
+						for j, ys := range seen {
+							prod := weights[j] / total
+							for v, y := range ys {
+								prod *= math.Pow(y-means[v], float64(xs[v]))
+							}
+							expected += prod
+						}
+
+						sum, err := core.Sum(xs...)
+						require.NoError(t, err)
+						testutil.Approx(t, expected, sum)
+						return nil
+					})
+				}
+			}
+		})
+	}
+}
+
+func TestConstantValues(t *testing.T) {
+	config := SumsConfig{{2, 1, 1}, {0, 3, 1}, {4, 0, 0}}
+	xs := []float64{0.1, -1.7, 3.3}
+
+	for _, tt := range []struct {
+		window int
+		decay  *float64
+	}{
+		{0, nil},
+		{3, nil},
+		{0, stream.FloatPtr(0.3)},
+		{3, stream.FloatPtr(0.3)},
+		{4, stream.FloatPtr(1e-17)},
+	} {
+		name := fmt.Sprintf("pass: window %d", tt.window)
+		if tt.decay != nil {
+			name = fmt.Sprintf("%s with decay %v", name, *tt.decay)
+		}
+		t.Run(name+" yields exactly 0 sums", func(t *testing.T) {
+			core, err := NewCore(&CoreConfig{
+				Sums:   config,
+				Window: stream.IntPtr(tt.window),
+				Decay:  tt.decay,
+			})
+			require.NoError(t, err)
+
+			for i := 0; i < 20; i++ {
+				err := core.Push(xs...)
+				require.NoError(t, err)
+			}
+
+			for v, x := range xs {
+				mean, err := core.Mean(v)
+				require.NoError(t, err)
+				assert.Equal(t, x, mean)
+			}
+			for _, tuple := range config {
+				_ = iter(tuple, false, func(ys ...int) error {
+					sum, err := core.Sum(ys...)
+					require.NoError(t, err)
+					assert.Equal(t, 0., sum, "sum for %v", ys)
+					return nil
+				})
+			}
+		})
+	}
+}
+
+func TestPushMatchesPreviousSums(t *testing.T) {
+	// these sums were recorded before the per-Tuple terms were precomputed
+	// in NewCore, and must be reproduced exactly
+	fourthOrder := SumsConfig{
+		{4, 0, 0},
+		{0, 4, 0},
+		{0, 0, 4},
+		{2, 1, 1},
+		{1, 2, 1},
+		{1, 1, 2},
+	}
+	for _, tc := range []struct {
+		name     string
+		config   *CoreConfig
+		expected []float64
+	}{
+		{
+			name:   "pass: global",
+			config: &CoreConfig{Sums: fourthOrder, Window: stream.IntPtr(0)},
+			expected: []float64{
+				0, 0, 31.578405429490417, 4.189207820846546, 90.43299554300202, 0,
+				15.466341743167705, 11.046397995070182, 28.82183656520098, 0,
+				18.94713940705142, 37.764372810342856, 114.80442039878245,
+				4.1947186212629575, -5.1668041137564416, -2.942111881755702,
+				-0.5836045361330043, 0.46312189433877193, 5.954095477877668,
+				0.41338884722955055, -5.839528994434702, 2.8100548641683374,
+				0.6318442274548455, -7.939919847352004, -2.3609156168417065,
+				20.96862624216549,
+			},
+		},
+		{
+			name: "pass: decay",
+			config: &CoreConfig{
+				Sums:   fourthOrder,
+				Window: stream.IntPtr(0),
+				Decay:  stream.FloatPtr(0.3),
+			},
+			expected: []float64{
+				0, 0, 0.8358317876843336, 0.3470167918435181, 1.3090159675008524, 0,
+				0.6996993602446413, 0.7617307840453142, 1.6257149182854462, 0,
+				0.445303367970163, 1.0949522503973415, 3.5228144246881317,
+				0.30616212693009254, 0.23743877186881956, -0.09790544648646736,
+				-0.06800335965735436, -0.1336731121518949, -0.039300989201664804,
+				-0.09010138313556122, 0.603284579254612, -0.14664877947491575,
+				-0.21013608825565255, -0.07348067571052, -0.08906422731260116,
+				0.38383293293292,
+			},
+		},
+		{
+			name:   "pass: window",
+			config: &CoreConfig{Sums: SumsConfig{{2, 1, 1}}, Window: stream.IntPtr(5)},
+			expected: []float64{
+				0, 0, 3.458781388373014, 0, 1.8784690399807238, 1.8157030037208244, 0,
+				-0.6812925214783525, -0.5809337804229963, -0.661934052879901,
+				-0.7373638090565062, -0.9577503381244903,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			core, err := NewCore(tc.config)
+			require.NoError(t, err)
+
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 20; i++ {
+				err := core.Push(r.NormFloat64(), 3*r.Float64(), r.ExpFloat64())
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expected, core.sums)
+		})
+	}
+}
+
+func benchmarkCorePush(b *testing.B, config *CoreConfig) {
+	r := rand.New(rand.NewSource(1))
+	vals := make([][]float64, 1e4)
+	for i := range vals {
+		vals[i] = []float64{r.NormFloat64(), r.NormFloat64(), r.NormFloat64()}
+	}
+
+	for i := 0; i < b.N; i++ {
+		core, err := NewCore(config)
+		require.NoError(b, err)
+		for _, xs := range vals {
+			err := core.Push(xs...)
+			require.NoError(b, err)
+		}
+	}
+}
+
+var benchmarkSums = SumsConfig{
+	{4, 0, 0},
+	{0, 4, 0},
+	{0, 0, 4},
+	{2, 1, 1},
+	{1, 2, 1},
+	{1, 1, 2},
+}
+
+func BenchmarkCorePush(b *testing.B) {
+	benchmarkCorePush(b, &CoreConfig{
+		Sums:   benchmarkSums,
+		Window: stream.IntPtr(1000),
+	})
+}
+
+func BenchmarkCorePushDecay(b *testing.B) {
+	benchmarkCorePush(b, &CoreConfig{
+		Sums:   benchmarkSums,
+		Window: stream.IntPtr(0),
+		Decay:  stream.FloatPtr(0.01),
+	})
+}
@@ -0,0 +1,124 @@
+package joint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CorrMatrix is a metric that tracks the sample Pearson correlation matrix of k variables.
+// It does not satisfy the Metric interface, since its value is a matrix rather than
+// a single number, but it is a CoreWrapper and should be passed into Init().
+type CorrMatrix struct {
+	vars   int
+	window int
+	core   *Core
+}
+
+// NewCorrMatrix instantiates a CorrMatrix struct for the provided number of variables.
+func NewCorrMatrix(vars int, window int) *CorrMatrix {
+	return &CorrMatrix{
+		vars:   vars,
+		window: window,
+	}
+}
+
+// NewGlobalCorrMatrix instantiates a global CorrMatrix struct.
+// This is equivalent to calling NewCorrMatrix(vars, 0).
+func NewGlobalCorrMatrix(vars int) *CorrMatrix {
+	return NewCorrMatrix(vars, 0)
+}
+
+// SetCore sets the Core.
+func (m *CorrMatrix) SetCore(c *Core) {
+	m.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (m *CorrMatrix) IsSetCore() bool {
+	return m.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (m *CorrMatrix) Config() *CoreConfig {
+	return &CoreConfig{
+		Sums:   pairSums(m.vars),
+		Window: &m.window,
+		Vars:   &m.vars,
+	}
+}
+
+// String returns a string representation of the metric.
+func (m *CorrMatrix) String() string {
+	name := "joint.CorrMatrix"
+	params := []string{
+		fmt.Sprintf("vars:%v", m.vars),
+		fmt.Sprintf("window:%v", m.window),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a new tuple of values for CorrMatrix to consume.
+func (m *CorrMatrix) Push(xs ...float64) error {
+	if !m.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	if err := checkArgs("CorrMatrix", m.vars, xs); err != nil {
+		return err
+	}
+
+	err := m.core.Push(xs...)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the sample Pearson correlation matrix;
+// the (i, j) entry is the sample correlation of the ith and jth variables,
+// and the diagonal entries are exactly 1. If the ith variable has zero
+// variance, every entry in the ith row and column is NaN instead.
+func (m *CorrMatrix) Value() ([][]float64, error) {
+	if !m.IsSetCore() {
+		return nil, ErrorCoreNotSet
+	}
+
+	m.core.RLock()
+	defer m.core.RUnlock()
+
+	// as with Corr, the sums are not normalized by the sample size (minus 1),
+	// since the denominator cancels out when dividing by the sqrt of the variances
+	sums, err := pairSumMatrix(m.core, m.vars)
+	if err != nil {
+		return nil, err
+	}
+
+	stds := make([]float64, m.vars)
+	for i := range stds {
+		stds[i] = math.Sqrt(sums[i][i])
+	}
+
+	for i := range sums {
+		for j := range sums[i] {
+			switch {
+			case stds[i] == 0 || stds[j] == 0:
+				sums[i][j] = math.NaN()
+			case i == j:
+				sums[i][j] = 1
+			default:
+				sums[i][j] /= stds[i] * stds[j]
+			}
+		}
+	}
+	return sums, nil
+}
+
+// Clear resets the metric.
+func (m *CorrMatrix) Clear() {
+	if m.IsSetCore() {
+		m.core.Clear()
+	}
+}
@@ -0,0 +1,177 @@
+package joint
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewCorrMatrix(t *testing.T) {
+	m := NewCorrMatrix(3, 3)
+	assert.Equal(t, 3, m.vars)
+	assert.Equal(t, 3, m.window)
+}
+
+func TestNewGlobalCorrMatrix(t *testing.T) {
+	m := NewCorrMatrix(3, 0)
+	globalM := NewGlobalCorrMatrix(3)
+	assert.Equal(t, m, globalM)
+}
+
+type CorrMatrixPushSuite struct {
+	suite.Suite
+	m *CorrMatrix
+}
+
+func TestCorrMatrixPushSuite(t *testing.T) {
+	suite.Run(t, &CorrMatrixPushSuite{})
+}
+
+func (s *CorrMatrixPushSuite) SetupTest() {
+	s.m = NewCorrMatrix(3, 3)
+	err := Init(s.m)
+	s.Require().NoError(err)
+}
+
+func (s *CorrMatrixPushSuite) TestPushSuccess() {
+	err := s.m.Push(3., 9., 1.)
+	s.NoError(err)
+}
+
+func (s *CorrMatrixPushSuite) TestPushFailOnNullCore() {
+	m := NewCorrMatrix(3, 3)
+	err := m.Push(0., 0., 0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *CorrMatrixPushSuite) TestPushFailOnWrongNumberOfArguments() {
+	err := s.m.Push(0., 0.)
+	testutil.ContainsError(s.T(), err, "CorrMatrix expected 3 arguments: got 2 ([0 0])")
+}
+
+type CorrMatrixValueSuite struct {
+	suite.Suite
+	xs      [][]float64
+	globalM *CorrMatrix
+}
+
+func TestCorrMatrixValueSuite(t *testing.T) {
+	suite.Run(t, &CorrMatrixValueSuite{})
+}
+
+func (s *CorrMatrixValueSuite) SetupTest() {
+	s.globalM = NewGlobalCorrMatrix(3)
+	err := Init(s.globalM)
+	s.Require().NoError(err)
+
+	s.xs = [][]float64{
+		{1, 2, 3},
+		{2, 4, 1},
+		{3, 7, 2},
+		{6, 8, 6},
+		{3, 4, 8},
+	}
+	for _, x := range s.xs {
+		err := s.globalM.Push(x...)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *CorrMatrixValueSuite) TestValueSuccess() {
+	value, err := s.globalM.Value()
+	s.Require().NoError(err)
+
+	expected := [][]float64{
+		{1, 4 / math.Sqrt(21), 2.75 / math.Sqrt(29.75)},
+		{4 / math.Sqrt(21), 1, 1 / math.Sqrt(51)},
+		{2.75 / math.Sqrt(29.75), 1 / math.Sqrt(51), 1},
+	}
+	assertMatrixApprox(s.T(), expected, value)
+
+	// the diagonal should be exact
+	for i := range value {
+		s.Equal(1., value[i][i])
+	}
+}
+
+func (s *CorrMatrixValueSuite) TestValueMatchesCorr() {
+	corr := NewGlobalCorr()
+	err := Init(corr)
+	s.Require().NoError(err)
+
+	for _, x := range s.xs {
+		err := corr.Push(x[0], x[2])
+		s.Require().NoError(err)
+	}
+
+	expected, err := corr.Value()
+	s.Require().NoError(err)
+
+	value, err := s.globalM.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), expected, value[0][2])
+	testutil.Approx(s.T(), expected, value[2][0])
+}
+
+func (s *CorrMatrixValueSuite) TestValueNaNOnZeroVariance() {
+	m := NewGlobalCorrMatrix(3)
+	err := Init(m)
+	s.Require().NoError(err)
+
+	for _, x := range s.xs {
+		err := m.Push(x[0], 5, x[2])
+		s.Require().NoError(err)
+	}
+
+	value, err := m.Value()
+	s.Require().NoError(err)
+
+	for i := 0; i < 3; i++ {
+		s.True(math.IsNaN(value[1][i]))
+		s.True(math.IsNaN(value[i][1]))
+	}
+	s.Equal(1., value[0][0])
+	s.Equal(1., value[2][2])
+	testutil.Approx(s.T(), 2.75/math.Sqrt(29.75), value[0][2])
+}
+
+func (s *CorrMatrixValueSuite) TestValueFailOnNullCore() {
+	m := NewCorrMatrix(3, 3)
+	_, err := m.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *CorrMatrixValueSuite) TestValueFailIfNoValuesSeen() {
+	m := NewCorrMatrix(3, 3)
+	err := Init(m)
+	s.Require().NoError(err)
+
+	_, err = m.Value()
+	testutil.ContainsError(s.T(), err, "no values seen yet")
+}
+
+func TestCorrMatrixClear(t *testing.T) {
+	m := NewCorrMatrix(3, 3)
+	err := Init(m)
+	require.NoError(t, err)
+
+	for i := 0.; i < 5; i++ {
+		err := m.Push(i, i*i, -i)
+		require.NoError(t, err)
+	}
+
+	m.Clear()
+	assert.Equal(t, 0, m.core.count)
+	assert.Equal(t, uint64(0), m.core.queue.Len())
+}
+
+func TestCorrMatrixString(t *testing.T) {
+	m := NewCorrMatrix(3, 3)
+	expectedString := "joint.CorrMatrix_{vars:3,window:3}"
+	assert.Equal(t, expectedString, m.String())
+}
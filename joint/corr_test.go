@@ -3,7 +3,9 @@ package joint
 import (
 	"fmt"
 	"math"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -156,8 +158,8 @@ func TestCorrClear(t *testing.T) {
 		32: 0.,
 		62: 0.,
 	}
-	assert.Equal(t, expectedSums, corr.core.sums)
-	assert.Equal(t, expectedSums, corr.core.newSums)
+	assert.Equal(t, expectedSums, byHash(corr.core, corr.core.sums))
+	assert.Equal(t, expectedSums, byHash(corr.core, corr.core.newSums))
 	assert.Equal(t, 0, corr.core.count)
 	assert.Equal(t, uint64(0), corr.core.queue.Len())
 }
@@ -167,3 +169,47 @@ func TestCorrString(t *testing.T) {
 	expectedString := "joint.Corr_{window:3}"
 	assert.Equal(t, expectedString, corr.String())
 }
+
+func TestCorrConcurrentPushAndValue(t *testing.T) {
+	corr := NewCorr(10)
+	err := Init(corr)
+	require.NoError(t, err)
+
+	for i := 0.; i < 10; i++ {
+		err := corr.Push(i, i*i)
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0.; i < 1000; i++ {
+				assert.NoError(t, corr.Push(i, i*i))
+			}
+		}()
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				_, err := corr.Value()
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	done := make(chan bool)
+	go func() {
+		wg.Wait()
+		done <- true
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("concurrent pushes and reads deadlocked")
+	}
+}
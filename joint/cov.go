@@ -41,6 +41,11 @@ func (cov *Cov) Config() *CoreConfig {
 	}
 }
 
+// QueriedSums returns the sums queried from the Core.
+func (cov *Cov) QueriedSums() SumsConfig {
+	return SumsConfig{{1, 1}}
+}
+
 // String returns a string representation of the metric.
 func (cov *Cov) String() string {
 	name := "joint.Cov"
@@ -50,15 +55,11 @@ func (cov *Cov) String() string {
 // Push adds a new pair of values for Cov to consume.
 func (cov *Cov) Push(xs ...float64) error {
 	if !cov.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
-	if len(xs) != 2 {
-		return errors.Errorf(
-			"Cov expected 2 arguments: got %d (%v)",
-			len(xs),
-			xs,
-		)
+	if err := checkArgs("Cov", 2, xs); err != nil {
+		return err
 	}
 
 	err := cov.core.Push(xs...)
@@ -71,18 +72,24 @@ func (cov *Cov) Push(xs ...float64) error {
 // Value returns the value of the sample covariance.
 func (cov *Cov) Value() (float64, error) {
 	if !cov.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	}
 
 	cov.core.RLock()
 	defer cov.core.RUnlock()
+	return cov.UnsafeValue()
+}
 
-	covariance, err := cov.core.Sum(1, 1)
+// UnsafeValue returns the same value as Value, but does not lock the Core.
+// This should only be used if the user plans to make use of the
+// RLock()/RUnlock() Core methods.
+func (cov *Cov) UnsafeValue() (float64, error) {
+	covariance, err := cov.core.UnsafeSum(1, 1)
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving sum")
+		return 0, fmt.Errorf("error retrieving sum: %w", err)
 	}
 
-	count := cov.core.Count()
+	count := cov.core.UnsafeCount()
 	covariance /= (float64(count) - 1.)
 
 	return covariance, nil
@@ -0,0 +1,152 @@
+package joint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CovMatrix is a metric that tracks the sample covariance matrix of k variables.
+// It does not satisfy the Metric interface, since its value is a matrix rather than
+// a single number, but it is a CoreWrapper and should be passed into Init().
+type CovMatrix struct {
+	vars   int
+	window int
+	core   *Core
+}
+
+// NewCovMatrix instantiates a CovMatrix struct for the provided number of variables.
+func NewCovMatrix(vars int, window int) *CovMatrix {
+	return &CovMatrix{
+		vars:   vars,
+		window: window,
+	}
+}
+
+// NewGlobalCovMatrix instantiates a global CovMatrix struct.
+// This is equivalent to calling NewCovMatrix(vars, 0).
+func NewGlobalCovMatrix(vars int) *CovMatrix {
+	return NewCovMatrix(vars, 0)
+}
+
+// SetCore sets the Core.
+func (m *CovMatrix) SetCore(c *Core) {
+	m.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (m *CovMatrix) IsSetCore() bool {
+	return m.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (m *CovMatrix) Config() *CoreConfig {
+	return &CoreConfig{
+		Sums:   pairSums(m.vars),
+		Window: &m.window,
+		Vars:   &m.vars,
+	}
+}
+
+// String returns a string representation of the metric.
+func (m *CovMatrix) String() string {
+	name := "joint.CovMatrix"
+	params := []string{
+		fmt.Sprintf("vars:%v", m.vars),
+		fmt.Sprintf("window:%v", m.window),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a new tuple of values for CovMatrix to consume.
+func (m *CovMatrix) Push(xs ...float64) error {
+	if !m.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	if err := checkArgs("CovMatrix", m.vars, xs); err != nil {
+		return err
+	}
+
+	err := m.core.Push(xs...)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the sample covariance matrix;
+// the (i, j) entry is the sample covariance of the ith and jth variables.
+func (m *CovMatrix) Value() ([][]float64, error) {
+	if !m.IsSetCore() {
+		return nil, ErrorCoreNotSet
+	}
+
+	m.core.RLock()
+	defer m.core.RUnlock()
+
+	sums, err := pairSumMatrix(m.core, m.vars)
+	if err != nil {
+		return nil, err
+	}
+
+	count := float64(m.core.UnsafeCount())
+	for i := range sums {
+		for j := range sums[i] {
+			sums[i][j] /= count - 1
+		}
+	}
+	return sums, nil
+}
+
+// Clear resets the metric.
+func (m *CovMatrix) Clear() {
+	if m.IsSetCore() {
+		m.core.Clear()
+	}
+}
+
+// pairTuple returns the Tuple over vars variables for the joint
+// centralized sum of the ith and jth variables; if i == j, this is
+// the sum of squared differences of the ith variable.
+func pairTuple(vars int, i int, j int) Tuple {
+	tuple := make(Tuple, vars)
+	tuple[i]++
+	tuple[j]++
+	return tuple
+}
+
+// pairSums returns the SumsConfig tracking the joint centralized
+// sums of every pair of the vars variables (including each variable with itself).
+func pairSums(vars int) SumsConfig {
+	sums := SumsConfig{}
+	for i := 0; i < vars; i++ {
+		for j := i; j < vars; j++ {
+			sums = append(sums, pairTuple(vars, i, j))
+		}
+	}
+	return sums
+}
+
+// pairSumMatrix returns the symmetric matrix of the joint centralized sums of
+// every pair of the vars variables, but does not lock the Core.
+func pairSumMatrix(core *Core, vars int) ([][]float64, error) {
+	sums := make([][]float64, vars)
+	for i := range sums {
+		sums[i] = make([]float64, vars)
+	}
+
+	for i := 0; i < vars; i++ {
+		for j := i; j < vars; j++ {
+			tuple := pairTuple(vars, i, j)
+			sum, err := core.UnsafeSum(tuple...)
+			if err != nil {
+				return nil, fmt.Errorf("error retrieving sum for %v: %w", tuple, err)
+			}
+			sums[i][j] = sum
+			sums[j][i] = sum
+		}
+	}
+	return sums, nil
+}
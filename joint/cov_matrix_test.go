@@ -0,0 +1,181 @@
+package joint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewCovMatrix(t *testing.T) {
+	m := NewCovMatrix(3, 3)
+	assert.Equal(t, 3, m.vars)
+	assert.Equal(t, 3, m.window)
+}
+
+func TestNewGlobalCovMatrix(t *testing.T) {
+	m := NewCovMatrix(3, 0)
+	globalM := NewGlobalCovMatrix(3)
+	assert.Equal(t, m, globalM)
+}
+
+func TestCovMatrixConfig(t *testing.T) {
+	m := NewCovMatrix(3, 3)
+	expectedSums := SumsConfig{
+		{2, 0, 0},
+		{1, 1, 0},
+		{1, 0, 1},
+		{0, 2, 0},
+		{0, 1, 1},
+		{0, 0, 2},
+	}
+	assert.Equal(t, expectedSums, m.Config().Sums)
+	assert.Equal(t, 3, *m.Config().Vars)
+}
+
+type CovMatrixPushSuite struct {
+	suite.Suite
+	m *CovMatrix
+}
+
+func TestCovMatrixPushSuite(t *testing.T) {
+	suite.Run(t, &CovMatrixPushSuite{})
+}
+
+func (s *CovMatrixPushSuite) SetupTest() {
+	s.m = NewCovMatrix(3, 3)
+	err := Init(s.m)
+	s.Require().NoError(err)
+}
+
+func (s *CovMatrixPushSuite) TestPushSuccess() {
+	err := s.m.Push(3., 9., 1.)
+	s.NoError(err)
+}
+
+func (s *CovMatrixPushSuite) TestPushFailOnNullCore() {
+	m := NewCovMatrix(3, 3)
+	err := m.Push(0., 0., 0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *CovMatrixPushSuite) TestPushFailOnWrongNumberOfArguments() {
+	err := s.m.Push(0., 0.)
+	testutil.ContainsError(s.T(), err, "CovMatrix expected 3 arguments: got 2 ([0 0])")
+}
+
+func (s *CovMatrixPushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.m.core.queue.Dispose()
+
+	err := s.m.Push(3., 9., 1.)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+type CovMatrixValueSuite struct {
+	suite.Suite
+	m       *CovMatrix
+	globalM *CovMatrix
+}
+
+func TestCovMatrixValueSuite(t *testing.T) {
+	suite.Run(t, &CovMatrixValueSuite{})
+}
+
+func (s *CovMatrixValueSuite) SetupTest() {
+	s.m = NewCovMatrix(3, 3)
+	err := Init(s.m)
+	s.Require().NoError(err)
+
+	s.globalM = NewGlobalCovMatrix(3)
+	err = Init(s.globalM)
+	s.Require().NoError(err)
+
+	xs := [][]float64{
+		{1, 2, 3},
+		{2, 4, 1},
+		{3, 7, 2},
+		{6, 8, 6},
+		{3, 4, 8},
+	}
+	for _, x := range xs {
+		err := s.m.Push(x...)
+		s.Require().NoError(err)
+
+		err = s.globalM.Push(x...)
+		s.Require().NoError(err)
+	}
+}
+
+func assertMatrixApprox(t *testing.T, expected [][]float64, actual [][]float64) {
+	require.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		require.Equal(t, len(expected[i]), len(actual[i]))
+		for j := range expected[i] {
+			testutil.Approx(t, expected[i][j], actual[i][j])
+		}
+	}
+}
+
+func (s *CovMatrixValueSuite) TestValueSuccess() {
+	value, err := s.m.Value()
+	s.Require().NoError(err)
+
+	expected := [][]float64{
+		{3, 5. / 2., 1},
+		{5. / 2., 13. / 3., -11. / 3.},
+		{1, -11. / 3., 28. / 3.},
+	}
+	assertMatrixApprox(s.T(), expected, value)
+}
+
+func (s *CovMatrixValueSuite) TestValueSuccessGlobal() {
+	value, err := s.globalM.Value()
+	s.Require().NoError(err)
+
+	expected := [][]float64{
+		{7. / 2., 4, 11. / 4.},
+		{4, 6, 1},
+		{11. / 4., 1, 17. / 2.},
+	}
+	assertMatrixApprox(s.T(), expected, value)
+}
+
+func (s *CovMatrixValueSuite) TestValueFailOnNullCore() {
+	m := NewCovMatrix(3, 3)
+	_, err := m.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *CovMatrixValueSuite) TestValueFailIfNoValuesSeen() {
+	m := NewCovMatrix(3, 3)
+	err := Init(m)
+	s.Require().NoError(err)
+
+	_, err = m.Value()
+	testutil.ContainsError(s.T(), err, "no values seen yet")
+}
+
+func TestCovMatrixClear(t *testing.T) {
+	m := NewCovMatrix(3, 3)
+	err := Init(m)
+	require.NoError(t, err)
+
+	for i := 0.; i < 5; i++ {
+		err := m.Push(i, i*i, -i)
+		require.NoError(t, err)
+	}
+
+	m.Clear()
+	assert.Equal(t, 0, m.core.count)
+	assert.Equal(t, uint64(0), m.core.queue.Len())
+}
+
+func TestCovMatrixString(t *testing.T) {
+	m := NewCovMatrix(3, 3)
+	expectedString := "joint.CovMatrix_{vars:3,window:3}"
+	assert.Equal(t, expectedString, m.String())
+}
@@ -115,6 +115,22 @@ func (s *CovValueSuite) TestValueSuccess() {
 	testutil.Approx(s.T(), 79., value)
 }
 
+func (s *CovValueSuite) TestValueSuccessGlobal() {
+	cov := NewGlobalCov()
+	err := Init(cov)
+	s.Require().NoError(err)
+
+	xs := []float64{1, 2, 3, 4, 8}
+	for _, x := range xs {
+		err := cov.Push(x, x*x)
+		s.Require().NoError(err)
+	}
+
+	value, err := cov.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 68.4, value)
+}
+
 func (s *CovValueSuite) TestValueFailOnNullCore() {
 	cov := NewCov(3)
 	_, err := cov.Value()
@@ -149,8 +165,8 @@ func TestCovClear(t *testing.T) {
 		31: 0.,
 		32: 0.,
 	}
-	assert.Equal(t, expectedSums, cov.core.sums)
-	assert.Equal(t, expectedSums, cov.core.newSums)
+	assert.Equal(t, expectedSums, byHash(cov.core, cov.core.sums))
+	assert.Equal(t, expectedSums, byHash(cov.core, cov.core.newSums))
 	assert.Equal(t, 0, cov.core.count)
 	assert.Equal(t, uint64(0), cov.core.queue.Len())
 }
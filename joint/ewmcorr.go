@@ -43,6 +43,11 @@ func (corr *EWMCorr) Config() *CoreConfig {
 	}
 }
 
+// QueriedSums returns the sums queried from the Core.
+func (corr *EWMCorr) QueriedSums() SumsConfig {
+	return SumsConfig{{1, 1}, {2, 0}, {0, 2}}
+}
+
 // String returns a string representation of the metric.
 func (corr *EWMCorr) String() string {
 	name := "joint.EWMCorr"
@@ -52,15 +57,11 @@ func (corr *EWMCorr) String() string {
 // Push adds a new pair of values for EWMCorr to consume.
 func (corr *EWMCorr) Push(xs ...float64) error {
 	if !corr.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
-	if len(xs) != 2 {
-		return errors.Errorf(
-			"EWMCorr expected 2 arguments: got %d (%v)",
-			len(xs),
-			xs,
-		)
+	if err := checkArgs("EWMCorr", 2, xs); err != nil {
+		return err
 	}
 
 	err := corr.core.Push(xs...)
@@ -73,30 +74,36 @@ func (corr *EWMCorr) Push(xs ...float64) error {
 // Value returns the value of the sample Pearson correlation coefficient.
 func (corr *EWMCorr) Value() (float64, error) {
 	if !corr.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	}
 
 	corr.core.RLock()
 	defer corr.core.RUnlock()
+	return corr.UnsafeValue()
+}
 
+// UnsafeValue returns the same value as Value, but does not lock the Core.
+// This should only be used if the user plans to make use of the
+// RLock()/RUnlock() Core methods.
+func (corr *EWMCorr) UnsafeValue() (float64, error) {
 	// this is technically not the covariance, as it is not normalized by
 	// the sample size (minus 1), but the denominator is cancelled out
 	// when dividing by the sqrt of the variances, so we can avoid extra
 	// float ops here
-	cov, err := corr.core.Sum(1, 1)
+	cov, err := corr.core.UnsafeSum(1, 1)
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving sum for {1, 1}")
+		return 0, fmt.Errorf("error retrieving sum for {1, 1}: %w", err)
 	}
 
 	// ditto with the "variance" variables here, as with above
-	xVar, err := corr.core.Sum(2, 0)
+	xVar, err := corr.core.UnsafeSum(2, 0)
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving sum for {2, 0}")
+		return 0, fmt.Errorf("error retrieving sum for {2, 0}: %w", err)
 	}
 
-	yVar, err := corr.core.Sum(0, 2)
+	yVar, err := corr.core.UnsafeSum(0, 2)
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving sum for {0, 2}")
+		return 0, fmt.Errorf("error retrieving sum for {0, 2}: %w", err)
 	}
 
 	return cov / math.Sqrt(xVar*yVar), nil
@@ -128,8 +128,8 @@ func TestEWMCorrClear(t *testing.T) {
 		32: 0.,
 		62: 0.,
 	}
-	assert.Equal(t, expectedSums, corr.core.sums)
-	assert.Equal(t, expectedSums, corr.core.newSums)
+	assert.Equal(t, expectedSums, byHash(corr.core, corr.core.sums))
+	assert.Equal(t, expectedSums, byHash(corr.core, corr.core.newSums))
 	assert.Equal(t, 0, corr.core.count)
 }
 
@@ -38,6 +38,11 @@ func (cov *EWMCov) Config() *CoreConfig {
 	}
 }
 
+// QueriedSums returns the sums queried from the Core.
+func (cov *EWMCov) QueriedSums() SumsConfig {
+	return SumsConfig{{1, 1}}
+}
+
 // String returns a string representation of the metric.
 func (cov *EWMCov) String() string {
 	name := "joint.EWMCov"
@@ -47,15 +52,11 @@ func (cov *EWMCov) String() string {
 // Push adds a new pair of values for EWMCov to consume.
 func (cov *EWMCov) Push(xs ...float64) error {
 	if !cov.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
-	if len(xs) != 2 {
-		return errors.Errorf(
-			"EWMCov expected 2 arguments: got %d (%v)",
-			len(xs),
-			xs,
-		)
+	if err := checkArgs("EWMCov", 2, xs); err != nil {
+		return err
 	}
 
 	err := cov.core.Push(xs...)
@@ -68,12 +69,21 @@ func (cov *EWMCov) Push(xs ...float64) error {
 // Value returns the value of the sample exponentially weighted covariance.
 func (cov *EWMCov) Value() (float64, error) {
 	if !cov.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	}
 
-	covariance, err := cov.core.Sum(1, 1)
+	cov.core.RLock()
+	defer cov.core.RUnlock()
+	return cov.UnsafeValue()
+}
+
+// UnsafeValue returns the same value as Value, but does not lock the Core.
+// This should only be used if the user plans to make use of the
+// RLock()/RUnlock() Core methods.
+func (cov *EWMCov) UnsafeValue() (float64, error) {
+	covariance, err := cov.core.UnsafeSum(1, 1)
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving sum")
+		return 0, fmt.Errorf("error retrieving sum: %w", err)
 	}
 
 	return covariance, nil
@@ -121,8 +121,8 @@ func TestEWMCovClear(t *testing.T) {
 		31: 0.,
 		32: 0.,
 	}
-	assert.Equal(t, expectedSums, cov.core.sums)
-	assert.Equal(t, expectedSums, cov.core.newSums)
+	assert.Equal(t, expectedSums, byHash(cov.core, cov.core.sums))
+	assert.Equal(t, expectedSums, byHash(cov.core, cov.core.newSums))
 	assert.Equal(t, 0, cov.core.count)
 }
 
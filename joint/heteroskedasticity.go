@@ -0,0 +1,99 @@
+package joint
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// HeteroskedasticityScore is a metric that tracks the sample correlation between
+// the values of a stream and their squared deviations from the mean; a nonzero
+// correlation indicates that the variance of the stream changes with its level.
+// Like Autocorr, it does not satisfy the JointMetric interface, but rather the
+// univariate Metric interface (SimpleMetric in particular), since it only tracks
+// a single variable.
+//
+// Each value is centered on the mean of the values seen before it (within the window,
+// if one is set), rather than on a mean that already includes the value itself; this
+// prevents a large deviation from partially masking itself by shifting the mean towards
+// it. The first value seen therefore has a squared deviation of 0.
+type HeteroskedasticityScore struct {
+	corr *Corr
+	core *Core
+}
+
+// NewHeteroskedasticityScore instantiates a HeteroskedasticityScore struct.
+func NewHeteroskedasticityScore(window int) *HeteroskedasticityScore {
+	return &HeteroskedasticityScore{corr: NewCorr(window)}
+}
+
+// NewGlobalHeteroskedasticityScore instantiates a global HeteroskedasticityScore struct.
+// This is equivalent to calling NewHeteroskedasticityScore(0).
+func NewGlobalHeteroskedasticityScore() *HeteroskedasticityScore {
+	return NewHeteroskedasticityScore(0)
+}
+
+// SetCore sets the Core.
+func (h *HeteroskedasticityScore) SetCore(c *Core) {
+	h.corr.SetCore(c)
+	h.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (h *HeteroskedasticityScore) IsSetCore() bool {
+	return h.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (h *HeteroskedasticityScore) Config() *CoreConfig {
+	return h.corr.Config()
+}
+
+// String returns a string representation of the metric.
+func (h *HeteroskedasticityScore) String() string {
+	name := "joint.HeteroskedasticityScore"
+	return fmt.Sprintf("%s_{window:%v}", name, h.corr.window)
+}
+
+// Push adds a new value for HeteroskedasticityScore to consume.
+func (h *HeteroskedasticityScore) Push(x float64) error {
+	if !h.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	h.core.Lock()
+	defer h.core.Unlock()
+
+	mean := x
+	if h.core.UnsafeCount() > 0 {
+		var err error
+		mean, err = h.core.UnsafeMean(0)
+		if err != nil {
+			return fmt.Errorf("error retrieving mean: %w", err)
+		}
+	}
+
+	delta := x - mean
+	err := h.core.UnsafePush(x, delta*delta)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the sample correlation between the values
+// and their squared deviations.
+func (h *HeteroskedasticityScore) Value() (float64, error) {
+	if !h.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	return h.corr.Value()
+}
+
+// Clear resets the metric.
+func (h *HeteroskedasticityScore) Clear() {
+	if h.IsSetCore() {
+		h.core.Clear()
+	}
+}
@@ -0,0 +1,156 @@
+package joint
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewHeteroskedasticityScore(t *testing.T) {
+	h := NewHeteroskedasticityScore(3)
+	assert.Equal(t, NewCorr(3), h.corr)
+}
+
+func TestNewGlobalHeteroskedasticityScore(t *testing.T) {
+	h := NewHeteroskedasticityScore(0)
+	globalH := NewGlobalHeteroskedasticityScore()
+	assert.Equal(t, h, globalH)
+}
+
+type HeteroskedasticityScorePushSuite struct {
+	suite.Suite
+	h *HeteroskedasticityScore
+}
+
+func TestHeteroskedasticityScorePushSuite(t *testing.T) {
+	suite.Run(t, &HeteroskedasticityScorePushSuite{})
+}
+
+func (s *HeteroskedasticityScorePushSuite) SetupTest() {
+	s.h = NewHeteroskedasticityScore(3)
+	err := Init(s.h)
+	s.Require().NoError(err)
+}
+
+func (s *HeteroskedasticityScorePushSuite) TestPushSuccess() {
+	xs := []float64{1, 2, 3}
+	for _, x := range xs {
+		err := s.h.Push(x)
+		s.Require().NoError(err)
+	}
+
+	// the squared deviations are taken from the mean of the preceding values
+	mean, err := s.h.core.Mean(1)
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), (0.+1.+9./4.)/3., mean)
+}
+
+func (s *HeteroskedasticityScorePushSuite) TestPushFailOnNullCore() {
+	h := NewHeteroskedasticityScore(3)
+	err := h.Push(0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *HeteroskedasticityScorePushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.h.core.queue.Dispose()
+
+	err := s.h.Push(3.)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+type HeteroskedasticityScoreValueSuite struct {
+	suite.Suite
+	h       *HeteroskedasticityScore
+	globalH *HeteroskedasticityScore
+}
+
+func TestHeteroskedasticityScoreValueSuite(t *testing.T) {
+	suite.Run(t, &HeteroskedasticityScoreValueSuite{})
+}
+
+func (s *HeteroskedasticityScoreValueSuite) SetupTest() {
+	s.h = NewHeteroskedasticityScore(3)
+	err := Init(s.h)
+	s.Require().NoError(err)
+
+	s.globalH = NewGlobalHeteroskedasticityScore()
+	err = Init(s.globalH)
+	s.Require().NoError(err)
+
+	xs := []float64{1, 2, 3, 4, 8}
+	for _, x := range xs {
+		err := s.h.Push(x)
+		s.Require().NoError(err)
+
+		err = s.globalH.Push(x)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *HeteroskedasticityScoreValueSuite) TestValueSuccess() {
+	// pairs in window: (3, 9/4), (4, 4), (8, 25)
+	value, err := s.h.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), (133./2.)/math.Sqrt(14.*7693./24.), value)
+}
+
+func (s *HeteroskedasticityScoreValueSuite) TestValueSuccessGlobal() {
+	// pairs: (1, 0), (2, 1), (3, 9/4), (4, 4), (8, 121/4)
+	value, err := s.globalH.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), (527./4.)/math.Sqrt(146./5.*5247./8.), value)
+}
+
+func (s *HeteroskedasticityScoreValueSuite) TestValueFailOnNullCore() {
+	h := NewHeteroskedasticityScore(3)
+	_, err := h.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *HeteroskedasticityScoreValueSuite) TestValueFailIfNoValuesSeen() {
+	h := NewHeteroskedasticityScore(3)
+	err := Init(h)
+	s.Require().NoError(err)
+
+	_, err = h.Value()
+	testutil.ContainsError(s.T(), err, "no values seen yet")
+}
+
+func TestHeteroskedasticityScoreClear(t *testing.T) {
+	h := NewHeteroskedasticityScore(3)
+	err := Init(h)
+	require.NoError(t, err)
+
+	xs := []float64{1, 2, 3, 4, 8}
+	for _, x := range xs {
+		err := h.Push(x)
+		require.NoError(t, err)
+	}
+
+	h.Clear()
+
+	expectedSums := map[uint64]float64{
+		0:  0.,
+		1:  0.,
+		2:  0.,
+		31: 0.,
+		32: 0.,
+		62: 0.,
+	}
+	assert.Equal(t, expectedSums, byHash(h.core, h.core.sums))
+	assert.Equal(t, expectedSums, byHash(h.core, h.core.newSums))
+	assert.Equal(t, 0, h.core.count)
+	assert.Equal(t, uint64(0), h.core.queue.Len())
+}
+
+func TestHeteroskedasticityScoreString(t *testing.T) {
+	h := NewHeteroskedasticityScore(3)
+	expectedString := "joint.HeteroskedasticityScore_{window:3}"
+	assert.Equal(t, expectedString, h.String())
+}
@@ -0,0 +1,165 @@
+package joint
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// LinReg is a metric that tracks the ordinary least squares fit y = a + b*x
+// of a stream of (x, y) pairs, without storing the points themselves. Like Cov
+// it can track the global fit or the fit over a rolling window; it can also
+// track an exponentially weighted fit, like EWMCov. Its Value is the slope b.
+type LinReg struct {
+	window int
+	decay  *float64
+	core   *Core
+}
+
+// NewLinReg instantiates a LinReg struct.
+func NewLinReg(window int) *LinReg {
+	return &LinReg{window: window}
+}
+
+// NewGlobalLinReg instantiates a global LinReg struct.
+// This is equivalent to calling NewLinReg(0).
+func NewGlobalLinReg() *LinReg {
+	return NewLinReg(0)
+}
+
+// NewEWLinReg instantiates an exponentially weighted LinReg struct.
+func NewEWLinReg(decay float64) *LinReg {
+	return &LinReg{decay: &decay}
+}
+
+// SetCore sets the Core.
+func (l *LinReg) SetCore(c *Core) {
+	l.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (l *LinReg) IsSetCore() bool {
+	return l.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (l *LinReg) Config() *CoreConfig {
+	return &CoreConfig{
+		Sums: SumsConfig{
+			{1, 1},
+			{2, 0},
+		},
+		Window: &l.window,
+		Decay:  l.decay,
+	}
+}
+
+// QueriedSums returns the sums queried from the Core.
+func (l *LinReg) QueriedSums() SumsConfig {
+	return SumsConfig{{1, 1}, {2, 0}}
+}
+
+// String returns a string representation of the metric.
+func (l *LinReg) String() string {
+	name := "joint.LinReg"
+	if l.decay != nil {
+		return fmt.Sprintf("%s_{decay:%v}", name, *l.decay)
+	}
+	return fmt.Sprintf("%s_{window:%v}", name, l.window)
+}
+
+// Push adds a new pair of values (x, y) for LinReg to consume.
+func (l *LinReg) Push(xs ...float64) error {
+	if !l.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	if err := checkArgs("LinReg", 2, xs); err != nil {
+		return err
+	}
+
+	err := l.core.Push(xs...)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the slope of the fitted line; it is equivalent to calling Slope().
+func (l *LinReg) Value() (float64, error) {
+	return l.Slope()
+}
+
+// UnsafeValue returns the same value as Value, but does not lock the Core.
+// This should only be used if the user plans to make use of the
+// RLock()/RUnlock() Core methods.
+func (l *LinReg) UnsafeValue() (float64, error) {
+	return l.slope()
+}
+
+// Slope returns the slope b of the fitted line y = a + b*x.
+func (l *LinReg) Slope() (float64, error) {
+	if !l.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	l.core.RLock()
+	defer l.core.RUnlock()
+
+	return l.slope()
+}
+
+// Intercept returns the intercept a of the fitted line y = a + b*x.
+func (l *LinReg) Intercept() (float64, error) {
+	if !l.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	l.core.RLock()
+	defer l.core.RUnlock()
+
+	slope, err := l.slope()
+	if err != nil {
+		return 0, err
+	}
+
+	xMean, err := l.core.UnsafeMean(0)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving mean of x: %w", err)
+	}
+
+	yMean, err := l.core.UnsafeMean(1)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving mean of y: %w", err)
+	}
+
+	return yMean - slope*xMean, nil
+}
+
+// slope returns the slope of the fitted line, but does not lock the Core.
+func (l *LinReg) slope() (float64, error) {
+	// neither sum is normalized by the sample size, since the
+	// normalization cancels out in the ratio
+	cov, err := l.core.UnsafeSum(1, 1)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving sum for {1, 1}: %w", err)
+	}
+
+	xVar, err := l.core.UnsafeSum(2, 0)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving sum for {2, 0}: %w", err)
+	}
+
+	if xVar == 0 {
+		return 0, errors.New("x has zero variance")
+	}
+
+	return cov / xVar, nil
+}
+
+// Clear resets the metric.
+func (l *LinReg) Clear() {
+	if l.IsSetCore() {
+		l.core.Clear()
+	}
+}
@@ -0,0 +1,200 @@
+package joint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewLinReg(t *testing.T) {
+	l := NewLinReg(3)
+	assert.Equal(t, 3, l.window)
+	assert.Nil(t, l.decay)
+}
+
+func TestNewGlobalLinReg(t *testing.T) {
+	l := NewLinReg(0)
+	globalL := NewGlobalLinReg()
+	assert.Equal(t, l, globalL)
+}
+
+func TestNewEWLinReg(t *testing.T) {
+	l := NewEWLinReg(0.3)
+	assert.Equal(t, 0, l.window)
+	assert.Equal(t, 0.3, *l.decay)
+}
+
+type LinRegPushSuite struct {
+	suite.Suite
+	l *LinReg
+}
+
+func TestLinRegPushSuite(t *testing.T) {
+	suite.Run(t, &LinRegPushSuite{})
+}
+
+func (s *LinRegPushSuite) SetupTest() {
+	s.l = NewLinReg(3)
+	err := Init(s.l)
+	s.Require().NoError(err)
+}
+
+func (s *LinRegPushSuite) TestPushSuccess() {
+	err := s.l.Push(3., 9.)
+	s.NoError(err)
+}
+
+func (s *LinRegPushSuite) TestPushFailOnNullCore() {
+	l := NewLinReg(3)
+	err := l.Push(0., 0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *LinRegPushSuite) TestPushFailOnWrongNumberOfValues() {
+	err := s.l.Push(3.)
+	testutil.ContainsError(s.T(), err, "LinReg expected 2 arguments: got 1 ([3])")
+
+	err = s.l.Push(3., 9., 27.)
+	testutil.ContainsError(s.T(), err, "LinReg expected 2 arguments: got 3 ([3 9 27])")
+}
+
+func (s *LinRegPushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.l.core.queue.Dispose()
+
+	err := s.l.Push(3., 9.)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+func TestLinRegValue(t *testing.T) {
+	t.Run("pass: global fit of a noiseless line is exact", func(t *testing.T) {
+		l := NewGlobalLinReg()
+		err := Init(l)
+		require.NoError(t, err)
+
+		for x := -3.; x <= 5; x++ {
+			err := l.Push(x, 2+3*x)
+			require.NoError(t, err)
+		}
+
+		slope, err := l.Slope()
+		require.NoError(t, err)
+		testutil.Approx(t, 3., slope)
+
+		intercept, err := l.Intercept()
+		require.NoError(t, err)
+		testutil.Approx(t, 2., intercept)
+
+		value, err := l.Value()
+		require.NoError(t, err)
+		assert.Equal(t, slope, value)
+	})
+
+	t.Run("pass: windowed fit only sees the values in the window", func(t *testing.T) {
+		l := NewLinReg(3)
+		err := Init(l)
+		require.NoError(t, err)
+
+		for x := 1.; x <= 3; x++ {
+			err := l.Push(x, 1+2*x)
+			require.NoError(t, err)
+		}
+		for x := 4.; x <= 6; x++ {
+			err := l.Push(x, 5-x)
+			require.NoError(t, err)
+		}
+
+		slope, err := l.Slope()
+		require.NoError(t, err)
+		testutil.Approx(t, -1., slope)
+
+		intercept, err := l.Intercept()
+		require.NoError(t, err)
+		testutil.Approx(t, 5., intercept)
+	})
+
+	t.Run("pass: exponentially weighted fit of a noiseless line is exact", func(t *testing.T) {
+		l := NewEWLinReg(0.3)
+		err := Init(l)
+		require.NoError(t, err)
+
+		for _, x := range []float64{4, -1, 7, 2, 0, 3} {
+			err := l.Push(x, 0.5*x-1)
+			require.NoError(t, err)
+		}
+
+		slope, err := l.Slope()
+		require.NoError(t, err)
+		testutil.Approx(t, 0.5, slope)
+
+		intercept, err := l.Intercept()
+		require.NoError(t, err)
+		testutil.Approx(t, -1., intercept)
+	})
+
+	t.Run("fail: x with zero variance fails", func(t *testing.T) {
+		l := NewGlobalLinReg()
+		err := Init(l)
+		require.NoError(t, err)
+
+		for y := 0.; y < 3; y++ {
+			err := l.Push(1, y)
+			require.NoError(t, err)
+		}
+
+		_, err = l.Slope()
+		testutil.ContainsError(t, err, "x has zero variance")
+
+		_, err = l.Intercept()
+		testutil.ContainsError(t, err, "x has zero variance")
+	})
+
+	t.Run("fail: no values seen fails", func(t *testing.T) {
+		l := NewGlobalLinReg()
+		err := Init(l)
+		require.NoError(t, err)
+
+		_, err = l.Slope()
+		testutil.ContainsError(t, err, "no values seen yet")
+
+		_, err = l.Intercept()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+
+	t.Run("fail: null core fails", func(t *testing.T) {
+		l := NewGlobalLinReg()
+
+		_, err := l.Slope()
+		testutil.ContainsError(t, err, "Core is not set")
+
+		_, err = l.Intercept()
+		testutil.ContainsError(t, err, "Core is not set")
+	})
+}
+
+func TestLinRegClear(t *testing.T) {
+	l := NewLinReg(3)
+	err := Init(l)
+	require.NoError(t, err)
+
+	for x := 0.; x < 5; x++ {
+		err := l.Push(x, 2*x)
+		require.NoError(t, err)
+	}
+
+	l.Clear()
+	assert.Equal(t, 0, l.core.count)
+	assert.Equal(t, uint64(0), l.core.queue.Len())
+}
+
+func TestLinRegString(t *testing.T) {
+	l := NewLinReg(3)
+	assert.Equal(t, "joint.LinReg_{window:3}", l.String())
+
+	l = NewEWLinReg(0.3)
+	assert.Equal(t, "joint.LinReg_{decay:0.3}", l.String())
+}
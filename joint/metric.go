@@ -1,6 +1,10 @@
 package joint
 
-import "github.com/K4Mobility/stream"
+import (
+	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream"
+)
 
 // Metric is the interface for a metric that tracks joint statistics of a stream.
 // Any Metric that will actually be consuming values (i.e. will have its Push
@@ -17,3 +21,28 @@ type CoreWrapper interface {
 	SetCore(*Core)
 	Config() *CoreConfig
 }
+
+// SumsQuerier is an optional interface for a CoreWrapper that declares the
+// sums it queries from its Core, i.e. the Tuples it passes to Core.Sum or
+// Core.UnsafeSum. Init checks that every one of them is tracked by the Core
+// created from the wrapper's config, so that a misconfigured metric fails at
+// setup rather than at its first call to Value.
+type SumsQuerier interface {
+	QueriedSums() SumsConfig
+}
+
+// checkArgs returns an error naming the metric if xs does not hold exactly
+// want values. The Core also validates the number of values it is pushed, but
+// checking up front gives the caller a message about the metric they called.
+func checkArgs(name string, want int, xs []float64) error {
+	if len(xs) != want {
+		return errors.Errorf(
+			"%s expected %d arguments: got %d (%v)",
+			name,
+			want,
+			len(xs),
+			xs,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package joint
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestMetricsShareInit(t *testing.T) {
+	autocorr, err := NewAutocorr(1, 3)
+	require.NoError(t, err)
+	autocov, err := NewAutocov(1, 3)
+	require.NoError(t, err)
+
+	for _, metric := range []interface {
+		CoreWrapper
+		IsSetCore() bool
+		String() string
+	}{
+		NewCov(3),
+		NewEWMCov(0.3),
+		NewCorr(3),
+		NewEWMCorr(0.3),
+		NewCovMatrix(3, 3),
+		NewCorrMatrix(3, 3),
+		NewLinReg(3),
+		NewRSquared(3),
+		NewHeteroskedasticityScore(3),
+		autocorr,
+		autocov,
+	} {
+		t.Run("pass: "+metric.String()+" is set up with Init", func(t *testing.T) {
+			assert.False(t, metric.IsSetCore())
+
+			// pushing before the Core is set matches the sentinel
+			switch m := metric.(type) {
+			case interface{ Push(...float64) error }:
+				err := m.Push(1, 2)
+				assert.True(t, errors.Is(err, ErrorCoreNotSet))
+			case interface{ Push(float64) error }:
+				err := m.Push(1)
+				assert.True(t, errors.Is(err, ErrorCoreNotSet))
+			default:
+				t.Fatalf("%s has no Push method", metric)
+			}
+
+			err := Init(metric)
+			require.NoError(t, err)
+			assert.True(t, metric.IsSetCore())
+		})
+	}
+}
+
+func TestPushEmpty(t *testing.T) {
+	composite, err := NewComposite(NewCov(3), NewCorr(3))
+	require.NoError(t, err)
+	core, err := NewCore(composite.Config())
+	require.NoError(t, err)
+	composite.SetCore(core)
+
+	for _, tt := range []struct {
+		name    string
+		metric  interface{ Push(...float64) error }
+		message string
+	}{
+		{"Corr", NewCorr(3), "Corr expected 2 arguments: got 0 ([])"},
+		{"Cov", NewCov(3), "Cov expected 2 arguments: got 0 ([])"},
+		{"EWMCorr", NewEWMCorr(0.3), "EWMCorr expected 2 arguments: got 0 ([])"},
+		{"EWMCov", NewEWMCov(0.3), "EWMCov expected 2 arguments: got 0 ([])"},
+		{"LinReg", NewLinReg(3), "LinReg expected 2 arguments: got 0 ([])"},
+		{"RSquared", NewRSquared(3), "RSquared expected 2 arguments: got 0 ([])"},
+		{"CovMatrix", NewCovMatrix(3, 3), "CovMatrix expected 3 arguments: got 0 ([])"},
+		{"CorrMatrix", NewCorrMatrix(3, 3), "CorrMatrix expected 3 arguments: got 0 ([])"},
+		{"Composite", composite, "tried to push 0 values when core is tracking 2 variables"},
+		{"Core", core, "tried to push 0 values when core is tracking 2 variables"},
+	} {
+		t.Run("fail: "+tt.name+" rejects nil and empty values", func(t *testing.T) {
+			if wrapper, ok := tt.metric.(CoreWrapper); ok && tt.name != "Composite" {
+				err := Init(wrapper)
+				require.NoError(t, err)
+			}
+
+			assert.NotPanics(t, func() {
+				err := tt.metric.Push()
+				testutil.ContainsError(t, err, tt.message)
+
+				err = tt.metric.Push(nil...)
+				testutil.ContainsError(t, err, tt.message)
+
+				err = tt.metric.Push([]float64{}...)
+				testutil.ContainsError(t, err, tt.message)
+			})
+		})
+	}
+
+	// nothing was pushed to the shared Core
+	assert.Equal(t, 0, core.Count())
+}
+
+func TestCheckArgs(t *testing.T) {
+	t.Run("pass: matching count is valid", func(t *testing.T) {
+		assert.NoError(t, checkArgs("Corr", 2, []float64{1, 2}))
+	})
+
+	t.Run("fail: mismatched count names the metric", func(t *testing.T) {
+		err := checkArgs("Corr", 2, []float64{1, 2, 3})
+		assert.EqualError(t, err, "Corr expected 2 arguments: got 3 ([1 2 3])")
+
+		err = checkArgs("CovMatrix", 3, nil)
+		assert.EqualError(t, err, "CovMatrix expected 3 arguments: got 0 ([])")
+	})
+
+	t.Run("fail: metrics report their own name", func(t *testing.T) {
+		for _, tt := range []struct {
+			name   string
+			metric Metric
+		}{
+			{"Corr", NewCorr(0)},
+			{"Cov", NewCov(0)},
+			{"EWMCorr", NewEWMCorr(0.3)},
+			{"EWMCov", NewEWMCov(0.3)},
+			{"LinReg", NewLinReg(0)},
+			{"RSquared", NewRSquared(0)},
+		} {
+			err := Init(tt.metric)
+			require.NoError(t, err)
+
+			err = tt.metric.Push(1)
+			assert.EqualError(t, err, tt.name+" expected 2 arguments: got 1 ([1])")
+		}
+	})
+}
+
+// sampleCov returns the sample covariance of xs and ys.
+func sampleCov(xs []float64, ys []float64) float64 {
+	n := float64(len(xs))
+	var xMean, yMean float64
+	for i := range xs {
+		xMean += xs[i] / n
+		yMean += ys[i] / n
+	}
+
+	var sum float64
+	for i := range xs {
+		sum += (xs[i] - xMean) * (ys[i] - yMean)
+	}
+	return sum / (n - 1)
+}
+
+func TestWindowedSampleStatistics(t *testing.T) {
+	// the sample corrections must use the number of values in the window,
+	// not the number of values pushed, once the stream outgrows the window
+	window := 5
+	cov, corr, linreg, rsquared := NewCov(window), NewCorr(window), NewLinReg(window), NewRSquared(window)
+	metrics := []Metric{cov, corr, linreg, rsquared}
+	for _, metric := range metrics {
+		require.NoError(t, Init(metric))
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	xs, ys := make([]float64, 100), make([]float64, 100)
+	for i := range xs {
+		xs[i], ys[i] = rng.Float64()*10, rng.Float64()*10
+		for _, metric := range metrics {
+			require.NoError(t, metric.Push(xs[i], ys[i]))
+		}
+		if i+1 < window {
+			continue
+		}
+
+		wx, wy := xs[i+1-window:i+1], ys[i+1-window:i+1]
+		expectedCov := sampleCov(wx, wy)
+		expectedCorr := expectedCov / math.Sqrt(sampleCov(wx, wx)*sampleCov(wy, wy))
+
+		val, err := cov.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, expectedCov, val, "cov after %d values", i+1)
+
+		val, err = corr.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, expectedCorr, val, "corr after %d values", i+1)
+
+		val, err = linreg.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, expectedCov/sampleCov(wx, wx), val, "slope after %d values", i+1)
+
+		val, err = rsquared.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, expectedCorr*expectedCorr, val, "r-squared after %d values", i+1)
+	}
+}
@@ -0,0 +1,73 @@
+package joint
+
+import (
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// UnsafeMetric is the interface for a Metric whose value can also be
+// retrieved without locking its Core, via UnsafeValue.
+type UnsafeMetric interface {
+	Metric
+	UnsafeValue() (float64, error)
+}
+
+// Reader is a facade over a Core that reads the values of several metrics
+// sharing that Core under a single read lock, so that the values returned
+// by Snapshot are mutually consistent, i.e. they are all computed from the
+// same set of values pushed to the Core. In contrast, calling Value on each
+// metric separately (as Composite.Values does) locks the Core once per metric,
+// so values may be pushed to the Core between any two of the reads.
+type Reader struct {
+	core    *Core
+	metrics []UnsafeMetric
+}
+
+// NewReader instantiates a Reader struct, setting the Core of each of the
+// metrics to the provided Core. The Core must track every sum needed by the
+// metrics, e.g. by being instantiated with the result of merging the configs
+// of the metrics with MergeConfigs.
+func NewReader(core *Core, metrics ...UnsafeMetric) (*Reader, error) {
+	if core == nil {
+		return nil, ErrorCoreNotSet
+	}
+
+	if len(metrics) == 0 {
+		return nil, errors.New("no metrics provided to read")
+	}
+
+	for _, metric := range metrics {
+		metric.SetCore(core)
+	}
+
+	return &Reader{
+		core:    core,
+		metrics: metrics,
+	}, nil
+}
+
+// Snapshot returns the values of the metrics, all retrieved under a single
+// read lock of the Core; in particular, it returns a map of strings to values,
+// where the strings are the string representations of each metric
+// (i.e. the result of calling String()).
+func (r *Reader) Snapshot() (map[string]float64, error) {
+	r.core.RLock()
+	defer r.core.RUnlock()
+
+	values := map[string]float64{}
+	var result *multierror.Error
+	for _, metric := range r.metrics {
+		val, err := metric.UnsafeValue()
+		if err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		values[metric.String()] = val
+	}
+
+	err := result.ErrorOrNil()
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving values from metrics")
+	}
+	return values, nil
+}
@@ -0,0 +1,140 @@
+package joint
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func newReaderCore(t *testing.T, metrics ...Metric) *Core {
+	configs := make([]*CoreConfig, len(metrics))
+	for i, metric := range metrics {
+		configs[i] = metric.Config()
+	}
+	config, err := MergeConfigs(configs...)
+	require.NoError(t, err)
+	core, err := NewCore(config)
+	require.NoError(t, err)
+	return core
+}
+
+func TestNewReader(t *testing.T) {
+	t.Run("pass: metrics share the core", func(t *testing.T) {
+		cov, corr := NewCov(3), NewCorr(3)
+		core := newReaderCore(t, cov, corr)
+		_, err := NewReader(core, cov, corr)
+		require.NoError(t, err)
+		assert.Equal(t, core, cov.core)
+		assert.Equal(t, core, corr.core)
+	})
+
+	t.Run("fail: nil core is invalid", func(t *testing.T) {
+		_, err := NewReader(nil, NewCov(3))
+		assert.Equal(t, ErrorCoreNotSet, err)
+	})
+
+	t.Run("fail: no metrics is invalid", func(t *testing.T) {
+		core := newReaderCore(t, NewCov(3))
+		_, err := NewReader(core)
+		testutil.ContainsError(t, err, "no metrics provided to read")
+	})
+}
+
+func TestReaderSnapshot(t *testing.T) {
+	t.Run("pass: snapshot matches separate values", func(t *testing.T) {
+		cov, corr, linreg := NewCov(3), NewCorr(3), NewLinReg(3)
+		core := newReaderCore(t, cov, corr, linreg)
+		r, err := NewReader(core, cov, corr, linreg)
+		require.NoError(t, err)
+
+		for _, xs := range [][]float64{{1, 2}, {3, -1}, {4, 4}, {-2, 0.5}, {0, 3}} {
+			require.NoError(t, core.Push(xs...))
+		}
+
+		values, err := r.Snapshot()
+		require.NoError(t, err)
+		require.Len(t, values, 3)
+		for _, metric := range []Metric{cov, corr, linreg} {
+			expected, err := metric.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, expected, values[metric.String()])
+		}
+	})
+
+	t.Run("fail: errors from metrics are returned", func(t *testing.T) {
+		cov := NewCov(3)
+		core := newReaderCore(t, cov)
+		r, err := NewReader(core, cov)
+		require.NoError(t, err)
+
+		_, err = r.Snapshot()
+		testutil.ContainsError(t, err, "error retrieving values from metrics")
+		testutil.ContainsError(t, err, ErrorNoValuesSeen.Error())
+	})
+
+	t.Run("fail: sums not tracked by the core are invalid", func(t *testing.T) {
+		cov, corr := NewCov(3), NewCorr(3)
+		core := newReaderCore(t, cov)
+		r, err := NewReader(core, cov, corr)
+		require.NoError(t, err)
+		require.NoError(t, core.Push(1, 2))
+
+		_, err = r.Snapshot()
+		testutil.ContainsError(t, err, ErrorNotTracked.Error())
+	})
+}
+
+func TestReaderConcurrentSnapshots(t *testing.T) {
+	corr, rsquared := NewCorr(10), NewRSquared(10)
+	core := newReaderCore(t, corr, rsquared)
+	r, err := NewReader(core, corr, rsquared)
+	require.NoError(t, err)
+	require.NoError(t, core.Push(0, 1))
+	require.NoError(t, core.Push(1, 0))
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		rng := rand.New(rand.NewSource(0))
+		for i := 0; i < 10000; i++ {
+			x := rng.NormFloat64()
+			// alternate between strongly positively and negatively
+			// correlated runs so that the correlation keeps changing
+			y := x + 0.1*rng.NormFloat64()
+			if (i/50)%2 == 1 {
+				y = -y
+			}
+			assert.NoError(t, core.Push(x, y))
+		}
+	}()
+
+	for snapshots := 0; ; snapshots++ {
+		select {
+		case <-done:
+			wg.Wait()
+			assert.Greater(t, snapshots, 0)
+			return
+		default:
+		}
+
+		values, err := r.Snapshot()
+		require.NoError(t, err)
+		c, r2 := values[corr.String()], values[rsquared.String()]
+		if !math.IsNaN(c) {
+			assert.GreaterOrEqual(t, c, -1-1e-9)
+			assert.LessOrEqual(t, c, 1+1e-9)
+		}
+		// the values are only guaranteed to agree if they
+		// were computed from the same values of the Core
+		testutil.Approx(t, c*c, r2)
+	}
+}
@@ -0,0 +1,122 @@
+package joint
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// RSquared is a metric that tracks the coefficient of determination of a simple
+// linear regression of y on x, i.e. the square of the sample Pearson correlation
+// coefficient of the (x, y) pairs.
+type RSquared struct {
+	window int
+	core   *Core
+}
+
+// NewRSquared instantiates a RSquared struct.
+func NewRSquared(window int) *RSquared {
+	return &RSquared{window: window}
+}
+
+// NewGlobalRSquared instantiates a global RSquared struct.
+// This is equivalent to calling NewRSquared(0).
+func NewGlobalRSquared() *RSquared {
+	return NewRSquared(0)
+}
+
+// SetCore sets the Core.
+func (r *RSquared) SetCore(c *Core) {
+	r.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (r *RSquared) IsSetCore() bool {
+	return r.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (r *RSquared) Config() *CoreConfig {
+	return &CoreConfig{
+		Sums: SumsConfig{
+			{1, 1},
+			{2, 0},
+			{0, 2},
+		},
+		Window: &r.window,
+	}
+}
+
+// QueriedSums returns the sums queried from the Core.
+func (r *RSquared) QueriedSums() SumsConfig {
+	return SumsConfig{{1, 1}, {2, 0}, {0, 2}}
+}
+
+// String returns a string representation of the metric.
+func (r *RSquared) String() string {
+	name := "joint.RSquared"
+	return fmt.Sprintf("%s_{window:%v}", name, r.window)
+}
+
+// Push adds a new pair of values for RSquared to consume.
+func (r *RSquared) Push(xs ...float64) error {
+	if !r.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	if err := checkArgs("RSquared", 2, xs); err != nil {
+		return err
+	}
+
+	err := r.core.Push(xs...)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the coefficient of determination.
+func (r *RSquared) Value() (float64, error) {
+	if !r.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	r.core.RLock()
+	defer r.core.RUnlock()
+	return r.UnsafeValue()
+}
+
+// UnsafeValue returns the same value as Value, but does not lock the Core.
+// This should only be used if the user plans to make use of the
+// RLock()/RUnlock() Core methods.
+func (r *RSquared) UnsafeValue() (float64, error) {
+	// as with Corr, none of these sums are normalized by the sample size
+	// (minus 1), since the normalization cancels out in the ratio
+	cov, err := r.core.UnsafeSum(1, 1)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving sum for {1, 1}: %w", err)
+	}
+
+	xVar, err := r.core.UnsafeSum(2, 0)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving sum for {2, 0}: %w", err)
+	}
+
+	yVar, err := r.core.UnsafeSum(0, 2)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving sum for {0, 2}: %w", err)
+	}
+
+	if xVar == 0 || yVar == 0 {
+		return 0, errors.New("cannot compute R-squared when a variable has zero variance")
+	}
+
+	return cov * cov / (xVar * yVar), nil
+}
+
+// Clear resets the metric.
+func (r *RSquared) Clear() {
+	if r.IsSetCore() {
+		r.core.Clear()
+	}
+}
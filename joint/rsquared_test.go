@@ -0,0 +1,145 @@
+package joint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewRSquared(t *testing.T) {
+	r := NewRSquared(3)
+	assert.Equal(t, 3, r.window)
+}
+
+func TestNewGlobalRSquared(t *testing.T) {
+	r := NewRSquared(0)
+	globalR := NewGlobalRSquared()
+	assert.Equal(t, r, globalR)
+}
+
+type RSquaredPushSuite struct {
+	suite.Suite
+	r *RSquared
+}
+
+func TestRSquaredPushSuite(t *testing.T) {
+	suite.Run(t, &RSquaredPushSuite{})
+}
+
+func (s *RSquaredPushSuite) SetupTest() {
+	s.r = NewRSquared(3)
+	err := Init(s.r)
+	s.Require().NoError(err)
+}
+
+func (s *RSquaredPushSuite) TestPushSuccess() {
+	err := s.r.Push(3., 9.)
+	s.NoError(err)
+}
+
+func (s *RSquaredPushSuite) TestPushFailOnNullCore() {
+	r := NewRSquared(3)
+	err := r.Push(0., 0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *RSquaredPushSuite) TestPushFailOnWrongNumberOfValues() {
+	err := s.r.Push(3.)
+	testutil.ContainsError(s.T(), err, "RSquared expected 2 arguments: got 1 ([3])")
+}
+
+func TestRSquaredValue(t *testing.T) {
+	t.Run("pass: R-squared equals the squared correlation", func(t *testing.T) {
+		r := NewRSquared(3)
+		err := Init(r)
+		require.NoError(t, err)
+
+		corr := NewCorr(3)
+		err = Init(corr)
+		require.NoError(t, err)
+
+		xs := []float64{1, 2, 3, 4, 8}
+		for _, x := range xs {
+			err := r.Push(x, x*x)
+			require.NoError(t, err)
+
+			err = corr.Push(x, x*x)
+			require.NoError(t, err)
+		}
+
+		value, err := r.Value()
+		require.NoError(t, err)
+
+		corrValue, err := corr.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, corrValue*corrValue, value)
+	})
+
+	t.Run("pass: R-squared of a perfect line is 1", func(t *testing.T) {
+		r := NewGlobalRSquared()
+		err := Init(r)
+		require.NoError(t, err)
+
+		for x := 0.; x < 5; x++ {
+			err := r.Push(x, 3-2*x)
+			require.NoError(t, err)
+		}
+
+		value, err := r.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 1., value)
+	})
+
+	t.Run("fail: zero variance fails", func(t *testing.T) {
+		r := NewGlobalRSquared()
+		err := Init(r)
+		require.NoError(t, err)
+
+		for x := 0.; x < 5; x++ {
+			err := r.Push(x, 1)
+			require.NoError(t, err)
+		}
+
+		_, err = r.Value()
+		testutil.ContainsError(t, err, "cannot compute R-squared when a variable has zero variance")
+	})
+
+	t.Run("fail: no values seen fails", func(t *testing.T) {
+		r := NewGlobalRSquared()
+		err := Init(r)
+		require.NoError(t, err)
+
+		_, err = r.Value()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+
+	t.Run("fail: null core fails", func(t *testing.T) {
+		r := NewGlobalRSquared()
+		_, err := r.Value()
+		testutil.ContainsError(t, err, "Core is not set")
+	})
+}
+
+func TestRSquaredClear(t *testing.T) {
+	r := NewRSquared(3)
+	err := Init(r)
+	require.NoError(t, err)
+
+	for x := 0.; x < 5; x++ {
+		err := r.Push(x, x*x)
+		require.NoError(t, err)
+	}
+
+	r.Clear()
+	assert.Equal(t, 0, r.core.count)
+	assert.Equal(t, uint64(0), r.core.queue.Len())
+}
+
+func TestRSquaredString(t *testing.T) {
+	r := NewRSquared(3)
+	assert.Equal(t, "joint.RSquared_{window:3}", r.String())
+}
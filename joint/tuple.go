@@ -2,6 +2,7 @@ package joint
 
 import (
 	"math"
+	"math/big"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
@@ -65,7 +66,10 @@ func sub(m, n Tuple) (Tuple, error) {
 	return result, nil
 }
 
-func multinom(m, n Tuple) (int, error) {
+// multinom returns the product of the binomial coefficients of the elements
+// of m and n. This is computed exactly and only converted to a float64 at the
+// end, since it can overflow an int for higher orders.
+func multinom(m, n Tuple) (float64, error) {
 	if len(m) != len(n) {
 		return 0, errors.Errorf(
 			"Tuples have different lengths: %d != %d",
@@ -74,12 +78,13 @@ func multinom(m, n Tuple) (int, error) {
 		)
 	}
 
-	result := 1
+	result := big.NewInt(1)
 	for i := range m {
-		result *= mathutil.Binom(m[i], n[i])
+		result.Mul(result, mathutil.BinomBig(m[i], n[i]))
 	}
 
-	return result, nil
+	f, _ := new(big.Float).SetInt(result).Float64()
+	return f, nil
 }
 
 func pow(x []float64, n Tuple) (float64, error) {
@@ -93,7 +98,22 @@ func pow(x []float64, n Tuple) (float64, error) {
 
 	result := 1.
 	for i := range x {
-		result *= math.Pow(x[i], float64(n[i]))
+		switch {
+		case n[i] < 0:
+			// the Tuples of a Core are validated to be nonnegative, so a
+			// negative exponent (and thus a possible 0^-k = Inf) is a bug
+			return 0, errors.Errorf("Cannot exponentiate with a negative exponent: %v", n)
+		case n[i] == 0:
+			// x^0 = 1 for any x, including 0 and NaN, so the variables skipped
+			// by the Tuple never affect the result
+			continue
+		case x[i] == 0:
+			// 0^k = 0 for k > 0, so the result is exactly 0, unless a NaN
+			// (or an infinity) elsewhere in x needs to be propagated
+			result *= 0
+		default:
+			result *= math.Pow(x[i], float64(n[i]))
+		}
 	}
 
 	return result, nil
@@ -2,6 +2,7 @@ package joint
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -81,7 +82,15 @@ func TestMultinom(t *testing.T) {
 		n := Tuple{1, 3, 4, 0}
 		value, err := multinom(m, n)
 		require.NoError(t, err)
-		assert.Equal(t, 140, value)
+		assert.Equal(t, 140., value)
+	})
+
+	t.Run("pass: returns multinomial coefficient that overflows an int", func(t *testing.T) {
+		m := Tuple{40, 40}
+		n := Tuple{20, 20}
+		value, err := multinom(m, n)
+		require.NoError(t, err)
+		assert.InEpsilon(t, 137846528820.*137846528820., value, 1e-15)
 	})
 
 	t.Run("fail: returns error if Tuples have different lengths", func(t *testing.T) {
@@ -119,6 +128,35 @@ func TestPow(t *testing.T) {
 		testutil.Approx(t, 0., value)
 	})
 
+	t.Run("pass: zero exponents are skipped", func(t *testing.T) {
+		value, err := pow([]float64{0., math.NaN(), 2.}, Tuple{0, 0, 3})
+		require.NoError(t, err)
+		assert.Equal(t, 8., value)
+
+		value, err = pow([]float64{0., 0.}, Tuple{0, 0})
+		require.NoError(t, err)
+		assert.Equal(t, 1., value)
+	})
+
+	t.Run("pass: zero bases yield exactly 0 unless a NaN is propagated", func(t *testing.T) {
+		value, err := pow([]float64{0., -3.}, Tuple{2, 3})
+		require.NoError(t, err)
+		assert.Equal(t, 0., value)
+
+		value, err = pow([]float64{math.NaN(), 0.}, Tuple{1, 1})
+		require.NoError(t, err)
+		assert.True(t, math.IsNaN(value))
+
+		value, err = pow([]float64{0., math.NaN()}, Tuple{1, 1})
+		require.NoError(t, err)
+		assert.True(t, math.IsNaN(value))
+	})
+
+	t.Run("fail: returns error for negative exponents", func(t *testing.T) {
+		_, err := pow([]float64{0., 1.}, Tuple{-1, 1})
+		assert.EqualError(t, err, "Cannot exponentiate with a negative exponent: [-1 1]")
+	})
+
 	t.Run("fail: returns error if Tuples have different lengths", func(t *testing.T) {
 		x := []float64{1., 2., 1.5}
 		n := Tuple{1, 2, 3, 4}
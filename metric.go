@@ -1,5 +1,7 @@
 package stream
 
+import "github.com/pkg/errors"
+
 // Every metric satisfies one of the following interfaces below.
 
 // Metric is the interface for a metric that consumes from a stream.
@@ -51,3 +53,36 @@ type JointAggregateMetric interface {
 	Values() (map[string]interface{}, error)
 	Clear()
 }
+
+// Valuable is the interface for any entity that reports a singular value;
+// in particular, both SimpleMetric and SimpleJointMetric satisfy it.
+type Valuable interface {
+	Value() (float64, error)
+}
+
+// Pushable is the interface for any entity that consumes tuples of values;
+// in particular, JointMetric satisfies it, and a Metric can be adapted to it
+// with AsJoint, so that univariate and joint metrics can be driven uniformly.
+type Pushable interface {
+	Push(...float64) error
+}
+
+// AsJoint adapts a SimpleMetric to the SimpleJointMetric interface, e.g. to
+// hold it in a slice alongside joint metrics; the Push method of the adapter
+// must be given exactly one value, which is pushed to the SimpleMetric.
+func AsJoint(m SimpleMetric) SimpleJointMetric {
+	return &jointAdapter{SimpleMetric: m}
+}
+
+// jointAdapter wraps a SimpleMetric, replacing its Push method with a variadic one.
+type jointAdapter struct {
+	SimpleMetric
+}
+
+// Push adds a new value for the wrapped metric to consume.
+func (a *jointAdapter) Push(xs ...float64) error {
+	if len(xs) != 1 {
+		return errors.Errorf("%s expected 1 argument: got %d (%v)", a.String(), len(xs), xs)
+	}
+	return a.SimpleMetric.Push(xs[0])
+}
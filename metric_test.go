@@ -0,0 +1,141 @@
+package stream_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/K4Mobility/stream"
+	"github.com/K4Mobility/stream/aggregate"
+	"github.com/K4Mobility/stream/joint"
+	"github.com/K4Mobility/stream/minmax"
+	"github.com/K4Mobility/stream/moment"
+	"github.com/K4Mobility/stream/quantile"
+	"github.com/K4Mobility/stream/signal"
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+// must returns the metric, panicking if it could not be constructed.
+func must[T fmt.Stringer](metric T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return metric
+}
+
+func TestMetricStrings(t *testing.T) {
+	for _, tt := range []struct {
+		metric   fmt.Stringer
+		expected string
+	}{
+		{moment.NewMean(3), "moment.Mean_{window:3}"},
+		{moment.NewSum(3), "moment.Sum_{window:3}"},
+		{moment.NewEWMA(0.3), "moment.EWMA_{decay:0.3}"},
+		{moment.NewGeometricMean(3), "moment.GeometricMean_{window:3}"},
+		{moment.NewHarmonicMean(3), "moment.HarmonicMean_{window:3}"},
+		{moment.New(2, 3), "moment.Moment_{k:2,window:3}"},
+		{moment.NewEWMMoment(2, 0.3), "moment.EWMMoment_{k:2,decay:0.3}"},
+		{moment.NewVariance(3), "moment.Variance_{window:3}"},
+		{moment.NewStd(3), "moment.Std_{window:3}"},
+		{moment.NewEWMStd(0.3), "moment.EWMStd_{decay:0.3}"},
+		{moment.NewEWMVar(0.3), "moment.EWMVar_{decay:0.3}"},
+		{moment.NewSkewness(3), "moment.Skewness_{window:3}"},
+		{moment.NewKurtosis(3), "moment.Kurtosis_{window:3}"},
+		{moment.NewStandardizedMoment(3, 3), "moment.StandardizedMoment_{k:3,window:3}"},
+		{moment.NewTrackingError(3), "moment.TrackingError_{window:3}"},
+		{moment.NewInformationRatio(3), "moment.InformationRatio_{window:3}"},
+		{must(moment.NewSortino(0.5, 3)), "moment.Sortino_{target:0.5,window:3}"},
+		{must(moment.NewCalmar(252, 3)), "moment.Calmar_{annualization:252,window:3}"},
+		{moment.NewZScore(3), "moment.ZScore_{window:3}"},
+		{joint.NewCov(3), "joint.Cov_{window:3}"},
+		{joint.NewEWMCov(0.3), "joint.EWMCov_{decay:0.3}"},
+		{joint.NewCorr(3), "joint.Corr_{window:3}"},
+		{joint.NewEWMCorr(0.3), "joint.EWMCorr_{decay:0.3}"},
+		{must(joint.NewAutocorr(1, 3)), "joint.Autocorr_{lag:1,window:3}"},
+		{must(joint.NewAutocov(1, 3)), "joint.Autocov_{lag:1,window:3}"},
+		{joint.NewHeteroskedasticityScore(3), "joint.HeteroskedasticityScore_{window:3}"},
+		{joint.NewCovMatrix(3, 3), "joint.CovMatrix_{vars:3,window:3}"},
+		{joint.NewCorrMatrix(3, 3), "joint.CorrMatrix_{vars:3,window:3}"},
+		{joint.NewLinReg(3), "joint.LinReg_{window:3}"},
+		{joint.NewRSquared(3), "joint.RSquared_{window:3}"},
+		{must(minmax.NewMin(3)), "minmax.Min_{window:3}"},
+		{must(minmax.NewMax(3)), "minmax.Max_{window:3}"},
+		{must(minmax.NewMaxDrawdown(3)), "minmax.MaxDrawdown_{window:3}"},
+		{must(minmax.NewScaler(3)), "minmax.Scaler_{window:3}"},
+		{must(quantile.NewHeapMedian(3)), "quantile.HeapMedian_{window:3}"},
+		{must(quantile.NewHeapQuantile(0.9, 3)), "quantile.HeapQuantile_{quantile:0.9,window:3}"},
+		{must(quantile.NewTDigest(50)), "quantile.TDigest_{compression:50}"},
+		{must(quantile.NewHistogram(3, []float64{1, 2.5})), "quantile.Histogram_{window:3,boundaries:[1 2.5]}"},
+		{must(quantile.NewMode(3)), "quantile.Mode_{window:3}"},
+		{must(quantile.NewKthSmallest(2, 3)), fmt.Sprintf("quantile.KthSmallest_{k:2,quantile:quantile.Quantile_{window:3,interpolation:%d}}", quantile.Linear)},
+		{must(quantile.NewKthLargest(2, 3)), fmt.Sprintf("quantile.KthLargest_{k:2,quantile:quantile.Quantile_{window:3,interpolation:%d}}", quantile.Linear)},
+		{must(quantile.New(3)), fmt.Sprintf("quantile.Quantile_{window:3,interpolation:%d}", quantile.Linear)},
+		{must(quantile.NewECDF(3)), fmt.Sprintf("quantile.ECDF_{quantile:quantile.Quantile_{window:3,interpolation:%d}}", quantile.Linear)},
+		{must(quantile.NewEWMMedian(0.3)), "quantile.EWMMedian_{decay:0.3}"},
+		{must(quantile.NewP2(0.9)), "quantile.P2_{quantile:0.9}"},
+		{must(signal.NewCrossingCount(1.5, 3)), "signal.CrossingCount_{level:1.5,window:3}"},
+		{
+			aggregate.NewSimpleAggregateMetric(moment.NewMean(3), moment.NewStd(3)),
+			"aggregate.SimpleAggregateMetric_{metrics:[moment.Mean_{window:3} moment.Std_{window:3}]}",
+		},
+		{
+			aggregate.NewSimpleJointAggregateMetric(joint.NewCov(3), joint.NewCorr(3)),
+			"aggregate.SimpleJointAggregateMetric_{metrics:[joint.Cov_{window:3} joint.Corr_{window:3}]}",
+		},
+	} {
+		t.Run(fmt.Sprintf("pass: %s", tt.expected), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.metric.String())
+		})
+	}
+}
+
+func TestAsJoint(t *testing.T) {
+	t.Run("pass: mixed metrics are driven through a shared loop", func(t *testing.T) {
+		mean, corr := moment.NewMean(3), joint.NewCorr(3)
+		require.NoError(t, moment.Init(mean))
+		require.NoError(t, joint.Init(corr))
+		max := must(minmax.NewMax(3))
+
+		metrics := []stream.SimpleJointMetric{stream.AsJoint(mean), stream.AsJoint(max), corr}
+		args := []int{1, 1, 2}
+		xs := [][]float64{{1, 2}, {3, -1}, {4, 4}, {-2, 0.5}, {0, 3}}
+		for _, x := range xs {
+			for i, metric := range metrics {
+				require.NoError(t, metric.Push(x[:args[i]]...))
+			}
+		}
+
+		values := map[string]float64{}
+		for _, metric := range metrics {
+			val, err := metric.Value()
+			require.NoError(t, err)
+			values[metric.String()] = val
+		}
+
+		expectedCorr, err := corr.Value()
+		require.NoError(t, err)
+		require.Len(t, values, 3)
+		testutil.Approx(t, 2./3, values["moment.Mean_{window:3}"])
+		testutil.Approx(t, 4, values["minmax.Max_{window:3}"])
+		testutil.Approx(t, expectedCorr, values["joint.Corr_{window:3}"])
+	})
+
+	t.Run("pass: adapters are Pushable and Valuable", func(t *testing.T) {
+		var pushable stream.Pushable = stream.AsJoint(moment.NewMean(3))
+		_, ok := pushable.(stream.Valuable)
+		assert.True(t, ok)
+	})
+
+	t.Run("fail: adapter must be pushed exactly one value", func(t *testing.T) {
+		mean := moment.NewMean(3)
+		require.NoError(t, moment.Init(mean))
+		adapter := stream.AsJoint(mean)
+
+		err := adapter.Push(1, 2)
+		assert.EqualError(t, err, "moment.Mean_{window:3} expected 1 argument: got 2 ([1 2])")
+		err = adapter.Push()
+		assert.EqualError(t, err, "moment.Mean_{window:3} expected 1 argument: got 0 ([])")
+	})
+}
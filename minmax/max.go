@@ -5,9 +5,10 @@ import (
 	"math"
 	"sync"
 
-	"github.com/Workiva/go-datastructures/queue"
 	"github.com/gammazero/deque"
 	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream/util/ring"
 )
 
 // Max keeps track of the maximum of a stream.
@@ -15,7 +16,7 @@ type Max struct {
 	window int
 	mux    sync.Mutex
 	// Used if window > 0
-	queue *queue.RingBuffer
+	queue ring.Queue[float64]
 	deque *deque.Deque[float64]
 	// Used if window == 0
 	max   float64
@@ -29,7 +30,7 @@ func NewMax(window int) (*Max, error) {
 	}
 
 	return &Max{
-		queue:  queue.NewRingBuffer(uint64(window)),
+		queue:  ring.New[float64](uint64(window)),
 		deque:  deque.New[float64](),
 		max:    math.Inf(-1),
 		window: window,
@@ -40,7 +41,7 @@ func NewMax(window int) (*Max, error) {
 // This is equivalent to calling NewMax(0).
 func NewGlobalMax() *Max {
 	return &Max{
-		queue:  queue.NewRingBuffer(uint64(0)),
+		queue:  ring.New[float64](uint64(0)),
 		deque:  deque.New[float64](),
 		max:    math.Inf(-1),
 		window: 0,
@@ -68,12 +69,12 @@ func (m *Max) Push(x float64) error {
 
 			m.count--
 
-			if m.deque.Front() == *val.(*float64) {
+			if m.deque.Front() == val {
 				m.deque.PopFront()
 			}
 		}
 
-		err := m.queue.Put(&x)
+		err := m.queue.Put(x)
 		if err != nil {
 			return errors.Wrapf(err, "error pushing %f to queue", x)
 		}
@@ -99,7 +100,7 @@ func (m *Max) Value() (float64, error) {
 	defer m.mux.Unlock()
 
 	if m.count == 0 {
-		return 0, errors.New("no values seen yet")
+		return 0, ErrorNoValuesSeen
 	} else if m.window == 0 {
 		return m.max, nil
 	}
@@ -113,7 +114,6 @@ func (m *Max) Clear() {
 	defer m.mux.Unlock()
 	m.count = 0
 	m.max = math.Inf(-1)
-	m.queue.Dispose()
-	m.queue = queue.NewRingBuffer(uint64(m.window))
+	m.queue.Reset()
 	m.deque = deque.New[float64]()
 }
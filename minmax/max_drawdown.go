@@ -0,0 +1,111 @@
+package minmax
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/gammazero/deque"
+	"github.com/pkg/errors"
+)
+
+// MaxDrawdown keeps track of the maximum drawdown of a stream of returns, i.e. the
+// largest relative decline from a peak of the compounded wealth, expressed as a positive
+// fraction. The wealth starts at 1 before the first return (or the first return in the
+// window, if one is set), so a loss on the first return already counts as a drawdown.
+type MaxDrawdown struct {
+	window int
+	mux    sync.Mutex
+	// Used if window > 0
+	returns *deque.Deque[float64]
+	// Used if window == 0
+	wealth   float64
+	peak     float64
+	drawdown float64
+	count    int
+}
+
+// NewMaxDrawdown instantiates a MaxDrawdown struct.
+func NewMaxDrawdown(window int) (*MaxDrawdown, error) {
+	if window < 0 {
+		return nil, errors.Errorf("%d is a negative window", window)
+	}
+
+	return &MaxDrawdown{
+		window:  window,
+		returns: deque.New[float64](),
+		wealth:  1,
+		peak:    1,
+	}, nil
+}
+
+// NewGlobalMaxDrawdown instantiates a global MaxDrawdown struct.
+// This is equivalent to calling NewMaxDrawdown(0).
+func NewGlobalMaxDrawdown() *MaxDrawdown {
+	return &MaxDrawdown{
+		window:  0,
+		returns: deque.New[float64](),
+		wealth:  1,
+		peak:    1,
+	}
+}
+
+// String returns a string representation of the metric.
+func (m *MaxDrawdown) String() string {
+	name := "minmax.MaxDrawdown"
+	window := fmt.Sprintf("window:%v", m.window)
+	return fmt.Sprintf("%s_{%s}", name, window)
+}
+
+// Push adds a return for calculating the maximum drawdown.
+func (m *MaxDrawdown) Push(x float64) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.window != 0 {
+		if m.returns.Len() == m.window {
+			m.returns.PopFront()
+			m.count--
+		}
+		m.returns.PushBack(x)
+	} else {
+		m.wealth *= 1 + x
+		m.peak = math.Max(m.peak, m.wealth)
+		m.drawdown = math.Max(m.drawdown, 1-m.wealth/m.peak)
+	}
+
+	m.count++
+	return nil
+}
+
+// Value returns the value of the maximum drawdown. If a window
+// is set, this takes time linear in the size of the window.
+func (m *MaxDrawdown) Value() (float64, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.count == 0 {
+		return 0, ErrorNoValuesSeen
+	} else if m.window == 0 {
+		return m.drawdown, nil
+	}
+
+	wealth, peak, drawdown := 1., 1., 0.
+	for i := 0; i < m.returns.Len(); i++ {
+		wealth *= 1 + m.returns.At(i)
+		peak = math.Max(peak, wealth)
+		drawdown = math.Max(drawdown, 1-wealth/peak)
+	}
+	return drawdown, nil
+}
+
+// Clear resets the metric.
+func (m *MaxDrawdown) Clear() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.count = 0
+	m.wealth = 1
+	m.peak = 1
+	m.drawdown = 0
+	m.returns = deque.New[float64]()
+}
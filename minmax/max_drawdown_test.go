@@ -0,0 +1,115 @@
+package minmax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewMaxDrawdown(t *testing.T) {
+	t.Run("pass: valid MaxDrawdown is valid", func(t *testing.T) {
+		m, err := NewMaxDrawdown(3)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, m.window)
+		assert.Equal(t, 0, m.returns.Len())
+		assert.Equal(t, 1., m.wealth)
+		assert.Equal(t, 1., m.peak)
+		assert.Equal(t, 0., m.drawdown)
+		assert.Equal(t, 0, m.count)
+	})
+
+	t.Run("fail: negative window returns error", func(t *testing.T) {
+		_, err := NewMaxDrawdown(-1)
+		testutil.ContainsError(t, err, "-1 is a negative window")
+	})
+}
+
+func TestNewGlobalMaxDrawdown(t *testing.T) {
+	m, err := NewMaxDrawdown(0)
+	require.NoError(t, err)
+
+	globalM := NewGlobalMaxDrawdown()
+	assert.Equal(t, m, globalM)
+}
+
+func TestMaxDrawdownString(t *testing.T) {
+	expectedString := "minmax.MaxDrawdown_{window:3}"
+	m, err := NewMaxDrawdown(3)
+	require.NoError(t, err)
+	assert.Equal(t, expectedString, m.String())
+}
+
+func TestMaxDrawdownValue(t *testing.T) {
+	// wealth is 1.5, 0.75, 0.9, 0.45, 0.9
+	returns := []float64{0.5, -0.5, 0.2, -0.5, 1}
+
+	t.Run("pass: tracks maximum drawdown over a window", func(t *testing.T) {
+		m, err := NewMaxDrawdown(2)
+		require.NoError(t, err)
+
+		expected := []float64{0, 0.5, 0.5, 0.5, 0.5}
+		for i, x := range returns {
+			err := m.Push(x)
+			require.NoError(t, err)
+
+			value, err := m.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, expected[i], value)
+		}
+		assert.Equal(t, 2, m.returns.Len())
+	})
+
+	t.Run("pass: tracks maximum drawdown globally", func(t *testing.T) {
+		m := NewGlobalMaxDrawdown()
+
+		expected := []float64{0, 0.5, 0.5, 0.7, 0.7}
+		for i, x := range returns {
+			err := m.Push(x)
+			require.NoError(t, err)
+
+			value, err := m.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, expected[i], value)
+		}
+		assert.Equal(t, 0, m.returns.Len())
+	})
+
+	t.Run("pass: initial loss counts as a drawdown", func(t *testing.T) {
+		m, err := NewMaxDrawdown(3)
+		require.NoError(t, err)
+
+		err = m.Push(-0.25)
+		require.NoError(t, err)
+
+		value, err := m.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 0.25, value)
+	})
+
+	t.Run("fail: if no values seen, return error", func(t *testing.T) {
+		m, err := NewMaxDrawdown(3)
+		require.NoError(t, err)
+
+		_, err = m.Value()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+}
+
+func TestMaxDrawdownClear(t *testing.T) {
+	m := NewGlobalMaxDrawdown()
+	for _, x := range []float64{0.5, -0.5, 0.2} {
+		err := m.Push(x)
+		require.NoError(t, err)
+	}
+
+	m.Clear()
+	assert.Equal(t, 0, m.count)
+	assert.Equal(t, 1., m.wealth)
+	assert.Equal(t, 1., m.peak)
+	assert.Equal(t, 0., m.drawdown)
+	assert.Equal(t, 0, m.returns.Len())
+}
@@ -188,3 +188,27 @@ func TestMinClear(t *testing.T) {
 	assert.Equal(t, uint64(0), min.queue.Len())
 	assert.Equal(t, 0, min.deque.Len())
 }
+
+func TestMinWindowEviction(t *testing.T) {
+	min, err := NewMin(3)
+	require.NoError(t, err)
+
+	// the minimum changes both as new values arrive and as old ones are evicted,
+	// including when one of several equal values is evicted
+	xs := []float64{5, 1, 4, 1, 6, 7, 3, 8, 2, 9, 9, 0}
+	for i, x := range xs {
+		err := min.Push(x)
+		require.NoError(t, err)
+
+		expected := x
+		for j := i - 1; j >= 0 && j > i-3; j-- {
+			if xs[j] < expected {
+				expected = xs[j]
+			}
+		}
+
+		value, err := min.Value()
+		require.NoError(t, err)
+		assert.Equal(t, expected, value, "after pushing %d values", i+1)
+	}
+}
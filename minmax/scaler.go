@@ -0,0 +1,129 @@
+package minmax
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Scaler keeps track of the minimum and maximum of a stream, for min-max
+// scaling values to their position within the observed range, i.e.
+// (x - min) / (max - min). If min == max (e.g. after a single value),
+// every value is scaled to 0.5, the midpoint of the degenerate range.
+type Scaler struct {
+	mux   sync.Mutex
+	min   *Min
+	max   *Max
+	last  float64
+	count int
+}
+
+// NewScaler instantiates a Scaler struct.
+func NewScaler(window int) (*Scaler, error) {
+	min, err := NewMin(window)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Min")
+	}
+
+	max, err := NewMax(window)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Max")
+	}
+
+	return &Scaler{
+		min: min,
+		max: max,
+	}, nil
+}
+
+// NewGlobalScaler instantiates a global Scaler struct.
+// This is equivalent to calling NewScaler(0).
+func NewGlobalScaler() *Scaler {
+	return &Scaler{
+		min: NewGlobalMin(),
+		max: NewGlobalMax(),
+	}
+}
+
+// String returns a string representation of the metric.
+func (s *Scaler) String() string {
+	name := "minmax.Scaler"
+	window := fmt.Sprintf("window:%v", s.min.window)
+	return fmt.Sprintf("%s_{%s}", name, window)
+}
+
+// Push adds a number for calculating the minimum and maximum.
+func (s *Scaler) Push(x float64) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	err := s.min.Push(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to Min")
+	}
+
+	err = s.max.Push(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to Max")
+	}
+
+	s.last = x
+	s.count++
+	return nil
+}
+
+// Value returns the scaled position of the last value pushed
+// within the range of the values in the window.
+func (s *Scaler) Value() (float64, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.count == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	return s.scale(s.last)
+}
+
+// Scale returns the position of x within the range of the values in the window,
+// i.e. (x - min) / (max - min). Values outside of the range are scaled to values
+// outside of [0, 1]; if min == max, every value is scaled to 0.5.
+func (s *Scaler) Scale(x float64) (float64, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.count == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	return s.scale(x)
+}
+
+func (s *Scaler) scale(x float64) (float64, error) {
+	min, err := s.min.Value()
+	if err != nil {
+		return 0, errors.Wrap(err, "error retrieving min")
+	}
+
+	max, err := s.max.Value()
+	if err != nil {
+		return 0, errors.Wrap(err, "error retrieving max")
+	}
+
+	if max == min {
+		return 0.5, nil
+	}
+
+	return (x - min) / (max - min), nil
+}
+
+// Clear resets the metric.
+func (s *Scaler) Clear() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.min.Clear()
+	s.max.Clear()
+	s.last = 0
+	s.count = 0
+}
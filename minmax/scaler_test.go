@@ -0,0 +1,118 @@
+package minmax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewScaler(t *testing.T) {
+	t.Run("pass: valid Scaler is valid", func(t *testing.T) {
+		scaler, err := NewScaler(3)
+		require.NoError(t, err)
+		assert.Equal(t, 3, scaler.min.window)
+		assert.Equal(t, 3, scaler.max.window)
+		assert.Equal(t, 0, scaler.count)
+	})
+
+	t.Run("fail: negative window returns error", func(t *testing.T) {
+		_, err := NewScaler(-1)
+		testutil.ContainsError(t, err, "-1 is a negative window")
+	})
+}
+
+func TestNewGlobalScaler(t *testing.T) {
+	scaler, err := NewScaler(0)
+	require.NoError(t, err)
+	assert.Equal(t, scaler, NewGlobalScaler())
+}
+
+func TestScalerString(t *testing.T) {
+	scaler, err := NewScaler(3)
+	require.NoError(t, err)
+	assert.Equal(t, "minmax.Scaler_{window:3}", scaler.String())
+}
+
+func TestScalerValue(t *testing.T) {
+	t.Run("pass: ramp is scaled to the top of the range", func(t *testing.T) {
+		scaler := NewGlobalScaler()
+		for i := 0.; i < 10; i++ {
+			require.NoError(t, scaler.Push(i))
+			if i == 0 {
+				continue
+			}
+			val, err := scaler.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, 1, val)
+		}
+
+		for i, expected := range []float64{0, 1. / 9, 0.5, 1, -1. / 9, 2} {
+			val, err := scaler.Scale([]float64{0, 1, 4.5, 9, -1, 18}[i])
+			require.NoError(t, err)
+			testutil.Approx(t, expected, val)
+		}
+	})
+
+	t.Run("pass: global max is evicted from the window", func(t *testing.T) {
+		scaler, err := NewScaler(3)
+		require.NoError(t, err)
+
+		xs := []float64{10, 0, 5, 2, 4, 1}
+		// windows: [10], [10 0], [10 0 5], [0 5 2], [5 2 4], [2 4 1]
+		expected := []float64{0.5, 0, 0.5, 0.4, 2. / 3, 0}
+		for i, x := range xs {
+			require.NoError(t, scaler.Push(x))
+			val, err := scaler.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, expected[i], val, "index %d", i)
+		}
+
+		// once 10 is evicted, 10 is outside of the range [1, 4]
+		val, err := scaler.Scale(10)
+		require.NoError(t, err)
+		testutil.Approx(t, 3, val)
+	})
+
+	t.Run("pass: equal min and max scale to 0.5", func(t *testing.T) {
+		scaler := NewGlobalScaler()
+		for i := 0; i < 3; i++ {
+			require.NoError(t, scaler.Push(2))
+		}
+
+		val, err := scaler.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, val)
+
+		val, err = scaler.Scale(100)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, val)
+	})
+
+	t.Run("fail: no values seen", func(t *testing.T) {
+		scaler := NewGlobalScaler()
+		_, err := scaler.Value()
+		assert.EqualError(t, err, "no values seen yet")
+		_, err = scaler.Scale(1)
+		assert.EqualError(t, err, "no values seen yet")
+	})
+}
+
+func TestScalerClear(t *testing.T) {
+	scaler, err := NewScaler(3)
+	require.NoError(t, err)
+	for i := 0.; i < 3; i++ {
+		require.NoError(t, scaler.Push(i))
+	}
+
+	scaler.Clear()
+	assert.Equal(t, 0, scaler.count)
+	assert.Equal(t, 0., scaler.last)
+	assert.Equal(t, 0, scaler.min.count)
+	assert.Equal(t, 0, scaler.max.count)
+
+	_, err = scaler.Value()
+	assert.EqualError(t, err, "no values seen yet")
+}
@@ -0,0 +1,131 @@
+package moment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream/minmax"
+)
+
+// Calmar is a metric that tracks the Calmar ratio of a stream of returns, i.e.
+// the annualized mean return divided by the maximum drawdown. The annualization
+// factor is the number of return periods per year (e.g. 252 for daily returns).
+type Calmar struct {
+	annualization float64
+	window        int
+	core          *Core
+	drawdown      *minmax.MaxDrawdown
+}
+
+// NewCalmar instantiates a Calmar struct.
+func NewCalmar(annualization float64, window int) (*Calmar, error) {
+	if annualization <= 0 {
+		return nil, errors.Errorf("%f is a nonpositive annualization factor", annualization)
+	}
+
+	drawdown, err := minmax.NewMaxDrawdown(window)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating MaxDrawdown")
+	}
+
+	return &Calmar{
+		annualization: annualization,
+		window:        window,
+		drawdown:      drawdown,
+	}, nil
+}
+
+// NewGlobalCalmar instantiates a global Calmar struct.
+// This is equivalent to calling NewCalmar(annualization, 0).
+func NewGlobalCalmar(annualization float64) (*Calmar, error) {
+	return NewCalmar(annualization, 0)
+}
+
+// SetCore sets the Core.
+func (c *Calmar) SetCore(core *Core) {
+	c.core = core
+}
+
+// IsSetCore returns if the core has been set.
+func (c *Calmar) IsSetCore() bool {
+	return c.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (c *Calmar) Config() *CoreConfig {
+	return &CoreConfig{
+		Window: &c.window,
+	}
+}
+
+// String returns a string representation of the metric.
+func (c *Calmar) String() string {
+	name := "moment.Calmar"
+	params := []string{
+		fmt.Sprintf("annualization:%v", c.annualization),
+		fmt.Sprintf("window:%v", c.window),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a new return for Calmar to consume.
+func (c *Calmar) Push(x float64) error {
+	if !c.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	// hold the lock on the shared core across both pushes,
+	// so that Value never sees one metric updated without the other
+	c.core.Lock()
+	defer c.core.Unlock()
+
+	err := c.core.UnsafePush(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+
+	err = c.drawdown.Push(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to MaxDrawdown")
+	}
+	return nil
+}
+
+// Value returns the value of the Calmar ratio. If a window is set,
+// this takes time linear in the size of the window.
+func (c *Calmar) Value() (float64, error) {
+	if !c.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	c.core.RLock()
+	defer c.core.RUnlock()
+
+	mean, err := c.core.UnsafeMean()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving mean: %w", err)
+	}
+
+	drawdown, err := c.drawdown.Value()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving maximum drawdown: %w", err)
+	}
+
+	if drawdown == 0 {
+		return 0, errors.New("maximum drawdown is zero")
+	}
+
+	return mean * c.annualization / drawdown, nil
+}
+
+// Clear resets the metric.
+func (c *Calmar) Clear() {
+	if c.IsSetCore() {
+		c.core.Lock()
+		defer c.core.Unlock()
+		c.core.UnsafeClear()
+	}
+	c.drawdown.Clear()
+}
@@ -0,0 +1,200 @@
+package moment
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/K4Mobility/stream/minmax"
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewCalmar(t *testing.T) {
+	t.Run("pass: valid Calmar is valid", func(t *testing.T) {
+		c, err := NewCalmar(252, 3)
+		require.NoError(t, err)
+
+		drawdown, err := minmax.NewMaxDrawdown(3)
+		require.NoError(t, err)
+
+		assert.Equal(t, 252., c.annualization)
+		assert.Equal(t, 3, c.window)
+		assert.Equal(t, drawdown, c.drawdown)
+		assert.Nil(t, c.core)
+	})
+
+	t.Run("fail: nonpositive annualization is invalid", func(t *testing.T) {
+		_, err := NewCalmar(0, 3)
+		testutil.ContainsError(t, err, fmt.Sprintf("%f is a nonpositive annualization factor", 0.))
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewCalmar(252, -1)
+		testutil.ContainsError(t, err, "error creating MaxDrawdown")
+	})
+}
+
+func TestNewGlobalCalmar(t *testing.T) {
+	c, err := NewCalmar(252, 0)
+	require.NoError(t, err)
+
+	globalC, err := NewGlobalCalmar(252)
+	require.NoError(t, err)
+
+	assert.Equal(t, c, globalC)
+}
+
+type CalmarPushSuite struct {
+	suite.Suite
+	c *Calmar
+}
+
+func TestCalmarPushSuite(t *testing.T) {
+	suite.Run(t, &CalmarPushSuite{})
+}
+
+func (s *CalmarPushSuite) SetupTest() {
+	var err error
+	s.c, err = NewCalmar(12, 3)
+	s.Require().NoError(err)
+	err = Init(s.c)
+	s.Require().NoError(err)
+}
+
+func (s *CalmarPushSuite) TestPushSuccess() {
+	err := s.c.Push(-0.5)
+	s.Require().NoError(err)
+
+	mean, err := s.c.core.Mean()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), -0.5, mean)
+
+	drawdown, err := s.c.drawdown.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 0.5, drawdown)
+}
+
+func (s *CalmarPushSuite) TestPushFailOnNullCore() {
+	c, err := NewCalmar(12, 3)
+	s.Require().NoError(err)
+
+	err = c.Push(0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *CalmarPushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.c.core.queue.Dispose()
+
+	err := s.c.Push(0.1)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+type CalmarValueSuite struct {
+	suite.Suite
+	c       *Calmar
+	globalC *Calmar
+}
+
+func TestCalmarValueSuite(t *testing.T) {
+	suite.Run(t, &CalmarValueSuite{})
+}
+
+func (s *CalmarValueSuite) SetupTest() {
+	var err error
+	s.c, err = NewCalmar(12, 3)
+	s.Require().NoError(err)
+	err = Init(s.c)
+	s.Require().NoError(err)
+
+	s.globalC, err = NewGlobalCalmar(12)
+	s.Require().NoError(err)
+	err = Init(s.globalC)
+	s.Require().NoError(err)
+
+	// wealth is 1.5, 0.75, 0.9, 0.45, 0.9
+	xs := []float64{0.5, -0.5, 0.2, -0.5, 1}
+	for _, x := range xs {
+		err := s.c.Push(x)
+		s.Require().NoError(err)
+
+		err = s.globalC.Push(x)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *CalmarValueSuite) TestValueSuccess() {
+	// window is {0.2, -0.5, 1}, wealth is 1.2, 0.6, 1.2
+	value, err := s.c.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), (0.7/3.)*12./0.5, value)
+}
+
+func (s *CalmarValueSuite) TestValueSuccessGlobal() {
+	value, err := s.globalC.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), (0.7/5.)*12./0.7, value)
+}
+
+func (s *CalmarValueSuite) TestValueFailOnNullCore() {
+	c, err := NewCalmar(12, 3)
+	s.Require().NoError(err)
+
+	_, err = c.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *CalmarValueSuite) TestValueFailIfNoValuesSeen() {
+	c, err := NewCalmar(12, 3)
+	s.Require().NoError(err)
+	err = Init(c)
+	s.Require().NoError(err)
+
+	_, err = c.Value()
+	testutil.ContainsError(s.T(), err, "no values seen yet")
+}
+
+func (s *CalmarValueSuite) TestValueFailIfMaxDrawdownIsZero() {
+	c, err := NewCalmar(12, 3)
+	s.Require().NoError(err)
+	err = Init(c)
+	s.Require().NoError(err)
+
+	for _, x := range []float64{0.1, 0, 0.2} {
+		err := c.Push(x)
+		s.Require().NoError(err)
+	}
+
+	_, err = c.Value()
+	testutil.ContainsError(s.T(), err, "maximum drawdown is zero")
+}
+
+func TestCalmarClear(t *testing.T) {
+	c, err := NewCalmar(12, 3)
+	require.NoError(t, err)
+	err = Init(c)
+	require.NoError(t, err)
+
+	for _, x := range []float64{0.5, -0.5, 0.2, -0.5, 1} {
+		err := c.Push(x)
+		require.NoError(t, err)
+	}
+
+	c.Clear()
+	assert.Equal(t, int(0), c.core.count)
+	assert.Equal(t, uint64(0), c.core.queue.Len())
+
+	_, err = c.drawdown.Value()
+	testutil.ContainsError(t, err, "no values seen yet")
+}
+
+func TestCalmarString(t *testing.T) {
+	c, err := NewCalmar(252, 3)
+	require.NoError(t, err)
+
+	expectedString := "moment.Calmar_{annualization:252,window:3}"
+	assert.Equal(t, expectedString, c.String())
+}
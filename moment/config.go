@@ -1,21 +1,41 @@
 package moment
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream"
 )
 
 // CoreConfig is the struct containing configuration options for
 // instantiating a Core object.
 type CoreConfig struct {
 	Sums   SumsConfig // sums tracked must be positive
-	Window *int       // must be 0 if decay is set, must be nonnegative in general
-	Decay  *float64   // optional, must lie in the interval (0, 1)
+	Window *int       // must be 0 if duration is set, must be nonnegative in general
+	// optional, must lie in the interval (0, 1); with a window, the values in the
+	// window are weighted in proportion to (1-decay)^age, and their weights sum to 1
+	Decay    *float64
+	Duration *time.Duration // optional, must be positive; tracks a time window instead of a count window
+	// optional, defaults to stream.NonFiniteError; configures how non-finite values pushed are handled
+	NonFinite *stream.NonFiniteMode
+	// optional, seeds the Core with the state of values already consumed (e.g. by
+	// a previous aggregation); must be nonnegative and cannot be set with a window
+	InitialCount *int
+	// optional, the mean of the values already consumed; requires InitialCount, and is
+	// required if InitialCount is positive
+	InitialMean *float64
+	// optional, the centralized sums of the values already consumed, keyed by order;
+	// requires InitialCount, and must have every order from 2 up to the largest one in Sums
+	InitialSums map[int]float64
 }
 
 var defaultConfig = &CoreConfig{
-	Sums:   map[int]bool{},
-	Window: nil,
-	Decay:  nil,
+	Sums:      map[int]bool{},
+	Window:    nil,
+	Decay:     nil,
+	Duration:  nil,
+	NonFinite: nil,
 }
 
 // SumsConfig is an alias for a map of ints to bools; this configures
@@ -28,6 +48,20 @@ func (s1 SumsConfig) add(s2 SumsConfig) {
 	}
 }
 
+// Orders returns a SumsConfig tracking the centralized power sums of the given
+// orders, e.g. Orders(2, 4) for the sums needed by Kurtosis; repeated orders are
+// tracked once. It returns an error if any order is not positive.
+func Orders(ks ...int) (SumsConfig, error) {
+	sums := SumsConfig{}
+	for _, k := range ks {
+		if k <= 0 {
+			return nil, errors.Errorf("order %d is not positive", k)
+		}
+		sums[k] = true
+	}
+	return sums, nil
+}
+
 // MergeConfigs merges CoreConfig objects.
 func MergeConfigs(configs ...*CoreConfig) (*CoreConfig, error) {
 	switch len(configs) {
@@ -37,14 +71,20 @@ func MergeConfigs(configs ...*CoreConfig) (*CoreConfig, error) {
 		return configs[0], nil
 	default:
 		var (
-			window *int
-			decay  *float64
+			window    *int
+			decay     *float64
+			duration  *time.Duration
+			nonFinite *stream.NonFiniteMode
 		)
 		mergedConfig := &CoreConfig{
 			Sums: SumsConfig{},
 		}
 
 		for _, config := range configs {
+			if config.InitialCount != nil || config.InitialMean != nil || config.InitialSums != nil {
+				return nil, errors.New("cannot merge configs with an initial state")
+			}
+
 			if config.Sums != nil {
 				mergedConfig.Sums.add(config.Sums)
 			}
@@ -64,10 +104,28 @@ func MergeConfigs(configs ...*CoreConfig) (*CoreConfig, error) {
 					return nil, errors.New("configs have differing decays")
 				}
 			}
+
+			if config.Duration != nil {
+				if duration == nil {
+					duration = config.Duration
+				} else if *duration != *config.Duration {
+					return nil, errors.New("configs have differing durations")
+				}
+			}
+
+			if config.NonFinite != nil {
+				if nonFinite == nil {
+					nonFinite = config.NonFinite
+				} else if *nonFinite != *config.NonFinite {
+					return nil, errors.New("configs have differing non-finite modes")
+				}
+			}
 		}
 
 		mergedConfig.Window = window
 		mergedConfig.Decay = decay
+		mergedConfig.Duration = duration
+		mergedConfig.NonFinite = nonFinite
 		return mergedConfig, nil
 	}
 }
@@ -80,10 +138,26 @@ func validateConfig(config *CoreConfig) error {
 	}
 
 	if config.Decay != nil {
-		if *config.Decay <= 0 || *config.Decay >= 1 {
+		if !(*config.Decay > 0 && *config.Decay < 1) {
 			return errors.Errorf("config has a decay of %f, which is not in (0, 1)", *config.Decay)
+		}
+	}
+
+	if config.Duration != nil {
+		if *config.Duration <= 0 {
+			return errors.Errorf("config has a nonpositive duration of %v", *config.Duration)
 		} else if *config.Window > 0 {
-			return errors.New("config cannot have Decay set with a nonzero window")
+			return errors.New("config cannot have Duration set with a nonzero window")
+		} else if config.Decay != nil {
+			return errors.New("config cannot have both Decay and Duration set")
+		}
+	}
+
+	if config.NonFinite != nil {
+		switch *config.NonFinite {
+		case stream.NonFiniteError, stream.NonFiniteSkip, stream.NonFinitePropagate:
+		default:
+			return errors.Errorf("config has an unknown non-finite mode of %d", *config.NonFinite)
 		}
 	}
 
@@ -93,6 +167,51 @@ func validateConfig(config *CoreConfig) error {
 		}
 	}
 
+	return validateInitialState(config)
+}
+
+func validateInitialState(config *CoreConfig) error {
+	if config.InitialCount == nil {
+		if config.InitialMean != nil || config.InitialSums != nil {
+			return errors.New("config cannot have InitialMean or InitialSums set without InitialCount")
+		}
+		return nil
+	}
+
+	if *config.InitialCount < 0 {
+		return errors.Errorf("config has a negative initial count of %d", *config.InitialCount)
+	} else if *config.Window > 0 || config.Duration != nil {
+		// the values consumed are unknown, so they could never be evicted
+		return errors.New("config cannot have InitialCount set with a window")
+	}
+
+	if *config.InitialCount == 0 {
+		if (config.InitialMean != nil && *config.InitialMean != 0) || len(config.InitialSums) > 0 {
+			return errors.New("config cannot have an initial mean or sums with an initial count of 0")
+		}
+		return nil
+	} else if config.InitialMean == nil {
+		return errors.Errorf("config is missing an initial mean for an initial count of %d", *config.InitialCount)
+	}
+
+	maxSum := 0
+	for k := range config.Sums {
+		if k > maxSum {
+			maxSum = k
+		}
+	}
+	for k := range config.InitialSums {
+		if k < 2 || k > maxSum {
+			return errors.Errorf("config has an initial sum of order %d, which is not tracked", k)
+		}
+	}
+	// the update formulas for each sum depend on all of the lower ones
+	for k := 2; k <= maxSum; k++ {
+		if _, ok := config.InitialSums[k]; !ok {
+			return errors.Errorf("config is missing an initial sum of order %d", k)
+		}
+	}
+
 	return nil
 }
 
@@ -109,5 +228,13 @@ func setConfigDefaults(config *CoreConfig) *CoreConfig {
 		config.Decay = defaultConfig.Decay
 	}
 
+	if config.Duration == nil {
+		config.Duration = defaultConfig.Duration
+	}
+
+	if config.NonFinite == nil {
+		config.NonFinite = defaultConfig.NonFinite
+	}
+
 	return config
 }
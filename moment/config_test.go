@@ -2,7 +2,9 @@ package moment
 
 import (
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -33,15 +35,55 @@ func TestValidateConfig(t *testing.T) {
 		}
 		err = validateConfig(config)
 		assert.EqualError(t, err, fmt.Sprintf("config has a decay of %f, which is not in (0, 1)", 1.))
+
+		for _, decay := range []float64{-0.2, 1.5, math.NaN()} {
+			config = &CoreConfig{
+				Window: stream.IntPtr(3),
+				Decay:  stream.FloatPtr(decay),
+			}
+			err = validateConfig(config)
+			assert.EqualError(t, err, fmt.Sprintf("config has a decay of %f, which is not in (0, 1)", decay))
+		}
 	})
 
-	t.Run("fail: config with a set decay and nonzero window is invalid", func(t *testing.T) {
+	t.Run("pass: config with a set decay and nonzero window is valid", func(t *testing.T) {
 		config := &CoreConfig{
 			Window: stream.IntPtr(3),
 			Decay:  stream.FloatPtr(0.3),
 		}
 		err := validateConfig(config)
-		assert.EqualError(t, err, "config cannot have Decay set with a nonzero window")
+		assert.NoError(t, err)
+	})
+
+	t.Run("fail: config with a nonpositive duration is invalid", func(t *testing.T) {
+		duration := time.Duration(0)
+		config := &CoreConfig{
+			Window:   stream.IntPtr(0),
+			Duration: &duration,
+		}
+		err := validateConfig(config)
+		assert.EqualError(t, err, fmt.Sprintf("config has a nonpositive duration of %v", duration))
+	})
+
+	t.Run("fail: config with a set duration and nonzero window is invalid", func(t *testing.T) {
+		duration := time.Minute
+		config := &CoreConfig{
+			Window:   stream.IntPtr(3),
+			Duration: &duration,
+		}
+		err := validateConfig(config)
+		assert.EqualError(t, err, "config cannot have Duration set with a nonzero window")
+	})
+
+	t.Run("fail: config with a set duration and decay is invalid", func(t *testing.T) {
+		duration := time.Minute
+		config := &CoreConfig{
+			Window:   stream.IntPtr(0),
+			Decay:    stream.FloatPtr(0.3),
+			Duration: &duration,
+		}
+		err := validateConfig(config)
+		assert.EqualError(t, err, "config cannot have both Decay and Duration set")
 	})
 
 	t.Run("fail: config with a nonpositive central moment is invalid", func(t *testing.T) {
@@ -75,6 +117,86 @@ func TestValidateConfig(t *testing.T) {
 		err = validateConfig(config)
 		assert.NoError(t, err)
 	})
+
+	t.Run("fail: config with an unknown non-finite mode is invalid", func(t *testing.T) {
+		config := &CoreConfig{
+			Window:    stream.IntPtr(3),
+			NonFinite: stream.NonFiniteModePtr(3),
+		}
+		err := validateConfig(config)
+		assert.EqualError(t, err, "config has an unknown non-finite mode of 3")
+	})
+
+	t.Run("fail: config with an inconsistent initial state is invalid", func(t *testing.T) {
+		config := &CoreConfig{
+			Sums:        SumsConfig{2: true},
+			Window:      stream.IntPtr(0),
+			InitialMean: stream.FloatPtr(1),
+		}
+		err := validateConfig(config)
+		assert.EqualError(t, err, "config cannot have InitialMean or InitialSums set without InitialCount")
+
+		config = &CoreConfig{
+			Sums:         SumsConfig{2: true},
+			Window:       stream.IntPtr(0),
+			InitialCount: stream.IntPtr(-1),
+		}
+		err = validateConfig(config)
+		assert.EqualError(t, err, "config has a negative initial count of -1")
+
+		config = &CoreConfig{
+			Sums:         SumsConfig{2: true},
+			Window:       stream.IntPtr(3),
+			InitialCount: stream.IntPtr(2),
+			InitialSums:  map[int]float64{2: 1},
+		}
+		err = validateConfig(config)
+		assert.EqualError(t, err, "config cannot have InitialCount set with a window")
+
+		config = &CoreConfig{
+			Sums:         SumsConfig{2: true},
+			Window:       stream.IntPtr(0),
+			InitialCount: stream.IntPtr(0),
+			InitialMean:  stream.FloatPtr(1),
+		}
+		err = validateConfig(config)
+		assert.EqualError(t, err, "config cannot have an initial mean or sums with an initial count of 0")
+
+		config = &CoreConfig{
+			Sums:         SumsConfig{2: true},
+			Window:       stream.IntPtr(0),
+			InitialCount: stream.IntPtr(2),
+			InitialSums:  map[int]float64{2: 1},
+		}
+		err = validateConfig(config)
+		assert.EqualError(t, err, "config is missing an initial mean for an initial count of 2")
+
+		config = &CoreConfig{
+			Sums:         SumsConfig{2: true, 4: true},
+			Window:       stream.IntPtr(0),
+			InitialCount: stream.IntPtr(2),
+			InitialMean:  stream.FloatPtr(1),
+			InitialSums:  map[int]float64{2: 1, 4: 1},
+		}
+		err = validateConfig(config)
+		assert.EqualError(t, err, "config is missing an initial sum of order 3")
+
+		config.InitialSums = map[int]float64{2: 1, 3: 0, 4: 1, 5: 0}
+		err = validateConfig(config)
+		assert.EqualError(t, err, "config has an initial sum of order 5, which is not tracked")
+	})
+
+	t.Run("pass: config with a consistent initial state is valid", func(t *testing.T) {
+		config := &CoreConfig{
+			Sums:         SumsConfig{2: true, 4: true},
+			Window:       stream.IntPtr(0),
+			InitialCount: stream.IntPtr(2),
+			InitialMean:  stream.FloatPtr(1.5),
+			InitialSums:  map[int]float64{2: 0.5, 3: 0, 4: 0.125},
+		}
+		err := validateConfig(config)
+		assert.NoError(t, err)
+	})
 }
 
 func TestSetConfigDefaults(t *testing.T) {
@@ -105,6 +227,36 @@ func TestSetConfigDefaults(t *testing.T) {
 	})
 }
 
+func TestOrders(t *testing.T) {
+	t.Run("pass: matches the sums used by metrics", func(t *testing.T) {
+		sums, err := Orders(2, 4)
+		require.NoError(t, err)
+		assert.Equal(t, NewKurtosis(3).Config().Sums, sums)
+
+		sums, err = Orders(3)
+		require.NoError(t, err)
+		assert.Equal(t, New(3, 5).Config().Sums, sums)
+	})
+
+	t.Run("pass: repeated orders are tracked once", func(t *testing.T) {
+		sums, err := Orders(4, 2, 4, 2)
+		require.NoError(t, err)
+		assert.Equal(t, SumsConfig{2: true, 4: true}, sums)
+
+		sums, err = Orders()
+		require.NoError(t, err)
+		assert.Equal(t, SumsConfig{}, sums)
+	})
+
+	t.Run("fail: nonpositive orders are invalid", func(t *testing.T) {
+		_, err := Orders(2, 0)
+		assert.EqualError(t, err, "order 0 is not positive")
+
+		_, err = Orders(-1)
+		assert.EqualError(t, err, "order -1 is not positive")
+	})
+}
+
 func TestMergeConfigs(t *testing.T) {
 	t.Run("fail: no configs passed is invalid", func(t *testing.T) {
 		_, err := MergeConfigs()
@@ -178,4 +330,82 @@ func TestMergeConfigs(t *testing.T) {
 		_, err := MergeConfigs(config1, config2)
 		assert.EqualError(t, err, "configs have differing decays")
 	})
+
+	t.Run("fail: multiple configs passed fails if durations are not compatible", func(t *testing.T) {
+		duration1, duration2 := time.Minute, time.Hour
+		config1 := &CoreConfig{
+			Sums:     SumsConfig{1: true, 2: true},
+			Window:   stream.IntPtr(0),
+			Duration: &duration1,
+		}
+		config2 := &CoreConfig{
+			Sums:     SumsConfig{2: true, 3: true},
+			Window:   stream.IntPtr(0),
+			Duration: &duration2,
+		}
+
+		_, err := MergeConfigs(config1, config2)
+		assert.EqualError(t, err, "configs have differing durations")
+	})
+
+	t.Run("fail: multiple configs passed fails if non-finite modes are not compatible", func(t *testing.T) {
+		config1 := &CoreConfig{
+			NonFinite: stream.NonFiniteModePtr(stream.NonFiniteSkip),
+		}
+		config2 := &CoreConfig{
+			NonFinite: stream.NonFiniteModePtr(stream.NonFinitePropagate),
+		}
+
+		_, err := MergeConfigs(config1, config2)
+		assert.EqualError(t, err, "configs have differing non-finite modes")
+
+		config3 := &CoreConfig{}
+		mergedConfig, err := MergeConfigs(config1, config3)
+		require.NoError(t, err)
+		assert.Equal(t, stream.NonFiniteSkip, *mergedConfig.NonFinite)
+	})
+
+	t.Run("fail: multiple configs passed fails if any has an initial state", func(t *testing.T) {
+		config1 := &CoreConfig{
+			Sums:         SumsConfig{2: true},
+			InitialCount: stream.IntPtr(1),
+		}
+		config2 := &CoreConfig{
+			Sums: SumsConfig{3: true},
+		}
+
+		_, err := MergeConfigs(config1, config2)
+		assert.EqualError(t, err, "cannot merge configs with an initial state")
+	})
+}
+
+func TestMergeMetricConfigs(t *testing.T) {
+	t.Run("pass: metrics with the same window merge", func(t *testing.T) {
+		config, err := MergeConfigs(
+			NewKurtosis(3).Config(),
+			NewSkewness(3).Config(),
+			NewStandardizedMoment(5, 3).Config(),
+		)
+		require.NoError(t, err)
+
+		expected := &CoreConfig{
+			Sums:   SumsConfig{2: true, 3: true, 4: true, 5: true},
+			Window: stream.IntPtr(3),
+		}
+		assert.Equal(t, expected, config)
+	})
+
+	t.Run("fail: metrics with differing windows conflict", func(t *testing.T) {
+		_, err := MergeConfigs(NewKurtosis(3).Config(), NewSkewness(5).Config())
+		assert.EqualError(t, err, "configs have differing windows")
+
+		// a global metric has a window of 0, which conflicts with a rolling window
+		_, err = MergeConfigs(NewGlobalKurtosis().Config(), NewSkewness(3).Config())
+		assert.EqualError(t, err, "configs have differing windows")
+	})
+
+	t.Run("fail: metrics with differing decays conflict", func(t *testing.T) {
+		_, err := MergeConfigs(NewEWMMoment(2, 0.3).Config(), NewEWMMoment(4, 0.5).Config())
+		assert.EqualError(t, err, "configs have differing decays")
+	})
 }
@@ -1,24 +1,45 @@
 package moment
 
 import (
+	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/Workiva/go-datastructures/queue"
+	"github.com/gammazero/deque"
 	"github.com/pkg/errors"
 
+	"github.com/K4Mobility/stream"
 	mathutil "github.com/K4Mobility/stream/util/math"
+	"github.com/K4Mobility/stream/util/ring"
 )
 
 // Core is a struct that stores fundamental information for moments of a stream.
 type Core struct {
-	mux    sync.RWMutex
-	mean   float64
-	sums   []float64
-	count  int
-	window int
-	decay  *float64
-	queue  *queue.RingBuffer
+	// copies of count and mean (the latter as its IEEE 754 bits), published
+	// atomically after every update so that Count and Mean need not lock;
+	// these are first in the struct so that they are 64-bit aligned
+	atomicCount int64
+	atomicMean  uint64
+	mux         sync.RWMutex
+	mean        float64
+	// the sum of the values, tracked directly (with compensated summation,
+	// whose running compensation is totalComp) rather than as mean*weight
+	total     float64
+	totalComp float64
+	sums      []float64
+	count     int
+	weight    float64
+	window    int
+	decay     *float64
+	queue     ring.Queue[float64]
+	// How non-finite values pushed are handled
+	nonFinite stream.NonFiniteMode
+	// Used if duration > 0
+	duration time.Duration
+	timed    *deque.Deque[timedValue]
+	latest   time.Time
 }
 
 // Init sets a CoreWrapper up with a core for consuming.
@@ -48,6 +69,12 @@ func NewCore(config *CoreConfig) (*Core, error) {
 	c := &Core{}
 	c.window = *config.Window
 	c.decay = config.Decay
+	if config.Duration != nil {
+		c.duration = *config.Duration
+	}
+	if config.NonFinite != nil {
+		c.nonFinite = *config.NonFinite
+	}
 
 	maxSum := -1
 	for k := range config.Sums {
@@ -57,7 +84,21 @@ func NewCore(config *CoreConfig) (*Core, error) {
 	}
 	c.sums = make([]float64, maxSum+1)
 
-	c.queue = queue.NewRingBuffer(uint64(c.window))
+	if config.InitialCount != nil {
+		c.count = *config.InitialCount
+		c.weight = float64(c.count)
+		if config.InitialMean != nil {
+			c.mean = *config.InitialMean
+			c.total = c.mean * c.weight
+		}
+		for k, sum := range config.InitialSums {
+			c.sums[k] = sum
+		}
+	}
+
+	c.queue = ring.New[float64](uint64(c.window))
+	c.timed = deque.New[timedValue]()
+	c.publish()
 
 	return c, nil
 }
@@ -69,10 +110,37 @@ func (c *Core) Push(x float64) error {
 	return c.UnsafePush(x)
 }
 
+// PushBatch adds a batch of values for a Core object to consume, in order.
+// This is equivalent to calling Push on each value, but only locks once.
+// If an error occurs, the values preceding the failing one remain consumed.
+func (c *Core) PushBatch(xs []float64) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for _, x := range xs {
+		err := c.UnsafePush(x)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // UnsafePush adds a new value for a Core object to consume,
 // but does not lock. This should only be used if the user
 // plans to make use of the Lock()/Unlock() Core methods.
 func (c *Core) UnsafePush(x float64) error {
+	if c.duration != 0 {
+		return errors.New("values must be pushed with PushAt to a Core with a time window")
+	}
+
+	// check the value before any state is mutated
+	ok, err := c.nonFinite.Admit(x)
+	if err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
 	if c.window != 0 {
 		if c.queue.Len() == uint64(c.window) {
 			tail, err := c.queue.Get()
@@ -80,7 +148,11 @@ func (c *Core) UnsafePush(x float64) error {
 				return ErrorPoppingQueue
 			}
 
-			c.remove(tail.(float64))
+			if c.decay == nil {
+				c.remove(tail)
+			} else {
+				c.removeDecay(tail)
+			}
 		}
 
 		err := c.queue.Put(x)
@@ -97,6 +169,40 @@ func (c *Core) UnsafePush(x float64) error {
 	return nil
 }
 
+// PushWeighted adds a new value with a positive weight for a Core object to consume;
+// pushing a value with an integral weight w is equivalent to pushing it w times.
+// Weighted values cannot be consumed by Cores with a window or decay set.
+func (c *Core) PushWeighted(x float64, w float64) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.UnsafePushWeighted(x, w)
+}
+
+// UnsafePushWeighted adds a new value with a positive weight for a Core object
+// to consume, but does not lock. This should only be used if the user
+// plans to make use of the Lock()/Unlock() Core methods.
+func (c *Core) UnsafePushWeighted(x float64, w float64) error {
+	if w <= 0 {
+		return errors.Errorf("%f is a nonpositive weight", w)
+	}
+	if c.window != 0 || c.duration != 0 {
+		return errors.New("cannot push weighted values to a Core with a window")
+	}
+	if c.decay != nil {
+		return errors.New("cannot push weighted values to a Core with decay")
+	}
+
+	ok, err := c.nonFinite.Admit(x, w)
+	if err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	c.addWeighted(x, w)
+	return nil
+}
+
 // add updates the mean, count, and centralized power sums in an efficient
 // and stable (numerically speaking) way, which allows for more accurate reporting
 // of moments. See the following paper for details on the algorithm used:
@@ -105,6 +211,8 @@ func (c *Core) UnsafePush(x float64) error {
 // moments with arbitrary weights, Computational Statistics 31 (2016) 1305–1325.
 func (c *Core) add(x float64) {
 	c.count++
+	c.weight++
+	c.addTotal(x)
 	count := float64(c.count)
 	delta := x - c.mean
 	c.mean += delta / count
@@ -115,11 +223,40 @@ func (c *Core) add(x float64) {
 				math.Pow(delta, float64(k))
 		for i := 1; i <= k-2; i++ {
 			c.sums[k] +=
-				float64(mathutil.Binom(k, i)*mathutil.Sign(i)) *
+				mathutil.BinomFloat(k, i) * float64(mathutil.Sign(i)) *
 					math.Pow(delta/count, float64(i)) *
 					c.sums[k-i]
 		}
 	}
+
+	c.publish()
+}
+
+// addWeighted is the generalization of add() to a value with an arbitrary weight,
+// using the arbitrary-weight update formulas from the same paper.
+func (c *Core) addWeighted(x float64, w float64) {
+	c.count++
+	c.addTotal(w * x)
+	weight := c.weight + w
+	delta := x - c.mean
+	// shifts of the previous mean and of x to the updated mean, respectively
+	shiftOld := -w * delta / weight
+	shiftNew := c.weight * delta / weight
+	for k := len(c.sums) - 1; k >= 2; k-- {
+		c.sums[k] +=
+			c.weight*math.Pow(shiftOld, float64(k)) +
+				w*math.Pow(shiftNew, float64(k))
+		for i := 1; i <= k-2; i++ {
+			c.sums[k] +=
+				mathutil.BinomFloat(k, i) *
+					math.Pow(shiftOld, float64(i)) *
+					c.sums[k-i]
+		}
+	}
+	c.mean += w * delta / weight
+	c.weight = weight
+
+	c.publish()
 }
 
 // addDecay updates the mean, count, and centralized power sums (with exponential decay)
@@ -130,10 +267,14 @@ func (c *Core) add(x float64) {
 // moments with arbitrary weights, Computational Statistics 31 (2016) 1305–1325.
 func (c *Core) addDecay(x float64) {
 	c.count++
+	c.weight++
+	c.addTotal(x)
 
 	var decay float64
 	if c.count == 1 {
 		decay = 1
+	} else if c.window != 0 {
+		decay = windowDecay(*c.decay, c.count)
 	} else {
 		decay = *c.decay
 	}
@@ -147,12 +288,66 @@ func (c *Core) addDecay(x float64) {
 		old += coeff * term
 		for i := 1; i <= k-2; i++ {
 			old +=
-				float64(mathutil.Binom(k, i)*mathutil.Sign(i)) *
+				mathutil.BinomFloat(k, i) * float64(mathutil.Sign(i)) *
 					math.Pow(decay*delta, float64(i)) *
 					(1 - decay) * c.sums[k-i]
 		}
 		c.sums[k] = old
 	}
+
+	c.publish()
+}
+
+// windowDecay returns the decay factor to use when pushing the nth value in the
+// window of a Core with decay, so that the values in the window are weighted in
+// proportion to (1-decay)^age, with weights that sum to 1. The denominator
+// 1-(1-decay)^n is computed via Expm1 and Log1p, since it would otherwise round
+// to 0 for tiny decays and turn the sums into NaNs.
+func windowDecay(decay float64, n int) float64 {
+	return decay / -math.Expm1(float64(n)*math.Log1p(-decay))
+}
+
+// removeDecay undoes the contribution of the oldest value in the window of a
+// Core with decay, whose weight is determined by its age. It inverts the update
+// formulas for combining weighted sums from the paper cited in addDecay() to
+// split the value off, and then renormalizes the sums of the remaining values
+// so that their weights sum to 1.
+func (c *Core) removeDecay(x float64) {
+	n := c.count
+	c.count--
+	c.weight--
+	c.addTotal(-x)
+	if c.count > 0 {
+		w := windowDecay(*c.decay, n) * math.Pow(1-*c.decay, float64(n-1))
+		// equivalent to (mean - w*x) / (1 - w), but leaves the mean
+		// exactly unchanged if x is equal to it
+		c.mean += w * (c.mean - x) / (1 - w)
+		delta := x - c.mean
+		for k := 2; k <= len(c.sums)-1; k++ {
+			c.sums[k] -=
+				((1-w)*math.Pow(-w, float64(k)) + w*math.Pow(1-w, float64(k))) *
+					math.Pow(delta, float64(k))
+			for i := 1; i <= k-2; i++ {
+				c.sums[k] -=
+					mathutil.BinomFloat(k, i) * float64(mathutil.Sign(i)) *
+						math.Pow(w*delta, float64(i)) *
+						c.sums[k-i]
+			}
+		}
+		for k := 2; k <= len(c.sums)-1; k++ {
+			c.sums[k] /= 1 - w
+		}
+	} else {
+		c.mean = 0
+		c.weight = 0
+		c.total = 0
+		c.totalComp = 0
+		for k := range c.sums {
+			c.sums[k] = 0
+		}
+	}
+
+	c.publish()
 }
 
 // remove simply undoes the result of an add() call, and clears out the stats
@@ -160,6 +355,8 @@ func (c *Core) addDecay(x float64) {
 // window size is 1).
 func (c *Core) remove(x float64) {
 	c.count--
+	c.weight--
+	c.addTotal(-x)
 	if c.count > 0 {
 		count := float64(c.count)
 		c.mean -= (x - c.mean) / count
@@ -171,38 +368,289 @@ func (c *Core) remove(x float64) {
 					math.Pow(delta, float64(k))
 			for i := 1; i <= k-2; i++ {
 				c.sums[k] -=
-					float64(mathutil.Binom(k, i)*mathutil.Sign(i)) *
+					mathutil.BinomFloat(k, i) * float64(mathutil.Sign(i)) *
 						math.Pow(delta/(count+1), float64(i)) *
 						c.sums[k-i]
 			}
 		}
 	} else {
 		c.mean = 0
+		c.weight = 0
+		c.total = 0
+		c.totalComp = 0
 		for k := range c.sums {
 			c.sums[k] = 0
 		}
 	}
+
+	c.publish()
 }
 
-// Count returns the number of values seen seen globally.
-func (c *Core) Count() int {
+// Merge combines the stats of another Core into this one, as if this Core
+// had also consumed all of the values consumed by the other Core. Both Cores
+// must track the same sums, and neither may have a window or decay set, since
+// the values within a window (or their weights) cannot be recovered from the sums.
+// The other Core is left untouched.
+func (c *Core) Merge(other *Core) error {
+	if c == other {
+		return errors.New("cannot merge a Core with itself")
+	}
+
+	// the other Core is copied before this one is locked, rather than holding both
+	// locks, so that merges in opposite directions cannot deadlock
+	other = other.snapshot()
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.window != 0 || other.window != 0 || c.duration != 0 || other.duration != 0 {
+		return errors.New("cannot merge Cores with a window")
+	}
+	if c.decay != nil || other.decay != nil {
+		return errors.New("cannot merge Cores with decay")
+	}
+	if len(c.sums) != len(other.sums) {
+		return errors.Errorf(
+			"cannot merge Cores tracking %d and %d sums",
+			len(c.sums),
+			len(other.sums),
+		)
+	}
+
+	c.merge(other)
+	return nil
+}
+
+// merge updates the mean, count, and centralized power sums with those of another
+// Core using the pairwise update formulas from the paper cited in add().
+func (c *Core) merge(other *Core) {
+	if other.count == 0 {
+		return
+	}
+
+	countA := c.weight
+	countB := other.weight
+	count := countA + countB
+	delta := other.mean - c.mean
+
+	// the sums of each side are shifted to the combined mean, i.e. by
+	// -countB*delta/count for this Core and countA*delta/count for the other
+	sumsA := powerSums(c.sums, countA)
+	sumsB := powerSums(other.sums, countB)
+	shiftA := -countB * delta / count
+	shiftB := countA * delta / count
+	for k := len(c.sums) - 1; k >= 2; k-- {
+		sum := sumsA[k] + sumsB[k]
+		for i := 1; i <= k; i++ {
+			sum +=
+				mathutil.BinomFloat(k, i) *
+					(math.Pow(shiftA, float64(i))*sumsA[k-i] +
+						math.Pow(shiftB, float64(i))*sumsB[k-i])
+		}
+		c.sums[k] = sum
+	}
+
+	c.count += other.count
+	c.weight += other.weight
+	c.addTotal(other.total)
+	c.addTotal(other.totalComp)
+	c.mean += countB * delta / count
+
+	c.publish()
+}
+
+// snapshot returns a copy of the stats of the Core under the read lock, for
+// comparing or merging it with another Core without holding both locks; the
+// copy has no window contents and is not meant to be pushed to.
+func (c *Core) snapshot() *Core {
 	c.mux.RLock()
 	defer c.mux.RUnlock()
-	return c.UnsafeCount()
+
+	snapshot := &Core{
+		mean:      c.mean,
+		total:     c.total,
+		totalComp: c.totalComp,
+		sums:      make([]float64, len(c.sums)),
+		count:     c.count,
+		weight:    c.weight,
+		window:    c.window,
+		decay:     c.decay,
+		duration:  c.duration,
+	}
+	copy(snapshot.sums, c.sums)
+	return snapshot
 }
 
-// UnsafeCount returns the number of values seen seen globally,
+// powerSums returns a copy of the centralized power sums, with the 0th and 1st
+// power sums filled in (i.e. the count and zero, respectively).
+func powerSums(sums []float64, count float64) []float64 {
+	result := make([]float64, len(sums))
+	copy(result, sums)
+	if len(result) > 0 {
+		result[0] = count
+	}
+	if len(result) > 1 {
+		result[1] = 0
+	}
+	return result
+}
+
+// Clone returns an independent snapshot of the Core, including the values
+// currently in the window (if one is set), which can be read without blocking
+// the original.
+func (c *Core) Clone() *Core {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	clone := &Core{
+		mean:      c.mean,
+		total:     c.total,
+		totalComp: c.totalComp,
+		sums:      make([]float64, len(c.sums)),
+		count:     c.count,
+		weight:    c.weight,
+		window:    c.window,
+		queue:     ring.New[float64](uint64(c.window)),
+		nonFinite: c.nonFinite,
+		duration:  c.duration,
+		timed:     deque.New[timedValue](),
+		latest:    c.latest,
+	}
+	copy(clone.sums, c.sums)
+	clone.publish()
+
+	if c.decay != nil {
+		decay := *c.decay
+		clone.decay = &decay
+	}
+
+	// the clone's queue is as large as this one's, so it has room for every value
+	for _, x := range c.queued() {
+		_ = clone.queue.Put(x)
+	}
+
+	for i := 0; i < c.timed.Len(); i++ {
+		clone.timed.PushBack(c.timed.At(i))
+	}
+
+	return clone
+}
+
+// Window returns a copy of the values currently in the window, from oldest
+// to newest; for a Core with a time window, these are the values pushed within
+// its duration, and for a global Core, there are none.
+func (c *Core) Window() []float64 {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if c.duration != 0 {
+		xs := make([]float64, 0, c.timed.Len())
+		for i := 0; i < c.timed.Len(); i++ {
+			xs = append(xs, c.timed.At(i).x)
+		}
+		return xs
+	}
+
+	return c.queued()
+}
+
+// Resize changes the size of the window of the Core, under the lock. Growing the
+// window keeps all of the values in it, while shrinking it evicts the oldest values
+// until the rest fit, updating the sums as if they had slid out of the window.
+// Resizing to a window of 0 makes the Core global, keeping its current statistics;
+// conversely, a global Core that has seen values cannot be given a window, since it
+// does not keep the values it would need to evict later. Metrics wrapping the Core
+// keep reporting the window they were created with in their string representations.
+func (c *Core) Resize(window int) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if window < 0 {
+		return errors.Errorf("%d is a negative window", window)
+	} else if c.duration != 0 {
+		return errors.New("cannot resize a Core with a time window")
+	} else if c.window == 0 && window != 0 && c.count > 0 {
+		return errors.New("cannot give a window to a global Core that has seen values")
+	}
+
+	xs := c.queued()
+
+	// evict the oldest values that no longer fit
+	for window != 0 && len(xs) > window {
+		if c.decay == nil {
+			c.remove(xs[0])
+		} else {
+			c.removeDecay(xs[0])
+		}
+		xs = xs[1:]
+	}
+
+	c.queue.Dispose()
+	c.queue = ring.New[float64](uint64(window))
+	c.window = window
+	if window == 0 {
+		return nil
+	}
+
+	for _, x := range xs {
+		err := c.queue.Put(x)
+		if err != nil {
+			return errors.Wrapf(err, "error pushing %f to queue", x)
+		}
+	}
+	return nil
+}
+
+// queued returns a copy of the values in the window's queue, from oldest to
+// newest; the Core must be locked, at least for reading.
+func (c *Core) queued() []float64 {
+	xs := make([]float64, 0, c.queue.Len())
+	c.queue.Do(func(x float64) {
+		xs = append(xs, x)
+	})
+	return xs
+}
+
+// Count returns the number of values currently in the window, if one is set,
+// or else the number of values seen globally. This does not lock, so it never
+// waits on a concurrent push; however, it is only guaranteed to be consistent
+// with the other stats of the Core once pushes have returned, e.g. the count
+// may already reflect a value whose sums are still being updated.
+func (c *Core) Count() int {
+	return int(atomic.LoadInt64(&c.atomicCount))
+}
+
+// UnsafeCount returns the number of values currently in the window (or seen globally),
 // but does not lock. This should only be used if the user
 // plans to make use of the [R]Lock()/[R]Unlock() Core methods.
 func (c *Core) UnsafeCount() int {
 	return c.count
 }
 
-// Mean returns the mean of values seen.
-func (c *Core) Mean() (float64, error) {
+// WeightSum returns the total weight of values seen, i.e. the number
+// of values seen if none of them were pushed with a weight.
+func (c *Core) WeightSum() float64 {
 	c.mux.RLock()
 	defer c.mux.RUnlock()
-	return c.UnsafeMean()
+	return c.UnsafeWeightSum()
+}
+
+// UnsafeWeightSum returns the total weight of values seen,
+// but does not lock. This should only be used if the user
+// plans to make use of the [R]Lock()/[R]Unlock() Core methods.
+func (c *Core) UnsafeWeightSum() float64 {
+	return c.weight
+}
+
+// Mean returns the mean of values seen. Like Count, this does not lock, so it is
+// only guaranteed to be consistent with the other stats of the Core (including
+// its count) once pushes have returned.
+func (c *Core) Mean() (float64, error) {
+	if atomic.LoadInt64(&c.atomicCount) == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	return math.Float64frombits(atomic.LoadUint64(&c.atomicMean)), nil
 }
 
 // UnsafeMean returns the mean of values seen,
@@ -234,12 +682,100 @@ func (c *Core) UnsafeSum(k int) (float64, error) {
 	}
 
 	if k <= 0 || k >= len(c.sums) {
-		return 0, errors.Errorf("%d is not a tracked power sum", k)
+		return 0, fmt.Errorf("%d is %w", k, ErrorNotTracked)
 	}
 
 	return c.sums[k], nil
 }
 
+// SumRaw returns the (non-centralized) sum of values seen,
+// i.e. the sum of the values in the window if one is set.
+func (c *Core) SumRaw() float64 {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.UnsafeSumRaw()
+}
+
+// UnsafeSumRaw returns the (non-centralized) sum of values seen,
+// but does not lock. This should only be used if the user
+// plans to make use of the [R]Lock()/[R]Unlock() Core methods.
+// Values pushed with a weight contribute their weighted value.
+func (c *Core) UnsafeSumRaw() float64 {
+	return c.total + c.totalComp
+}
+
+// addTotal adds x to the sum of the values using Neumaier's compensated summation,
+// so that e.g. the small values left in a window after a large one is evicted
+// are summed without the rounding error incurred while the large one was present.
+func (c *Core) addTotal(x float64) {
+	t := c.total + x
+	if math.IsInf(t, 0) || math.IsNaN(t) {
+		// the compensation is meaningless (and would become NaN) for a non-finite sum
+		c.total = t
+		return
+	}
+
+	if math.Abs(c.total) >= math.Abs(x) {
+		c.totalComp += (c.total - t) + x
+	} else {
+		c.totalComp += (x - t) + c.total
+	}
+	c.total = t
+}
+
+// SumOfSquares returns the (non-centralized) sum of squares of values seen.
+// The 2nd power sum must be tracked by the Core.
+func (c *Core) SumOfSquares() (float64, error) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.UnsafeSumOfSquares()
+}
+
+// UnsafeSumOfSquares returns the (non-centralized) sum of squares of values seen,
+// but does not lock. This should only be used if the user
+// plans to make use of the [R]Lock()/[R]Unlock() Core methods.
+func (c *Core) UnsafeSumOfSquares() (float64, error) {
+	if len(c.sums) <= 2 {
+		return 0, fmt.Errorf("%d is %w", 2, ErrorNotTracked)
+	}
+
+	return c.sums[2] + c.weight*c.mean*c.mean, nil
+}
+
+// ApproxEqual returns whether the Core has the same count as another Core, and
+// whether their means, weight sums and centralized power sums agree to the given
+// number of decimal places (see mathutil.ApproxEqual); this is useful for comparing
+// Cores after a serialization or merge round trip. Cores tracking different sums
+// are never equal.
+func (c *Core) ApproxEqual(other *Core, digits int) bool {
+	if c == other {
+		return true
+	}
+
+	// the other Core is copied before this one is locked, since holding both read
+	// locks can deadlock with a comparison in the opposite direction and a waiting writer
+	other = other.snapshot()
+
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if c.count != other.count || len(c.sums) != len(other.sums) {
+		return false
+	}
+
+	if !mathutil.ApproxEqual(c.mean, other.mean, digits) ||
+		!mathutil.ApproxEqual(c.weight, other.weight, digits) {
+		return false
+	}
+
+	for k := range c.sums {
+		if !mathutil.ApproxEqual(c.sums[k], other.sums[k], digits) {
+			return false
+		}
+	}
+	return true
+}
+
 // Clear clears all stats being tracked.
 func (c *Core) Clear() {
 	c.mux.Lock()
@@ -256,8 +792,24 @@ func (c *Core) UnsafeClear() {
 	}
 
 	c.count = 0
+	c.weight = 0
 	c.mean = 0
+	c.total = 0
+	c.totalComp = 0
 	c.queue.Reset()
+	c.timed = deque.New[timedValue]()
+	c.latest = time.Time{}
+
+	c.publish()
+}
+
+// publish atomically stores the count and mean for lock-free reads by Count and
+// Mean; it must be called while holding the write lock, after every update to them.
+// The mean is stored first, so that a reader that sees a nonzero count sees a mean
+// at least as new as the one stored with that count.
+func (c *Core) publish() {
+	atomic.StoreUint64(&c.atomicMean, math.Float64bits(c.mean))
+	atomic.StoreInt64(&c.atomicCount, int64(c.count))
 }
 
 // RLock locks the core internals for reading.
@@ -2,7 +2,11 @@ package moment
 
 import (
 	"fmt"
+	"math"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -241,6 +245,9 @@ func TestClear(t *testing.T) {
 	assert.Equal(t, float64(0), wrapper.core.mean)
 	assert.Equal(t, int(0), wrapper.core.count)
 	assert.Equal(t, uint64(0), wrapper.core.queue.Len())
+	assert.Equal(t, 0, wrapper.core.Count())
+	_, err = wrapper.core.Mean()
+	assert.EqualError(t, err, "no values seen yet")
 }
 
 func TestCount(t *testing.T) {
@@ -257,6 +264,60 @@ func TestCount(t *testing.T) {
 	assert.Equal(t, 3, wrapper.core.Count())
 }
 
+func TestLockFreeReads(t *testing.T) {
+	core, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true}, Window: stream.IntPtr(0)})
+	require.NoError(t, err)
+
+	const pushers, pushes = 4, 2000
+	var wg sync.WaitGroup
+	for p := 0; p < pushers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < pushes; i++ {
+				assert.NoError(t, core.Push(float64((p+i)%10)))
+			}
+		}(p)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// the global count never decreases, and the mean of
+	// values in [0, 9] always lies in [0, 9], even mid-push
+	last := 0
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+
+		count := core.Count()
+		assert.GreaterOrEqual(t, count, last)
+		last = count
+
+		mean, err := core.Mean()
+		if count > 0 {
+			require.NoError(t, err)
+			assert.True(t, mean >= 0 && mean <= 9, "mean %v out of range", mean)
+		}
+	}
+
+	// once pushes have returned, the lock-free reads agree with the locked ones
+	assert.Equal(t, pushers*pushes, core.Count())
+	mean, err := core.Mean()
+	require.NoError(t, err)
+	core.RLock()
+	expected, err := core.UnsafeMean()
+	core.RUnlock()
+	require.NoError(t, err)
+	assert.Equal(t, expected, mean)
+}
+
 type CoreMeanSuite struct {
 	suite.Suite
 	wrapper *mockWrapper
@@ -337,6 +398,192 @@ func (s *CoreSumSuite) TestFailForUntrackedSum() {
 	s.EqualError(err, "10 is not a tracked power sum")
 }
 
+func TestHighOrderSums(t *testing.T) {
+	// binomial coefficients for orders this high overflow an int
+	xs := []float64{1, 2, 3, 4, 8, -1, 0.5}
+	for _, window := range []int{0, 4} {
+		t.Run(fmt.Sprintf("pass: window of %d", window), func(t *testing.T) {
+			core, err := NewCore(&CoreConfig{
+				Sums:   SumsConfig{24: true},
+				Window: stream.IntPtr(window),
+			})
+			require.NoError(t, err)
+
+			for i, x := range xs {
+				err := core.Push(x)
+				require.NoError(t, err)
+
+				seen := xs[:i+1]
+				if window > 0 && len(seen) > window {
+					seen = seen[len(seen)-window:]
+				}
+				mean := 0.
+				for _, y := range seen {
+					mean += y / float64(len(seen))
+				}
+
+				for k := 21; k <= 24; k++ {
+					// odd sums can cancel to 0, so compare relative to the absolute sum
+					expected, scale := 0., 0.
+					for _, y := range seen {
+						expected += math.Pow(y-mean, float64(k))
+						scale += math.Pow(math.Abs(y-mean), float64(k))
+					}
+
+					sum, err := core.Sum(k)
+					require.NoError(t, err)
+					assert.InDelta(t, expected, sum, 1e-9*scale)
+				}
+			}
+		})
+	}
+}
+
+func TestDecayWindow(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 8, -1, 0.5}
+	decay := 0.3
+
+	t.Run("pass: matches sums weighted by age within the window", func(t *testing.T) {
+		for _, window := range []int{1, 3, 5} {
+			core, err := NewCore(&CoreConfig{
+				Sums:   SumsConfig{2: true, 3: true, 4: true},
+				Window: stream.IntPtr(window),
+				Decay:  stream.FloatPtr(decay),
+			})
+			require.NoError(t, err)
+
+			for i, x := range xs {
+				err := core.Push(x)
+				require.NoError(t, err)
+
+				seen := xs[:i+1]
+				if len(seen) > window {
+					seen = seen[len(seen)-window:]
+				}
+				weights := make([]float64, len(seen))
+				total := 0.
+				for j := range seen {
+					weights[j] = math.Pow(1-decay, float64(len(seen)-1-j))
+					total += weights[j]
+				}
+				mean := 0.
+				for j, y := range seen {
+					mean += weights[j] / total * y
+				}
+
+				actualMean, err := core.Mean()
+				require.NoError(t, err)
+				testutil.Approx(t, mean, actualMean)
+				for k := 2; k <= 4; k++ {
+					expected := 0.
+					for j, y := range seen {
+						expected += weights[j] / total * math.Pow(y-mean, float64(k))
+					}
+
+					sum, err := core.Sum(k)
+					require.NoError(t, err)
+					testutil.Approx(t, expected, sum)
+				}
+			}
+		}
+	})
+
+	t.Run("pass: matches values computed offline", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(3),
+			Decay:  stream.FloatPtr(decay),
+		})
+		require.NoError(t, err)
+		err = core.PushBatch(xs)
+		require.NoError(t, err)
+
+		mean, err := core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 1.6986301369863013, mean)
+
+		expectedSums := []float64{11.868080315256146, 48.91495101756478, 370.664952408496}
+		for k := 2; k <= 4; k++ {
+			sum, err := core.Sum(k)
+			require.NoError(t, err)
+			testutil.Approx(t, expectedSums[k-2], sum)
+		}
+	})
+
+	t.Run("pass: tiny decays weight the window uniformly", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{2: true},
+			Window: stream.IntPtr(3),
+			Decay:  stream.FloatPtr(1e-17),
+		})
+		require.NoError(t, err)
+		err = core.PushBatch(xs)
+		require.NoError(t, err)
+
+		// the last 3 values are 8, -1 and 0.5, which are weighted equally
+		mean, err := core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 2.5, mean)
+
+		sum, err := core.Sum(2)
+		require.NoError(t, err)
+		testutil.Approx(t, (5.5*5.5+3.5*3.5+2*2)/3, sum)
+	})
+}
+
+func TestInitialState(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 8, -1, 0.5, 6}
+	sums := SumsConfig{2: true, 3: true, 4: true}
+
+	for _, decay := range []*float64{nil, stream.FloatPtr(0.3)} {
+		// an unseeded Core fed all of the values
+		unseeded, err := NewCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(0), Decay: decay})
+		require.NoError(t, err)
+		err = unseeded.PushBatch(xs)
+		require.NoError(t, err)
+
+		// a Core seeded with the state of the first half of the values
+		previous, err := NewCore(&CoreConfig{Sums: sums, Window: stream.IntPtr(0), Decay: decay})
+		require.NoError(t, err)
+		err = previous.PushBatch(xs[:4])
+		require.NoError(t, err)
+
+		mean, err := previous.Mean()
+		require.NoError(t, err)
+		initialSums := map[int]float64{}
+		for k := 2; k <= 4; k++ {
+			initialSums[k], err = previous.Sum(k)
+			require.NoError(t, err)
+		}
+
+		seeded, err := NewCore(&CoreConfig{
+			Sums:         sums,
+			Window:       stream.IntPtr(0),
+			Decay:        decay,
+			InitialCount: stream.IntPtr(previous.Count()),
+			InitialMean:  stream.FloatPtr(mean),
+			InitialSums:  initialSums,
+		})
+		require.NoError(t, err)
+		err = seeded.PushBatch(xs[4:])
+		require.NoError(t, err)
+
+		assert.Equal(t, unseeded.Count(), seeded.Count())
+		expectedMean, err := unseeded.Mean()
+		require.NoError(t, err)
+		actualMean, err := seeded.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, expectedMean, actualMean)
+		for k := 2; k <= 4; k++ {
+			expected, err := unseeded.Sum(k)
+			require.NoError(t, err)
+			actual, err := seeded.Sum(k)
+			require.NoError(t, err)
+			testutil.Approx(t, expected, actual)
+		}
+	}
+}
+
 func TestLock(t *testing.T) {
 	wrapper := &mockWrapper{window: stream.IntPtr(3)}
 	err := Init(wrapper)
@@ -362,9 +609,9 @@ func TestLock(t *testing.T) {
 		done <- true
 	}()
 
-	// Read the sum; note that Sum also uses the RLock/RUnlock of the lock internally,
-	// and this should not be blocked by the earlier RLock call
-	sum, err := wrapper.core.Sum(2)
+	// Read the sum while holding the read lock; UnsafeSum must be used here, since
+	// acquiring the read lock again via Sum can deadlock once the writer is waiting
+	sum, err := wrapper.core.UnsafeSum(2)
 	require.NoError(t, err)
 	testutil.Approx(t, 14., sum)
 
@@ -377,3 +624,862 @@ func TestLock(t *testing.T) {
 	require.NoError(t, err)
 	testutil.Approx(t, 26./3., sum)
 }
+
+func TestMerge(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 8, -3, 0.5, 7, 2, 11, -6}
+
+	t.Run("pass: merging shards matches a single core", func(t *testing.T) {
+		whole := &mockWrapper{window: stream.IntPtr(0)}
+		err := Init(whole)
+		require.NoError(t, err)
+		for _, x := range xs {
+			err := whole.core.Push(x)
+			require.NoError(t, err)
+		}
+
+		merged := &mockWrapper{window: stream.IntPtr(0)}
+		err = Init(merged)
+		require.NoError(t, err)
+		for _, shard := range [][]float64{xs[:4], xs[4:5], {}, xs[5:]} {
+			wrapper := &mockWrapper{window: stream.IntPtr(0)}
+			err := Init(wrapper)
+			require.NoError(t, err)
+			for _, x := range shard {
+				err := wrapper.core.Push(x)
+				require.NoError(t, err)
+			}
+
+			err = merged.core.Merge(wrapper.core)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, whole.core.count, merged.core.count)
+		testutil.Approx(t, whole.core.mean, merged.core.mean)
+		for k := range whole.core.sums {
+			testutil.Approx(t, whole.core.sums[k], merged.core.sums[k])
+		}
+	})
+
+	t.Run("pass: concurrent merges in opposite directions do not deadlock", func(t *testing.T) {
+		a, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true}, Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+		b, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true}, Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+
+		// the cores are left empty, so that they can be merged any number of times;
+		// the merges need to run in parallel to interleave, even on a single CPU
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+		var wg sync.WaitGroup
+		for _, pair := range [][2]*Core{{a, b}, {b, a}} {
+			wg.Add(1)
+			go func(core *Core, other *Core) {
+				defer wg.Done()
+				for i := 0; i < 100000; i++ {
+					assert.NoError(t, core.Merge(other))
+				}
+			}(pair[0], pair[1])
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("concurrent merges deadlocked")
+		}
+	})
+
+	t.Run("pass: merging leaves the other core untouched", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true}, Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+		other, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true}, Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+		for _, x := range xs {
+			err := other.Push(x)
+			require.NoError(t, err)
+		}
+
+		count, mean, sums := other.count, other.mean, append([]float64{}, other.sums...)
+		err = core.Merge(other)
+		require.NoError(t, err)
+		assert.Equal(t, count, other.count)
+		assert.Equal(t, mean, other.mean)
+		assert.Equal(t, sums, other.sums)
+	})
+
+	t.Run("fail: merging windowed cores returns error", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+		other, err := NewCore(&CoreConfig{Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+
+		err = core.Merge(other)
+		testutil.ContainsError(t, err, "cannot merge Cores with a window")
+
+		err = other.Merge(core)
+		testutil.ContainsError(t, err, "cannot merge Cores with a window")
+	})
+
+	t.Run("fail: merging decayed cores returns error", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Window: stream.IntPtr(0), Decay: stream.FloatPtr(0.3)})
+		require.NoError(t, err)
+		other, err := NewCore(&CoreConfig{Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+
+		err = core.Merge(other)
+		testutil.ContainsError(t, err, "cannot merge Cores with decay")
+	})
+
+	t.Run("fail: merging cores with different sums returns error", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true}, Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+		other, err := NewCore(&CoreConfig{Sums: SumsConfig{4: true}, Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+
+		err = core.Merge(other)
+		testutil.ContainsError(t, err, "cannot merge Cores tracking 3 and 5 sums")
+	})
+
+	t.Run("fail: merging a core with itself returns error", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+
+		err = core.Merge(core)
+		testutil.ContainsError(t, err, "cannot merge a Core with itself")
+	})
+}
+
+func TestPushBatch(t *testing.T) {
+	configs := map[string]*CoreConfig{
+		"global": {
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(0),
+		},
+		"window": {
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(3),
+		},
+		"decay": {
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(0),
+			Decay:  stream.FloatPtr(0.3),
+		},
+	}
+
+	xs := []float64{1, 2, 3, 4, 8, -3, 0.5, 7}
+	for name, config := range configs {
+		t.Run("pass: batch matches push loop for "+name, func(t *testing.T) {
+			batch, err := NewCore(config)
+			require.NoError(t, err)
+			loop, err := NewCore(config)
+			require.NoError(t, err)
+
+			err = batch.PushBatch(xs[:5])
+			require.NoError(t, err)
+			err = batch.PushBatch(xs[5:])
+			require.NoError(t, err)
+			for _, x := range xs {
+				err := loop.Push(x)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, loop.count, batch.count)
+			assert.Equal(t, loop.queue.Len(), batch.queue.Len())
+			assert.Equal(t, loop.mean, batch.mean)
+			assert.Equal(t, loop.sums, batch.sums)
+		})
+	}
+
+	t.Run("fail: if queue insertion fails, return error", func(t *testing.T) {
+		core, err := NewCore(configs["window"])
+		require.NoError(t, err)
+
+		// dispose the queue to simulate an error when we try to insert into the queue
+		core.queue.Dispose()
+
+		err = core.PushBatch(xs)
+		testutil.ContainsError(t, err, fmt.Sprintf("error pushing %f to queue", xs[0]))
+	})
+}
+
+func benchmarkValues(n int) []float64 {
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = float64(i%1000) / 7.
+	}
+	return xs
+}
+
+func BenchmarkCorePush(b *testing.B) {
+	xs := benchmarkValues(1e6)
+	for i := 0; i < b.N; i++ {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(1000),
+		})
+		require.NoError(b, err)
+		for _, x := range xs {
+			err := core.Push(x)
+			require.NoError(b, err)
+		}
+	}
+}
+
+// BenchmarkCoreCountContended reads the count while another goroutine pushes
+// continuously, comparing the lock-free Count with a read under the read lock.
+func BenchmarkCoreCountContended(b *testing.B) {
+	for _, bench := range []struct {
+		name  string
+		count func(*Core) int
+	}{
+		{"lock-free", func(c *Core) int { return c.Count() }},
+		{"locked", func(c *Core) int {
+			c.RLock()
+			defer c.RUnlock()
+			return c.UnsafeCount()
+		}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			core, err := NewCore(&CoreConfig{
+				Sums:   SumsConfig{2: true, 3: true, 4: true},
+				Window: stream.IntPtr(1000),
+			})
+			require.NoError(b, err)
+
+			done := make(chan struct{})
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; ; i++ {
+					select {
+					case <-done:
+						return
+					default:
+						_ = core.Push(float64(i % 1000))
+					}
+				}
+			}()
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					bench.count(core)
+				}
+			})
+			b.StopTimer()
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+func BenchmarkCorePushBatch(b *testing.B) {
+	xs := benchmarkValues(1e6)
+	for i := 0; i < b.N; i++ {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(1000),
+		})
+		require.NoError(b, err)
+		err = core.PushBatch(xs)
+		require.NoError(b, err)
+	}
+}
+
+func TestPushWeighted(t *testing.T) {
+	config := &CoreConfig{
+		Sums:   SumsConfig{2: true, 3: true, 4: true},
+		Window: stream.IntPtr(0),
+	}
+
+	t.Run("pass: weighted push matches repeated pushes", func(t *testing.T) {
+		weighted, err := NewCore(config)
+		require.NoError(t, err)
+		repeated, err := NewCore(config)
+		require.NoError(t, err)
+
+		pushes := []struct {
+			x float64
+			w int
+		}{{1, 1}, {2, 3}, {8, 2}, {-3, 1}, {4, 4}}
+		for _, push := range pushes {
+			err := weighted.PushWeighted(push.x, float64(push.w))
+			require.NoError(t, err)
+			for i := 0; i < push.w; i++ {
+				err := repeated.Push(push.x)
+				require.NoError(t, err)
+			}
+		}
+
+		assert.Equal(t, len(pushes), weighted.Count())
+		assert.Equal(t, repeated.WeightSum(), weighted.WeightSum())
+		testutil.Approx(t, repeated.mean, weighted.mean)
+		for k := range repeated.sums {
+			testutil.Approx(t, repeated.sums[k], weighted.sums[k])
+		}
+	})
+
+	t.Run("pass: fractional weights scale the sums", func(t *testing.T) {
+		core, err := NewCore(config)
+		require.NoError(t, err)
+
+		err = core.PushWeighted(1, 0.5)
+		require.NoError(t, err)
+		err = core.PushWeighted(3, 1.5)
+		require.NoError(t, err)
+
+		testutil.Approx(t, 2., core.WeightSum())
+		testutil.Approx(t, 2.5, core.mean)
+		testutil.Approx(t, 0.5*2.25+1.5*0.25, core.sums[2])
+	})
+
+	t.Run("fail: nonpositive weight returns error", func(t *testing.T) {
+		core, err := NewCore(config)
+		require.NoError(t, err)
+
+		err = core.PushWeighted(1, 0)
+		testutil.ContainsError(t, err, fmt.Sprintf("%f is a nonpositive weight", 0.))
+
+		err = core.PushWeighted(1, -1)
+		testutil.ContainsError(t, err, fmt.Sprintf("%f is a nonpositive weight", -1.))
+	})
+
+	t.Run("fail: windowed core returns error", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+
+		err = core.PushWeighted(1, 2)
+		testutil.ContainsError(t, err, "cannot push weighted values to a Core with a window")
+	})
+
+	t.Run("fail: decayed core returns error", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Window: stream.IntPtr(0), Decay: stream.FloatPtr(0.3)})
+		require.NoError(t, err)
+
+		err = core.PushWeighted(1, 2)
+		testutil.ContainsError(t, err, "cannot push weighted values to a Core with decay")
+	})
+}
+
+func TestWeightSum(t *testing.T) {
+	wrapper := &mockWrapper{window: stream.IntPtr(3)}
+	err := Init(wrapper)
+	require.NoError(t, err)
+
+	xs := []float64{1, 2, 3, 4, 8}
+	for _, x := range xs {
+		err := wrapper.core.Push(x)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3., wrapper.core.WeightSum())
+}
+
+func TestSumRaw(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 8, -3, 0.5}
+
+	t.Run("pass: returns sum of values in window", func(t *testing.T) {
+		wrapper := &mockWrapper{window: stream.IntPtr(3)}
+		err := Init(wrapper)
+		require.NoError(t, err)
+
+		for i, x := range xs {
+			err := wrapper.core.Push(x)
+			require.NoError(t, err)
+
+			expected := 0.
+			for j := i; j >= 0 && j > i-3; j-- {
+				expected += xs[j]
+			}
+			testutil.Approx(t, expected, wrapper.core.SumRaw())
+		}
+	})
+
+	t.Run("pass: returns sum of all values if global", func(t *testing.T) {
+		wrapper := &mockWrapper{window: stream.IntPtr(0)}
+		err := Init(wrapper)
+		require.NoError(t, err)
+
+		err = wrapper.core.PushBatch(xs)
+		require.NoError(t, err)
+		testutil.Approx(t, 15.5, wrapper.core.SumRaw())
+	})
+
+	t.Run("pass: returns zero if no values seen", func(t *testing.T) {
+		wrapper := &mockWrapper{window: stream.IntPtr(3)}
+		err := Init(wrapper)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0., wrapper.core.SumRaw())
+	})
+}
+
+func TestSumOfSquares(t *testing.T) {
+	t.Run("pass: returns sum of squares of values in window", func(t *testing.T) {
+		wrapper := &mockWrapper{window: stream.IntPtr(3)}
+		err := Init(wrapper)
+		require.NoError(t, err)
+
+		err = wrapper.core.PushBatch([]float64{1, 2, 3, 4, 8})
+		require.NoError(t, err)
+
+		sum, err := wrapper.core.SumOfSquares()
+		require.NoError(t, err)
+		testutil.Approx(t, 9.+16.+64., sum)
+	})
+
+	t.Run("fail: untracked 2nd power sum returns error", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+
+		_, err = core.SumOfSquares()
+		testutil.ContainsError(t, err, "2 is not a tracked power sum")
+	})
+}
+
+func TestClone(t *testing.T) {
+	t.Run("pass: clone is unaffected by later pushes to the original", func(t *testing.T) {
+		wrapper := &mockWrapper{window: stream.IntPtr(3)}
+		err := Init(wrapper)
+		require.NoError(t, err)
+
+		err = wrapper.core.PushBatch([]float64{1, 2, 3, 4})
+		require.NoError(t, err)
+
+		clone := wrapper.core.Clone()
+
+		err = wrapper.core.PushBatch([]float64{8, 16})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, clone.Count())
+		mean, err := clone.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 3., mean)
+		sum, err := clone.Sum(2)
+		require.NoError(t, err)
+		testutil.Approx(t, 2., sum)
+		assert.Equal(t, uint64(3), clone.queue.Len())
+
+		// the clone's window holds its own copy of the values, so pushing the same
+		// values to it should bring it back in line with the original
+		err = clone.PushBatch([]float64{8, 16})
+		require.NoError(t, err)
+
+		for k := 1; k <= 4; k++ {
+			expected, err := wrapper.core.Sum(k)
+			require.NoError(t, err)
+			actual, err := clone.Sum(k)
+			require.NoError(t, err)
+			testutil.Approx(t, expected, actual)
+		}
+	})
+
+	t.Run("pass: clone does not share decay with the original", func(t *testing.T) {
+		wrapper := &mockWrapper{window: stream.IntPtr(0), decay: stream.FloatPtr(0.3)}
+		err := Init(wrapper)
+		require.NoError(t, err)
+
+		err = wrapper.core.PushBatch([]float64{1, 2, 3})
+		require.NoError(t, err)
+
+		clone := wrapper.core.Clone()
+		assert.Equal(t, *wrapper.core.decay, *clone.decay)
+		assert.NotSame(t, wrapper.core.decay, clone.decay)
+
+		err = wrapper.core.Push(100)
+		require.NoError(t, err)
+
+		mean, err := clone.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 1.81, mean)
+	})
+
+	t.Run("pass: clone copies the values in a time window", func(t *testing.T) {
+		duration := 3 * time.Second
+		core, err := NewCore(&CoreConfig{
+			Sums:     SumsConfig{2: true},
+			Window:   stream.IntPtr(0),
+			Duration: &duration,
+		})
+		require.NoError(t, err)
+
+		start := time.Unix(0, 0)
+		for i, x := range []float64{1, 2, 3} {
+			err := core.PushAt(x, start.Add(time.Duration(i)*time.Second))
+			require.NoError(t, err)
+		}
+
+		clone := core.Clone()
+
+		err = core.PushAt(10, start.Add(5*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 1, core.Count())
+		assert.Equal(t, 3, clone.Count())
+
+		err = clone.PushAt(10, start.Add(5*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 1, clone.Count())
+	})
+}
+
+func TestApproxEqual(t *testing.T) {
+	newCore := func(xs ...float64) *Core {
+		wrapper := &mockWrapper{window: stream.IntPtr(3)}
+		err := Init(wrapper)
+		require.NoError(t, err)
+		err = wrapper.core.PushBatch(xs)
+		require.NoError(t, err)
+		return wrapper.core
+	}
+
+	t.Run("pass: core equals itself and its clone", func(t *testing.T) {
+		core := newCore(1, 2, 3, 4)
+		assert.True(t, core.ApproxEqual(core, 10))
+
+		clone := core.Clone()
+		assert.True(t, core.ApproxEqual(clone, 10))
+		assert.True(t, clone.ApproxEqual(core, 10))
+	})
+
+	t.Run("pass: cores with the same window are equal", func(t *testing.T) {
+		core := newCore(100, 2, 3, 4)
+		other := newCore(2, 3, 4)
+		assert.True(t, core.ApproxEqual(other, 8))
+	})
+
+	t.Run("pass: tolerance follows the number of digits", func(t *testing.T) {
+		core := newCore(1, 2, 3)
+		other := newCore(1.001, 2.001, 3.001)
+		// the means differ by 0.001, while the centralized sums are equal
+		assert.True(t, core.ApproxEqual(other, 2))
+		assert.False(t, core.ApproxEqual(other, 3))
+	})
+
+	t.Run("pass: concurrent comparisons and pushes do not deadlock", func(t *testing.T) {
+		a, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true}, Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+		b, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true}, Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+		var wg sync.WaitGroup
+		for _, pair := range [][2]*Core{{a, b}, {b, a}} {
+			wg.Add(2)
+			go func(core *Core, other *Core) {
+				defer wg.Done()
+				for i := 0; i < 100000; i++ {
+					core.ApproxEqual(other, 10)
+				}
+			}(pair[0], pair[1])
+			go func(core *Core) {
+				defer wg.Done()
+				for i := 0; i < 100000; i++ {
+					assert.NoError(t, core.Push(1))
+				}
+			}(pair[0])
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("concurrent comparisons deadlocked")
+		}
+	})
+
+	t.Run("fail: differing counts are not equal", func(t *testing.T) {
+		core := newCore(2, 2)
+		other := newCore(2, 2, 2)
+		assert.False(t, core.ApproxEqual(other, 0))
+	})
+
+	t.Run("fail: differing sums are not equal", func(t *testing.T) {
+		core := newCore(1, 2, 3)
+		other, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true}, Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+		err = other.PushBatch([]float64{1, 2, 3})
+		require.NoError(t, err)
+		assert.False(t, core.ApproxEqual(other, 0))
+	})
+}
+
+func TestNonFinite(t *testing.T) {
+	newCore := func(mode *stream.NonFiniteMode) *Core {
+		core, err := NewCore(&CoreConfig{
+			Sums:      SumsConfig{1: true, 2: true},
+			Window:    stream.IntPtr(3),
+			NonFinite: mode,
+		})
+		require.NoError(t, err)
+
+		for _, x := range []float64{1, 2, 3} {
+			err := core.Push(x)
+			require.NoError(t, err)
+		}
+		return core
+	}
+
+	t.Run("fail: non-finite values return error by default", func(t *testing.T) {
+		for _, mode := range []*stream.NonFiniteMode{nil, stream.NonFiniteModePtr(stream.NonFiniteError)} {
+			core := newCore(mode)
+			err := core.Push(math.NaN())
+			assert.EqualError(t, err, "NaN is not a finite value")
+
+			// the window is left untouched
+			assert.Equal(t, 3, core.Count())
+			mean, err := core.Mean()
+			require.NoError(t, err)
+			testutil.Approx(t, 2, mean)
+		}
+	})
+
+	t.Run("pass: skip mode leaves count and sums unchanged", func(t *testing.T) {
+		core := newCore(stream.NonFiniteModePtr(stream.NonFiniteSkip))
+		for _, x := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+			err := core.Push(x)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, core.Count())
+		assert.Equal(t, uint64(3), core.queue.Len())
+		mean, err := core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 2, mean)
+		sum, err := core.Sum(2)
+		require.NoError(t, err)
+		testutil.Approx(t, 2, sum)
+
+		// finite values are still consumed, evicting the oldest value
+		err = core.Push(4)
+		require.NoError(t, err)
+		mean, err = core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 3, mean)
+	})
+
+	t.Run("pass: propagate mode consumes non-finite values", func(t *testing.T) {
+		core := newCore(stream.NonFiniteModePtr(stream.NonFinitePropagate))
+		err := core.Push(math.Inf(1))
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, core.Count())
+		mean, err := core.Mean()
+		require.NoError(t, err)
+		assert.True(t, math.IsInf(mean, 1))
+	})
+
+	t.Run("pass: mode also applies to timed and weighted pushes", func(t *testing.T) {
+		duration := time.Minute
+		core, err := NewCore(&CoreConfig{
+			Sums:      SumsConfig{1: true},
+			Window:    stream.IntPtr(0),
+			Duration:  &duration,
+			NonFinite: stream.NonFiniteModePtr(stream.NonFiniteSkip),
+		})
+		require.NoError(t, err)
+		err = core.PushAt(math.NaN(), time.Unix(0, 0))
+		require.NoError(t, err)
+		assert.Equal(t, 0, core.Count())
+
+		core, err = NewCore(&CoreConfig{
+			Sums:   SumsConfig{1: true},
+			Window: stream.IntPtr(0),
+		})
+		require.NoError(t, err)
+		err = core.PushWeighted(1, math.Inf(1))
+		assert.EqualError(t, err, "+Inf is not a finite value")
+		assert.Equal(t, 0, core.Count())
+	})
+}
+
+func TestWindow(t *testing.T) {
+	t.Run("pass: returns the values in the window in order after evictions", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{1: true},
+			Window: stream.IntPtr(3),
+		})
+		require.NoError(t, err)
+
+		for x := 1.; x <= 5; x++ {
+			err := core.Push(x)
+			require.NoError(t, err)
+		}
+
+		window := core.Window()
+		assert.Equal(t, []float64{3, 4, 5}, window)
+
+		// reading the window leaves it intact for later pushes
+		err = core.Push(6)
+		require.NoError(t, err)
+
+		window = core.Window()
+		assert.Equal(t, []float64{4, 5, 6}, window)
+		mean, err := core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 5, mean)
+	})
+
+	t.Run("pass: returns an empty slice for a global core", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{1: true},
+			Window: stream.IntPtr(0),
+		})
+		require.NoError(t, err)
+
+		err = core.Push(1)
+		require.NoError(t, err)
+
+		window := core.Window()
+		assert.Equal(t, []float64{}, window)
+	})
+
+	t.Run("pass: returns the values in a time window", func(t *testing.T) {
+		duration := time.Minute
+		core, err := NewCore(&CoreConfig{
+			Sums:     SumsConfig{1: true},
+			Window:   stream.IntPtr(0),
+			Duration: &duration,
+		})
+		require.NoError(t, err)
+
+		start := time.Unix(0, 0)
+		for i, x := range []float64{1, 2, 3} {
+			err := core.PushAt(x, start.Add(time.Duration(i)*40*time.Second))
+			require.NoError(t, err)
+		}
+
+		window := core.Window()
+		assert.Equal(t, []float64{2, 3}, window)
+	})
+}
+
+func TestResize(t *testing.T) {
+	newCore := func(t *testing.T, window int, decay *float64) *Core {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(window),
+			Decay:  decay,
+		})
+		require.NoError(t, err)
+		return core
+	}
+
+	// assertSameStats checks that the core has the same statistics
+	// as a fresh core of the same window that was pushed xs
+	assertSameStats := func(t *testing.T, core *Core, decay *float64, xs []float64) {
+		expected := newCore(t, core.window, decay)
+		err := expected.PushBatch(xs)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected.Count(), core.Count())
+		window := core.Window()
+		assert.Equal(t, xs, window)
+
+		expectedMean, err := expected.Mean()
+		require.NoError(t, err)
+		mean, err := core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, expectedMean, mean)
+
+		for k := 2; k <= 4; k++ {
+			expectedSum, err := expected.Sum(k)
+			require.NoError(t, err)
+			sum, err := core.Sum(k)
+			require.NoError(t, err)
+			testutil.Approx(t, expectedSum, sum)
+		}
+	}
+
+	t.Run("pass: shrinking a full window evicts the oldest values", func(t *testing.T) {
+		for _, decay := range []*float64{nil, stream.FloatPtr(0.3)} {
+			core := newCore(t, 6, decay)
+			err := core.PushBatch([]float64{4, 9, 1, 7, 3, 8})
+			require.NoError(t, err)
+
+			err = core.Resize(3)
+			require.NoError(t, err)
+			assertSameStats(t, core, decay, []float64{7, 3, 8})
+
+			// the window keeps sliding at its new size
+			err = core.Push(2)
+			require.NoError(t, err)
+			assertSameStats(t, core, decay, []float64{3, 8, 2})
+		}
+	})
+
+	t.Run("pass: growing a window keeps its values", func(t *testing.T) {
+		core := newCore(t, 2, nil)
+		err := core.PushBatch([]float64{4, 9, 1})
+		require.NoError(t, err)
+
+		err = core.Resize(4)
+		require.NoError(t, err)
+		assertSameStats(t, core, nil, []float64{9, 1})
+
+		err = core.PushBatch([]float64{7, 3, 8})
+		require.NoError(t, err)
+		assertSameStats(t, core, nil, []float64{1, 7, 3, 8})
+	})
+
+	t.Run("pass: resizing to 0 makes the core global", func(t *testing.T) {
+		core := newCore(t, 2, nil)
+		err := core.PushBatch([]float64{4, 9, 1})
+		require.NoError(t, err)
+
+		err = core.Resize(0)
+		require.NoError(t, err)
+		err = core.PushBatch([]float64{7, 3})
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, core.Count())
+		mean, err := core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 5, mean)
+	})
+
+	t.Run("pass: an empty global core can be given a window", func(t *testing.T) {
+		core := newCore(t, 0, nil)
+		err := core.Resize(2)
+		require.NoError(t, err)
+
+		err = core.PushBatch([]float64{4, 9, 1})
+		require.NoError(t, err)
+		assertSameStats(t, core, nil, []float64{9, 1})
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		core := newCore(t, 3, nil)
+		err := core.Resize(-1)
+		assert.EqualError(t, err, "-1 is a negative window")
+	})
+
+	t.Run("fail: global core with values cannot be given a window", func(t *testing.T) {
+		core := newCore(t, 0, nil)
+		err := core.Push(1)
+		require.NoError(t, err)
+
+		err = core.Resize(3)
+		assert.EqualError(t, err, "cannot give a window to a global Core that has seen values")
+	})
+
+	t.Run("fail: core with a time window cannot be resized", func(t *testing.T) {
+		duration := time.Minute
+		core, err := NewCore(&CoreConfig{
+			Sums:     SumsConfig{1: true},
+			Window:   stream.IntPtr(0),
+			Duration: &duration,
+		})
+		require.NoError(t, err)
+
+		err = core.Resize(3)
+		assert.EqualError(t, err, "cannot resize a Core with a time window")
+	})
+}
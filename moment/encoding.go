@@ -0,0 +1,156 @@
+package moment
+
+import (
+	"bytes"
+	"encoding/gob"
+	"time"
+
+	"github.com/gammazero/deque"
+	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream"
+	"github.com/K4Mobility/stream/util/ring"
+)
+
+// coreState is the serialized form of a Core.
+type coreState struct {
+	Mean float64
+	// Total is the sum of the values
+	Total  float64
+	Sums   []float64
+	Count  int
+	Weight float64
+	Window int
+	Decay  *float64
+	// NonFinite configures how non-finite values pushed are handled
+	NonFinite stream.NonFiniteMode
+	// Queue holds the values in the window, from oldest to newest
+	Queue []float64
+	// Used if the Core has a time window; Times holds the
+	// timestamps corresponding to the values in Queue
+	Duration time.Duration
+	Times    []time.Time
+	Latest   time.Time
+}
+
+// MarshalBinary serializes the state of the Core, including the values
+// currently in the window (if one is set), so that it can be restored
+// later via UnmarshalBinary. It satisfies the encoding.BinaryMarshaler interface.
+func (c *Core) MarshalBinary() ([]byte, error) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	state := coreState{
+		Mean:      c.mean,
+		Total:     c.total + c.totalComp,
+		Sums:      c.sums,
+		Count:     c.count,
+		Weight:    c.weight,
+		Window:    c.window,
+		Decay:     c.decay,
+		NonFinite: c.nonFinite,
+	}
+
+	if c.duration != 0 {
+		state.Duration = c.duration
+		state.Latest = c.latest
+		for i := 0; i < c.timed.Len(); i++ {
+			v := c.timed.At(i)
+			state.Queue = append(state.Queue, v.x)
+			state.Times = append(state.Times, v.t)
+		}
+	}
+
+	if c.window != 0 {
+		state.Queue = c.queued()
+	}
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(state)
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding Core")
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores the state of a Core serialized by MarshalBinary,
+// overwriting any existing state. It satisfies the encoding.BinaryUnmarshaler interface.
+func (c *Core) UnmarshalBinary(data []byte) error {
+	var state coreState
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state)
+	if err != nil {
+		return errors.Wrap(err, "error decoding Core")
+	}
+
+	config := &CoreConfig{
+		Window:    &state.Window,
+		Decay:     state.Decay,
+		NonFinite: &state.NonFinite,
+	}
+	if state.Duration != 0 {
+		config.Duration = &state.Duration
+	}
+	err = validateConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "decoded Core is invalid")
+	}
+
+	// every value in a window is queued, so the count must match the queue
+	// for the sums to stay in sync as values are evicted
+	if (state.Window != 0 || state.Duration != 0) && state.Count != len(state.Queue) {
+		return errors.Errorf(
+			"decoded Core has a count of %d for %d queued values",
+			state.Count,
+			len(state.Queue),
+		)
+	} else if state.Duration == 0 && len(state.Queue) > state.Window {
+		return errors.Errorf(
+			"decoded Core has %d queued values, more than its window of %d",
+			len(state.Queue),
+			state.Window,
+		)
+	}
+
+	q := ring.New[float64](uint64(state.Window))
+	timed := deque.New[timedValue]()
+	if state.Duration != 0 {
+		if len(state.Times) != len(state.Queue) {
+			return errors.Errorf(
+				"decoded Core has %d timestamps for %d queued values",
+				len(state.Times),
+				len(state.Queue),
+			)
+		}
+		for i, x := range state.Queue {
+			timed.PushBack(timedValue{x: x, t: state.Times[i]})
+		}
+	} else {
+		for _, x := range state.Queue {
+			err := q.Put(x)
+			if err != nil {
+				return errors.Wrapf(err, "error pushing %f to queue", x)
+			}
+		}
+	}
+
+	sums := make([]float64, len(state.Sums))
+	copy(sums, state.Sums)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.mean = state.Mean
+	c.total = state.Total
+	c.totalComp = 0
+	c.sums = sums
+	c.count = state.Count
+	c.weight = state.Weight
+	c.window = state.Window
+	c.decay = state.Decay
+	c.nonFinite = state.NonFinite
+	c.queue = q
+	c.duration = state.Duration
+	c.timed = timed
+	c.latest = state.Latest
+	c.publish()
+	return nil
+}
@@ -0,0 +1,228 @@
+package moment
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/K4Mobility/stream"
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestCoreMarshalBinary(t *testing.T) {
+	configs := map[string]*CoreConfig{
+		"global": {
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(0),
+		},
+		"window": {
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(3),
+		},
+		"decay": {
+			Sums:   SumsConfig{2: true, 3: true, 4: true},
+			Window: stream.IntPtr(0),
+			Decay:  stream.FloatPtr(0.3),
+		},
+	}
+
+	for name, config := range configs {
+		t.Run("pass: round trip matches uninterrupted core for "+name, func(t *testing.T) {
+			core, err := NewCore(config)
+			require.NoError(t, err)
+
+			for _, x := range []float64{1, 2, 3, 4, 8} {
+				err := core.Push(x)
+				require.NoError(t, err)
+			}
+
+			data, err := core.MarshalBinary()
+			require.NoError(t, err)
+
+			restored := &Core{}
+			err = restored.UnmarshalBinary(data)
+			require.NoError(t, err)
+
+			for _, x := range []float64{-2, 5, 0.5, 7} {
+				err := core.Push(x)
+				require.NoError(t, err)
+
+				err = restored.Push(x)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, core.count, restored.count)
+			assert.Equal(t, core.window, restored.window)
+			assert.Equal(t, core.decay, restored.decay)
+			assert.Equal(t, core.queue.Len(), restored.queue.Len())
+			testutil.Approx(t, core.mean, restored.mean)
+			testutil.Approx(t, core.total+core.totalComp, restored.total+restored.totalComp)
+			require.Equal(t, len(core.sums), len(restored.sums))
+			for k := range core.sums {
+				testutil.Approx(t, core.sums[k], restored.sums[k])
+			}
+		})
+	}
+
+	t.Run("pass: marshaling preserves the window", func(t *testing.T) {
+		core, err := NewCore(configs["window"])
+		require.NoError(t, err)
+
+		for _, x := range []float64{1, 2, 3, 4} {
+			err := core.Push(x)
+			require.NoError(t, err)
+		}
+
+		_, err = core.MarshalBinary()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(3), core.queue.Len())
+		for _, expected := range []float64{2, 3, 4} {
+			x, err := core.queue.Get()
+			require.NoError(t, err)
+			assert.Equal(t, expected, x)
+		}
+	})
+
+	t.Run("pass: marshaling preserves the non-finite mode", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{
+			Sums:      SumsConfig{1: true},
+			Window:    stream.IntPtr(0),
+			NonFinite: stream.NonFiniteModePtr(stream.NonFiniteSkip),
+		})
+		require.NoError(t, err)
+
+		data, err := core.MarshalBinary()
+		require.NoError(t, err)
+
+		restored := &Core{}
+		err = restored.UnmarshalBinary(data)
+		require.NoError(t, err)
+		assert.Equal(t, stream.NonFiniteSkip, restored.nonFinite)
+	})
+}
+
+func TestCoreMarshalBinaryTimed(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	core := newTimedCore(t, 5*time.Minute)
+	for i, x := range []float64{1, 2, 3, 4, 8} {
+		err := core.PushAt(x, start.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, err)
+	}
+
+	data, err := core.MarshalBinary()
+	require.NoError(t, err)
+
+	restored := &Core{}
+	err = restored.UnmarshalBinary(data)
+	require.NoError(t, err)
+
+	for i, x := range []float64{-2, 5, 0.5} {
+		ts := start.Add(time.Duration(i+6) * time.Minute)
+		err := core.PushAt(x, ts)
+		require.NoError(t, err)
+
+		err = restored.PushAt(x, ts)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, core.count, restored.count)
+	assert.Equal(t, core.duration, restored.duration)
+	assert.Equal(t, core.timed.Len(), restored.timed.Len())
+	assert.True(t, core.latest.Equal(restored.latest))
+	testutil.Approx(t, core.mean, restored.mean)
+	for k := range core.sums {
+		testutil.Approx(t, core.sums[k], restored.sums[k])
+	}
+
+	// out of order timestamps are still rejected after restoring
+	err = restored.PushAt(1, start)
+	testutil.ContainsError(t, err, "precedes the latest timestamp")
+}
+
+func TestCoreUnmarshalBinary(t *testing.T) {
+	t.Run("fail: invalid data returns error", func(t *testing.T) {
+		core := &Core{}
+		err := core.UnmarshalBinary([]byte("invalid"))
+		testutil.ContainsError(t, err, "error decoding Core")
+	})
+
+	encode := func(t *testing.T, state coreState) []byte {
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(state)
+		require.NoError(t, err)
+		return buf.Bytes()
+	}
+
+	valid := func() coreState {
+		return coreState{
+			Mean:   2,
+			Total:  6,
+			Sums:   []float64{0, 0, 2},
+			Count:  3,
+			Weight: 3,
+			Window: 3,
+			Queue:  []float64{1, 2, 3},
+		}
+	}
+
+	t.Run("pass: valid state is restored", func(t *testing.T) {
+		core := &Core{}
+		err := core.UnmarshalBinary(encode(t, valid()))
+		require.NoError(t, err)
+		assert.Equal(t, 3, core.Count())
+	})
+
+	t.Run("fail: corrupted state returns error", func(t *testing.T) {
+		corruptions := map[string]struct {
+			corrupt  func(state *coreState)
+			expected string
+		}{
+			"count not matching the queue": {
+				corrupt:  func(state *coreState) { state.Count = 5 },
+				expected: "decoded Core has a count of 5 for 3 queued values",
+			},
+			"more values queued than the window": {
+				corrupt: func(state *coreState) {
+					state.Queue = []float64{1, 2, 3, 4}
+					state.Count = 4
+				},
+				expected: "decoded Core has 4 queued values, more than its window of 3",
+			},
+			"negative window": {
+				corrupt:  func(state *coreState) { state.Window = -1 },
+				expected: "config has a negative window of -1",
+			},
+			"decay out of range": {
+				corrupt:  func(state *coreState) { state.Decay = stream.FloatPtr(1.5) },
+				expected: "config has a decay of 1.500000, which is not in (0, 1)",
+			},
+			"negative duration": {
+				corrupt: func(state *coreState) {
+					state.Window = 0
+					state.Duration = -time.Minute
+				},
+				expected: "config has a nonpositive duration of -1m0s",
+			},
+			"unknown non-finite mode": {
+				corrupt:  func(state *coreState) { state.NonFinite = 7 },
+				expected: "config has an unknown non-finite mode of 7",
+			},
+		}
+
+		for name, c := range corruptions {
+			t.Run(name, func(t *testing.T) {
+				state := valid()
+				c.corrupt(&state)
+
+				core := &Core{}
+				err := core.UnmarshalBinary(encode(t, state))
+				testutil.ContainsError(t, err, c.expected)
+			})
+		}
+	})
+}
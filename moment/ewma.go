@@ -47,7 +47,7 @@ func (a *EWMA) String() string {
 // Push adds a new value for EWMA to consume.
 func (a *EWMA) Push(x float64) error {
 	if !a.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
 	err := a.core.Push(x)
@@ -60,15 +60,15 @@ func (a *EWMA) Push(x float64) error {
 // Value returns the value of the exponentially weighted moving average.
 func (a *EWMA) Value() (float64, error) {
 	if !a.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	}
 
 	a.core.RLock()
 	defer a.core.RUnlock()
 
-	ewma, err := a.core.Mean()
+	ewma, err := a.core.UnsafeMean()
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving sum")
+		return 0, fmt.Errorf("error retrieving sum: %w", err)
 	}
 	return ewma, nil
 }
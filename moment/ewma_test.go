@@ -39,6 +39,7 @@ func (s *EWMAPushSuite) TestPushFailOnNullCore() {
 	ewma := NewEWMA(0.3)
 	err := ewma.Push(0.)
 	testutil.ContainsError(s.T(), err, "Core is not set")
+	s.Assert().ErrorIs(err, ErrorCoreNotSet)
 }
 
 type EWMAValueSuite struct {
@@ -103,3 +104,53 @@ func TestEWMAString(t *testing.T) {
 	expectedString := "moment.EWMA_{decay:0.3}"
 	assert.Equal(t, expectedString, ewma.String())
 }
+
+func TestEWMAConvergence(t *testing.T) {
+	t.Run("pass: converges to a constant input", func(t *testing.T) {
+		ewma := NewEWMA(0.3)
+		err := Init(ewma)
+		require.NoError(t, err)
+
+		for i := 0; i < 200; i++ {
+			err := ewma.Push(5)
+			require.NoError(t, err)
+		}
+
+		value, err := ewma.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 5, value)
+	})
+
+	t.Run("pass: converges to the new level after a step input", func(t *testing.T) {
+		ewma := NewEWMA(0.3)
+		err := Init(ewma)
+		require.NoError(t, err)
+
+		for i := 0; i < 200; i++ {
+			err := ewma.Push(0)
+			require.NoError(t, err)
+		}
+		err = ewma.Push(10)
+		require.NoError(t, err)
+
+		value, err := ewma.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 3, value)
+
+		for i := 0; i < 200; i++ {
+			err := ewma.Push(10)
+			require.NoError(t, err)
+		}
+
+		value, err = ewma.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 10, value)
+	})
+
+	t.Run("fail: decay outside of (0, 1) is invalid", func(t *testing.T) {
+		for _, decay := range []float64{0, 1, -0.5, 1.5} {
+			err := Init(NewEWMA(decay))
+			testutil.ContainsError(t, err, "which is not in (0, 1)")
+		}
+	})
+}
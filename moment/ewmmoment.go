@@ -59,7 +59,7 @@ func (m *EWMMoment) String() string {
 // Push adds a new value for EWMMoment to consume.
 func (m *EWMMoment) Push(x float64) error {
 	if !m.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
 	err := m.core.Push(x)
@@ -72,12 +72,12 @@ func (m *EWMMoment) Push(x float64) error {
 // Value returns the value of the kth exponentially weighted sample central moment.
 func (m *EWMMoment) Value() (float64, error) {
 	if !m.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	}
 
 	moment, err := m.core.Sum(m.k)
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving sum")
+		return 0, fmt.Errorf("error retrieving sum: %w", err)
 	}
 
 	return moment, nil
@@ -40,6 +40,7 @@ func (s *EWMMomentPushSuite) TestPushFailOnNullCore() {
 	moment := NewEWMMoment(2, 0.3)
 	err := moment.Push(0.)
 	testutil.ContainsError(s.T(), err, "Core is not set")
+	s.Assert().ErrorIs(err, ErrorCoreNotSet)
 }
 
 type EWMMomentValueSuite struct {
@@ -42,7 +42,7 @@ func (s *EWMStd) String() string {
 // Push adds a new value for EWMStd to consume.
 func (s *EWMStd) Push(x float64) error {
 	if !s.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
 	err := s.variance.Push(x)
@@ -55,12 +55,12 @@ func (s *EWMStd) Push(x float64) error {
 // Value returns the value of the exponentially weighted sample standard deviation.
 func (s *EWMStd) Value() (float64, error) {
 	if !s.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	}
 
 	variance, err := s.variance.Value()
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving 2nd moment")
+		return 0, fmt.Errorf("error retrieving 2nd moment: %w", err)
 	}
 	return math.Sqrt(variance), nil
 }
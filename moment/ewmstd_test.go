@@ -40,6 +40,7 @@ func (s *EWMStdPushSuite) TestPushFailOnNullCore() {
 	std := NewEWMStd(0.3)
 	err := std.Push(0.)
 	testutil.ContainsError(s.T(), err, "Core is not set")
+	s.Assert().ErrorIs(err, ErrorCoreNotSet)
 }
 
 type EWMStdValueSuite struct {
@@ -107,3 +108,34 @@ func TestEWMStdString(t *testing.T) {
 	expectedString := "moment.EWMStd_{decay:0.3}"
 	assert.Equal(t, expectedString, std.String())
 }
+
+func TestEWMStdConvergence(t *testing.T) {
+	std := NewEWMStd(0.3)
+	err := Init(std)
+	require.NoError(t, err)
+
+	for i := 0; i < 200; i++ {
+		err := std.Push(0)
+		require.NoError(t, err)
+	}
+
+	value, err := std.Value()
+	require.NoError(t, err)
+	testutil.Approx(t, 0, value)
+
+	err = std.Push(10)
+	require.NoError(t, err)
+
+	value, err = std.Value()
+	require.NoError(t, err)
+	testutil.Approx(t, math.Sqrt(21), value)
+
+	for i := 0; i < 200; i++ {
+		err := std.Push(10)
+		require.NoError(t, err)
+	}
+
+	value, err = std.Value()
+	require.NoError(t, err)
+	testutil.Approx(t, 0, value)
+}
@@ -0,0 +1,72 @@
+package moment
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// EWMVar is a metric that tracks the exponentially weighted sample variance.
+type EWMVar struct {
+	variance *EWMMoment
+}
+
+// NewEWMVar instantiates an EWMVar struct.
+func NewEWMVar(decay float64) *EWMVar {
+	return &EWMVar{variance: NewEWMMoment(2, decay)}
+}
+
+// SetCore sets the Core.
+func (v *EWMVar) SetCore(c *Core) {
+	v.variance.SetCore(c)
+}
+
+// IsSetCore returns if the core has been set.
+func (v *EWMVar) IsSetCore() bool {
+	return v.variance.IsSetCore()
+}
+
+// Config returns the CoreConfig needed.
+func (v *EWMVar) Config() *CoreConfig {
+	return v.variance.Config()
+}
+
+// String returns a string representation of the metric.
+func (v *EWMVar) String() string {
+	name := "moment.EWMVar"
+	decay := fmt.Sprintf("decay:%v", *v.variance.Config().Decay)
+	return fmt.Sprintf("%s_{%s}", name, decay)
+}
+
+// Push adds a new value for EWMVar to consume.
+func (v *EWMVar) Push(x float64) error {
+	if !v.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := v.variance.Push(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the exponentially weighted sample variance.
+func (v *EWMVar) Value() (float64, error) {
+	if !v.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	variance, err := v.variance.Value()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving 2nd moment: %w", err)
+	}
+	return variance, nil
+}
+
+// Clear resets the metric.
+func (v *EWMVar) Clear() {
+	if v.IsSetCore() {
+		v.variance.Clear()
+	}
+}
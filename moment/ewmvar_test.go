@@ -0,0 +1,160 @@
+package moment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewEWMVar(t *testing.T) {
+	v := NewEWMVar(0.3)
+	assert.Equal(t, NewEWMMoment(2, 0.3), v.variance)
+}
+
+type EWMVarPushSuite struct {
+	suite.Suite
+	variance *EWMVar
+}
+
+func TestEWMVarPushSuite(t *testing.T) {
+	suite.Run(t, &EWMVarPushSuite{})
+}
+
+func (s *EWMVarPushSuite) SetupTest() {
+	s.variance = NewEWMVar(0.3)
+	err := Init(s.variance)
+	s.Require().NoError(err)
+}
+
+func (s *EWMVarPushSuite) TestPushSuccess() {
+	err := s.variance.Push(3.)
+	s.NoError(err)
+}
+
+func (s *EWMVarPushSuite) TestPushFailOnNullCore() {
+	v := NewEWMVar(0.3)
+	err := v.Push(0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+	s.Assert().ErrorIs(err, ErrorCoreNotSet)
+}
+
+type EWMVarValueSuite struct {
+	suite.Suite
+	variance *EWMVar
+}
+
+func TestEWMVarValueSuite(t *testing.T) {
+	suite.Run(t, &EWMVarValueSuite{})
+}
+
+func (s *EWMVarValueSuite) SetupTest() {
+	s.variance = NewEWMVar(0.3)
+	err := Init(s.variance)
+	s.Require().NoError(err)
+
+	xs := []float64{3, 4, 8}
+	for _, x := range xs {
+		err := s.variance.Push(x)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *EWMVarValueSuite) TestValueSuccess() {
+	value, err := s.variance.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 4.7859, value)
+}
+
+func (s *EWMVarValueSuite) TestValueFailOnNullCore() {
+	v := NewEWMVar(0.3)
+	_, err := v.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *EWMVarValueSuite) TestValueFailIfNoValuesSeen() {
+	v := NewEWMVar(0.3)
+	err := Init(v)
+	s.Require().NoError(err)
+
+	_, err = v.Value()
+	testutil.ContainsError(s.T(), err, "no values seen yet")
+}
+
+func TestEWMVarClear(t *testing.T) {
+	v := NewEWMVar(0.3)
+	err := Init(v)
+	require.NoError(t, err)
+
+	xs := []float64{3, 4, 8}
+	for _, x := range xs {
+		err := v.Push(x)
+		require.NoError(t, err)
+	}
+
+	v.Clear()
+	expectedSums := []float64{0, 0, 0}
+	assert.Equal(t, expectedSums, v.variance.core.sums)
+	assert.Equal(t, int(0), v.variance.core.count)
+	assert.Equal(t, uint64(0), v.variance.core.queue.Len())
+}
+
+func TestEWMVarString(t *testing.T) {
+	v := NewEWMVar(0.3)
+	expectedString := "moment.EWMVar_{decay:0.3}"
+	assert.Equal(t, expectedString, v.String())
+}
+
+func TestEWMVarConvergence(t *testing.T) {
+	t.Run("pass: converges to 0 for a constant input", func(t *testing.T) {
+		v := NewEWMVar(0.3)
+		err := Init(v)
+		require.NoError(t, err)
+
+		for i := 0; i < 200; i++ {
+			err := v.Push(5)
+			require.NoError(t, err)
+		}
+
+		value, err := v.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 0, value)
+	})
+
+	t.Run("pass: spikes after a step input, then converges to 0", func(t *testing.T) {
+		v := NewEWMVar(0.3)
+		err := Init(v)
+		require.NoError(t, err)
+
+		for i := 0; i < 200; i++ {
+			err := v.Push(0)
+			require.NoError(t, err)
+		}
+		err = v.Push(10)
+		require.NoError(t, err)
+
+		// decay * (1 - decay) * 10^2
+		value, err := v.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 21, value)
+
+		for i := 0; i < 200; i++ {
+			err := v.Push(10)
+			require.NoError(t, err)
+		}
+
+		value, err = v.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 0, value)
+	})
+
+	t.Run("fail: decay outside of (0, 1) is invalid", func(t *testing.T) {
+		for _, decay := range []float64{0, 1, -0.5, 1.5} {
+			err := Init(NewEWMVar(decay))
+			testutil.ContainsError(t, err, "which is not in (0, 1)")
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package moment
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// GeometricMean is a metric that tracks the geometric mean of a stream of strictly
+// positive values, by tracking the mean of their logarithms. Like Mean it can track
+// the global geometric mean or the geometric mean over a rolling window; it can
+// also track an exponentially weighted geometric mean, like EWMA.
+type GeometricMean struct {
+	window int
+	decay  *float64
+	core   *Core
+}
+
+// NewGeometricMean instantiates a GeometricMean struct.
+func NewGeometricMean(window int) *GeometricMean {
+	return &GeometricMean{window: window}
+}
+
+// NewGlobalGeometricMean instantiates a global GeometricMean struct.
+// This is equivalent to calling NewGeometricMean(0).
+func NewGlobalGeometricMean() *GeometricMean {
+	return NewGeometricMean(0)
+}
+
+// NewEWGeometricMean instantiates an exponentially weighted GeometricMean struct.
+func NewEWGeometricMean(decay float64) *GeometricMean {
+	return &GeometricMean{decay: &decay}
+}
+
+// SetCore sets the Core.
+func (g *GeometricMean) SetCore(c *Core) {
+	g.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (g *GeometricMean) IsSetCore() bool {
+	return g.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (g *GeometricMean) Config() *CoreConfig {
+	return &CoreConfig{
+		Sums:   SumsConfig{1: true},
+		Window: &g.window,
+		Decay:  g.decay,
+	}
+}
+
+// String returns a string representation of the metric.
+func (g *GeometricMean) String() string {
+	name := "moment.GeometricMean"
+	if g.decay != nil {
+		return fmt.Sprintf("%s_{decay:%v}", name, *g.decay)
+	}
+	return fmt.Sprintf("%s_{window:%v}", name, g.window)
+}
+
+// Push adds a new value for GeometricMean to consume.
+func (g *GeometricMean) Push(x float64) error {
+	if !g.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	if x <= 0 {
+		return errors.Errorf("%f is not a positive value", x)
+	}
+
+	err := g.core.Push(math.Log(x))
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the geometric mean.
+func (g *GeometricMean) Value() (float64, error) {
+	if !g.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	mean, err := g.core.Mean()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving mean: %w", err)
+	}
+	return math.Exp(mean), nil
+}
+
+// Clear resets the metric.
+func (g *GeometricMean) Clear() {
+	if g.IsSetCore() {
+		g.core.Clear()
+	}
+}
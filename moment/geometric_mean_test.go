@@ -0,0 +1,172 @@
+package moment
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewGeometricMean(t *testing.T) {
+	g := NewGeometricMean(3)
+	assert.Equal(t, 3, g.window)
+	assert.Nil(t, g.decay)
+	assert.Nil(t, g.core)
+}
+
+func TestNewGlobalGeometricMean(t *testing.T) {
+	g := NewGeometricMean(0)
+	globalG := NewGlobalGeometricMean()
+	assert.Equal(t, g, globalG)
+}
+
+func TestNewEWGeometricMean(t *testing.T) {
+	g := NewEWGeometricMean(0.3)
+	assert.Equal(t, 0, g.window)
+	assert.Equal(t, 0.3, *g.decay)
+}
+
+type GeometricMeanPushSuite struct {
+	suite.Suite
+	g *GeometricMean
+}
+
+func TestGeometricMeanPushSuite(t *testing.T) {
+	suite.Run(t, &GeometricMeanPushSuite{})
+}
+
+func (s *GeometricMeanPushSuite) SetupTest() {
+	s.g = NewGeometricMean(3)
+	err := Init(s.g)
+	s.Require().NoError(err)
+}
+
+func (s *GeometricMeanPushSuite) TestPushSuccess() {
+	err := s.g.Push(3.)
+	s.NoError(err)
+}
+
+func (s *GeometricMeanPushSuite) TestPushFailOnNullCore() {
+	g := NewGeometricMean(3)
+	err := g.Push(1.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *GeometricMeanPushSuite) TestPushFailOnNonpositiveValue() {
+	err := s.g.Push(0.)
+	testutil.ContainsError(s.T(), err, fmt.Sprintf("%f is not a positive value", 0.))
+
+	err = s.g.Push(-2.)
+	testutil.ContainsError(s.T(), err, fmt.Sprintf("%f is not a positive value", -2.))
+
+	s.Equal(0, s.g.core.Count())
+}
+
+func (s *GeometricMeanPushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.g.core.queue.Dispose()
+
+	err := s.g.Push(3.)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+type GeometricMeanValueSuite struct {
+	suite.Suite
+	g       *GeometricMean
+	globalG *GeometricMean
+	ewG     *GeometricMean
+}
+
+func TestGeometricMeanValueSuite(t *testing.T) {
+	suite.Run(t, &GeometricMeanValueSuite{})
+}
+
+func (s *GeometricMeanValueSuite) SetupTest() {
+	s.g = NewGeometricMean(3)
+	err := Init(s.g)
+	s.Require().NoError(err)
+
+	s.globalG = NewGlobalGeometricMean()
+	err = Init(s.globalG)
+	s.Require().NoError(err)
+
+	s.ewG = NewEWGeometricMean(0.5)
+	err = Init(s.ewG)
+	s.Require().NoError(err)
+
+	xs := []float64{1, 2, 4, 8, 1}
+	for _, x := range xs {
+		err := s.g.Push(x)
+		s.Require().NoError(err)
+
+		err = s.globalG.Push(x)
+		s.Require().NoError(err)
+
+		err = s.ewG.Push(x)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *GeometricMeanValueSuite) TestValueSuccess() {
+	// window is {4, 8, 1}
+	value, err := s.g.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), math.Cbrt(32), value)
+}
+
+func (s *GeometricMeanValueSuite) TestValueSuccessGlobal() {
+	value, err := s.globalG.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), math.Pow(64, 1./5.), value)
+}
+
+func (s *GeometricMeanValueSuite) TestValueSuccessDecay() {
+	// log2 of the values are 0, 1, 2, 3, 0, and their EWMA is 1.0625
+	value, err := s.ewG.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), math.Pow(2, 1.0625), value)
+}
+
+func (s *GeometricMeanValueSuite) TestValueFailOnNullCore() {
+	g := NewGeometricMean(3)
+	_, err := g.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *GeometricMeanValueSuite) TestValueFailIfNoValuesSeen() {
+	g := NewGeometricMean(3)
+	err := Init(g)
+	s.Require().NoError(err)
+
+	_, err = g.Value()
+	testutil.ContainsError(s.T(), err, "no values seen yet")
+}
+
+func TestGeometricMeanClear(t *testing.T) {
+	g := NewGeometricMean(3)
+	err := Init(g)
+	require.NoError(t, err)
+
+	xs := []float64{1, 2, 4, 8, 1}
+	for _, x := range xs {
+		err := g.Push(x)
+		require.NoError(t, err)
+	}
+
+	g.Clear()
+	assert.Equal(t, int(0), g.core.count)
+	assert.Equal(t, uint64(0), g.core.queue.Len())
+}
+
+func TestGeometricMeanString(t *testing.T) {
+	g := NewGeometricMean(3)
+	assert.Equal(t, "moment.GeometricMean_{window:3}", g.String())
+
+	g = NewEWGeometricMean(0.3)
+	assert.Equal(t, "moment.GeometricMean_{decay:0.3}", g.String())
+}
@@ -0,0 +1,102 @@
+package moment
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// HarmonicMean is a metric that tracks the harmonic mean of a stream of nonzero
+// values, by tracking the mean of their reciprocals. Like Mean it can track the
+// global harmonic mean or the harmonic mean over a rolling window; it can also
+// track an exponentially weighted harmonic mean, like EWMA.
+type HarmonicMean struct {
+	window int
+	decay  *float64
+	core   *Core
+}
+
+// NewHarmonicMean instantiates a HarmonicMean struct.
+func NewHarmonicMean(window int) *HarmonicMean {
+	return &HarmonicMean{window: window}
+}
+
+// NewGlobalHarmonicMean instantiates a global HarmonicMean struct.
+// This is equivalent to calling NewHarmonicMean(0).
+func NewGlobalHarmonicMean() *HarmonicMean {
+	return NewHarmonicMean(0)
+}
+
+// NewEWHarmonicMean instantiates an exponentially weighted HarmonicMean struct.
+func NewEWHarmonicMean(decay float64) *HarmonicMean {
+	return &HarmonicMean{decay: &decay}
+}
+
+// SetCore sets the Core.
+func (h *HarmonicMean) SetCore(c *Core) {
+	h.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (h *HarmonicMean) IsSetCore() bool {
+	return h.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (h *HarmonicMean) Config() *CoreConfig {
+	return &CoreConfig{
+		Sums:   SumsConfig{1: true},
+		Window: &h.window,
+		Decay:  h.decay,
+	}
+}
+
+// String returns a string representation of the metric.
+func (h *HarmonicMean) String() string {
+	name := "moment.HarmonicMean"
+	if h.decay != nil {
+		return fmt.Sprintf("%s_{decay:%v}", name, *h.decay)
+	}
+	return fmt.Sprintf("%s_{window:%v}", name, h.window)
+}
+
+// Push adds a new value for HarmonicMean to consume.
+func (h *HarmonicMean) Push(x float64) error {
+	if !h.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	if x == 0 {
+		return errors.New("cannot push 0, which has no reciprocal")
+	}
+
+	err := h.core.Push(1 / x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the harmonic mean.
+func (h *HarmonicMean) Value() (float64, error) {
+	if !h.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	mean, err := h.core.Mean()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving mean: %w", err)
+	}
+
+	if mean == 0 {
+		return 0, errors.New("mean of reciprocals is zero")
+	}
+	return 1 / mean, nil
+}
+
+// Clear resets the metric.
+func (h *HarmonicMean) Clear() {
+	if h.IsSetCore() {
+		h.core.Clear()
+	}
+}
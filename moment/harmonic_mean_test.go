@@ -0,0 +1,180 @@
+package moment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewHarmonicMean(t *testing.T) {
+	h := NewHarmonicMean(3)
+	assert.Equal(t, 3, h.window)
+	assert.Nil(t, h.decay)
+	assert.Nil(t, h.core)
+}
+
+func TestNewGlobalHarmonicMean(t *testing.T) {
+	h := NewHarmonicMean(0)
+	globalH := NewGlobalHarmonicMean()
+	assert.Equal(t, h, globalH)
+}
+
+func TestNewEWHarmonicMean(t *testing.T) {
+	h := NewEWHarmonicMean(0.3)
+	assert.Equal(t, 0, h.window)
+	assert.Equal(t, 0.3, *h.decay)
+}
+
+type HarmonicMeanPushSuite struct {
+	suite.Suite
+	h *HarmonicMean
+}
+
+func TestHarmonicMeanPushSuite(t *testing.T) {
+	suite.Run(t, &HarmonicMeanPushSuite{})
+}
+
+func (s *HarmonicMeanPushSuite) SetupTest() {
+	s.h = NewHarmonicMean(3)
+	err := Init(s.h)
+	s.Require().NoError(err)
+}
+
+func (s *HarmonicMeanPushSuite) TestPushSuccess() {
+	err := s.h.Push(3.)
+	s.NoError(err)
+}
+
+func (s *HarmonicMeanPushSuite) TestPushFailOnNullCore() {
+	h := NewHarmonicMean(3)
+	err := h.Push(1.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *HarmonicMeanPushSuite) TestPushFailOnZero() {
+	err := s.h.Push(0.)
+	testutil.ContainsError(s.T(), err, "cannot push 0, which has no reciprocal")
+	s.Equal(0, s.h.core.Count())
+}
+
+func (s *HarmonicMeanPushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.h.core.queue.Dispose()
+
+	err := s.h.Push(3.)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+type HarmonicMeanValueSuite struct {
+	suite.Suite
+	h       *HarmonicMean
+	globalH *HarmonicMean
+	ewH     *HarmonicMean
+}
+
+func TestHarmonicMeanValueSuite(t *testing.T) {
+	suite.Run(t, &HarmonicMeanValueSuite{})
+}
+
+func (s *HarmonicMeanValueSuite) SetupTest() {
+	s.h = NewHarmonicMean(3)
+	err := Init(s.h)
+	s.Require().NoError(err)
+
+	s.globalH = NewGlobalHarmonicMean()
+	err = Init(s.globalH)
+	s.Require().NoError(err)
+
+	s.ewH = NewEWHarmonicMean(0.5)
+	err = Init(s.ewH)
+	s.Require().NoError(err)
+
+	xs := []float64{1, 2, 4, 8, 1}
+	for _, x := range xs {
+		err := s.h.Push(x)
+		s.Require().NoError(err)
+
+		err = s.globalH.Push(x)
+		s.Require().NoError(err)
+
+		err = s.ewH.Push(x)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *HarmonicMeanValueSuite) TestValueSuccess() {
+	// window is {4, 8, 1}
+	value, err := s.h.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 3./(1./4.+1./8.+1.), value)
+}
+
+func (s *HarmonicMeanValueSuite) TestValueSuccessGlobal() {
+	value, err := s.globalH.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 5./(1.+1./2.+1./4.+1./8.+1.), value)
+}
+
+func (s *HarmonicMeanValueSuite) TestValueSuccessDecay() {
+	// the EWMA of the reciprocals is 0.65625
+	value, err := s.ewH.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 1./0.65625, value)
+}
+
+func (s *HarmonicMeanValueSuite) TestValueFailOnNullCore() {
+	h := NewHarmonicMean(3)
+	_, err := h.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *HarmonicMeanValueSuite) TestValueFailIfMeanOfReciprocalsIsZero() {
+	h := NewHarmonicMean(3)
+	err := Init(h)
+	s.Require().NoError(err)
+
+	for _, x := range []float64{2, -2} {
+		err := h.Push(x)
+		s.Require().NoError(err)
+	}
+
+	_, err = h.Value()
+	testutil.ContainsError(s.T(), err, "mean of reciprocals is zero")
+}
+
+func (s *HarmonicMeanValueSuite) TestValueFailIfNoValuesSeen() {
+	h := NewHarmonicMean(3)
+	err := Init(h)
+	s.Require().NoError(err)
+
+	_, err = h.Value()
+	testutil.ContainsError(s.T(), err, "no values seen yet")
+}
+
+func TestHarmonicMeanClear(t *testing.T) {
+	h := NewHarmonicMean(3)
+	err := Init(h)
+	require.NoError(t, err)
+
+	xs := []float64{1, 2, 4, 8, 1}
+	for _, x := range xs {
+		err := h.Push(x)
+		require.NoError(t, err)
+	}
+
+	h.Clear()
+	assert.Equal(t, int(0), h.core.count)
+	assert.Equal(t, uint64(0), h.core.queue.Len())
+}
+
+func TestHarmonicMeanString(t *testing.T) {
+	h := NewHarmonicMean(3)
+	assert.Equal(t, "moment.HarmonicMean_{window:3}", h.String())
+
+	h = NewEWHarmonicMean(0.3)
+	assert.Equal(t, "moment.HarmonicMean_{decay:0.3}", h.String())
+}
@@ -0,0 +1,112 @@
+package moment
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// InformationRatio is a metric that tracks the information ratio of a portfolio
+// against a benchmark, i.e. the mean of the differences between their returns
+// divided by the tracking error. Like TrackingError, it consumes a pair of returns
+// at a time, so it does not satisfy the Metric interface, but it is a CoreWrapper
+// and should be passed into Init().
+type InformationRatio struct {
+	window int
+	core   *Core
+}
+
+// NewInformationRatio instantiates an InformationRatio struct.
+func NewInformationRatio(window int) *InformationRatio {
+	return &InformationRatio{window: window}
+}
+
+// NewGlobalInformationRatio instantiates a global InformationRatio struct.
+// This is equivalent to calling NewInformationRatio(0).
+func NewGlobalInformationRatio() *InformationRatio {
+	return NewInformationRatio(0)
+}
+
+// SetCore sets the Core.
+func (r *InformationRatio) SetCore(c *Core) {
+	r.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (r *InformationRatio) IsSetCore() bool {
+	return r.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (r *InformationRatio) Config() *CoreConfig {
+	return &CoreConfig{
+		Sums:   SumsConfig{2: true},
+		Window: &r.window,
+	}
+}
+
+// String returns a string representation of the metric.
+func (r *InformationRatio) String() string {
+	name := "moment.InformationRatio"
+	window := fmt.Sprintf("window:%v", r.window)
+	return fmt.Sprintf("%s_{%s}", name, window)
+}
+
+// Push adds a new pair of portfolio and benchmark returns for InformationRatio to consume.
+func (r *InformationRatio) Push(portfolio float64, benchmark float64) error {
+	if !r.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := r.core.Push(portfolio - benchmark)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the information ratio.
+func (r *InformationRatio) Value() (float64, error) {
+	if !r.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	r.core.RLock()
+	defer r.core.RUnlock()
+
+	mean, err := r.core.UnsafeMean()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving mean: %w", err)
+	}
+
+	// the tracking error is a sample standard deviation, which is undefined for a single value
+	count := r.core.UnsafeCount()
+	if count < 2 {
+		return 0, errors.Errorf("InformationRatio needs at least 2 values: got %d", count)
+	}
+
+	sum, err := r.core.UnsafeSum(2)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving 2nd moment: %w", err)
+	}
+
+	variance, err := clampVariance(sum/(r.core.UnsafeWeightSum()-1), NegativeVarianceTolerance)
+	if err != nil {
+		return 0, wrapSentinel(ErrorRetrievingVariance, err)
+	}
+
+	trackingError := math.Sqrt(variance)
+	if trackingError == 0 {
+		return 0, errors.New("tracking error is zero")
+	}
+
+	return mean / trackingError, nil
+}
+
+// Clear resets the metric.
+func (r *InformationRatio) Clear() {
+	if r.IsSetCore() {
+		r.core.Clear()
+	}
+}
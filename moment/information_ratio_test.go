@@ -0,0 +1,172 @@
+package moment
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewInformationRatio(t *testing.T) {
+	r := NewInformationRatio(3)
+	assert.Equal(t, 3, r.window)
+	assert.Nil(t, r.core)
+}
+
+func TestNewGlobalInformationRatio(t *testing.T) {
+	r := NewInformationRatio(0)
+	globalR := NewGlobalInformationRatio()
+	assert.Equal(t, r, globalR)
+}
+
+type InformationRatioPushSuite struct {
+	suite.Suite
+	r *InformationRatio
+}
+
+func TestInformationRatioPushSuite(t *testing.T) {
+	suite.Run(t, &InformationRatioPushSuite{})
+}
+
+func (s *InformationRatioPushSuite) SetupTest() {
+	s.r = NewInformationRatio(3)
+	err := Init(s.r)
+	s.Require().NoError(err)
+}
+
+func (s *InformationRatioPushSuite) TestPushSuccess() {
+	err := s.r.Push(3., 1.)
+	s.Require().NoError(err)
+
+	mean, err := s.r.core.Mean()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 2., mean)
+}
+
+func (s *InformationRatioPushSuite) TestPushFailOnNullCore() {
+	r := NewInformationRatio(3)
+	err := r.Push(0., 0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *InformationRatioPushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.r.core.queue.Dispose()
+
+	err := s.r.Push(3., 1.)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+type InformationRatioValueSuite struct {
+	suite.Suite
+	r *InformationRatio
+}
+
+func TestInformationRatioValueSuite(t *testing.T) {
+	suite.Run(t, &InformationRatioValueSuite{})
+}
+
+func (s *InformationRatioValueSuite) SetupTest() {
+	s.r = NewInformationRatio(3)
+	err := Init(s.r)
+	s.Require().NoError(err)
+
+	// differences are 1, 2, 3, 4, 8
+	portfolio := []float64{2, 1, 5, 3, 10}
+	benchmark := []float64{1, -1, 2, -1, 2}
+	for i := range portfolio {
+		err := s.r.Push(portfolio[i], benchmark[i])
+		s.Require().NoError(err)
+	}
+}
+
+func (s *InformationRatioValueSuite) TestValueSuccess() {
+	value, err := s.r.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 5./math.Sqrt(7.), value)
+}
+
+func (s *InformationRatioValueSuite) TestValueFailOnNullCore() {
+	r := NewInformationRatio(3)
+	_, err := r.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *InformationRatioValueSuite) TestValueFailIfNoValuesSeen() {
+	r := NewInformationRatio(3)
+	err := Init(r)
+	s.Require().NoError(err)
+
+	_, err = r.Value()
+	testutil.ContainsError(s.T(), err, "no values seen yet")
+}
+
+func (s *InformationRatioValueSuite) TestValueFailIfOneValueSeen() {
+	r := NewInformationRatio(3)
+	err := Init(r)
+	s.Require().NoError(err)
+
+	err = r.Push(3., 1.)
+	s.Require().NoError(err)
+
+	_, err = r.Value()
+	testutil.ContainsError(s.T(), err, "InformationRatio needs at least 2 values: got 1")
+}
+
+func (s *InformationRatioValueSuite) TestValueFailIfVarianceIsNegative() {
+	// simulate numerical instability beyond the tolerance
+	s.r.core.sums[2] = -1
+
+	_, err := s.r.Value()
+	s.Assert().ErrorIs(err, ErrorRetrievingVariance)
+	s.Assert().ErrorIs(err, ErrorNegativeVariance)
+}
+
+func (s *InformationRatioValueSuite) TestValueFailIfVarianceIsMarginallyNegative() {
+	// a marginally negative variance is clamped to zero
+	s.r.core.sums[2] = -NegativeVarianceTolerance / 10
+
+	_, err := s.r.Value()
+	testutil.ContainsError(s.T(), err, "tracking error is zero")
+}
+
+func (s *InformationRatioValueSuite) TestValueFailIfTrackingErrorIsZero() {
+	r := NewInformationRatio(3)
+	err := Init(r)
+	s.Require().NoError(err)
+
+	for i := 0.; i < 3; i++ {
+		err := r.Push(i+1, i)
+		s.Require().NoError(err)
+	}
+
+	_, err = r.Value()
+	testutil.ContainsError(s.T(), err, "tracking error is zero")
+}
+
+func TestInformationRatioClear(t *testing.T) {
+	r := NewInformationRatio(3)
+	err := Init(r)
+	require.NoError(t, err)
+
+	for i := 0.; i < 5; i++ {
+		err := r.Push(i*i, i)
+		require.NoError(t, err)
+	}
+
+	r.Clear()
+	expectedSums := []float64{0, 0, 0}
+	assert.Equal(t, expectedSums, r.core.sums)
+	assert.Equal(t, int(0), r.core.count)
+	assert.Equal(t, uint64(0), r.core.queue.Len())
+}
+
+func TestInformationRatioString(t *testing.T) {
+	r := NewInformationRatio(3)
+	expectedString := "moment.InformationRatio_{window:3}"
+	assert.Equal(t, expectedString, r.String())
+}
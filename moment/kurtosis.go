@@ -3,20 +3,24 @@ package moment
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/pkg/errors"
 )
 
-// Kurtosis is a metric that tracks the sample excess kurtosis.
+// Kurtosis is a metric that tracks the excess kurtosis, which is the
+// population excess kurtosis unless SampleOption is provided.
 type Kurtosis struct {
-	variance *Moment
-	moment4  *Moment
-	config   *CoreConfig
-	core     *Core
+	variance   *Moment
+	moment4    *Moment
+	population bool
+	tolerance  float64
+	config     *CoreConfig
+	core       *Core
 }
 
 // NewKurtosis instantiates a Kurtosis struct.
-func NewKurtosis(window int) *Kurtosis {
+func NewKurtosis(window int, options ...Option) *Kurtosis {
 	config := &CoreConfig{
 		Sums: SumsConfig{
 			2: true,
@@ -26,16 +30,18 @@ func NewKurtosis(window int) *Kurtosis {
 	}
 
 	return &Kurtosis{
-		variance: New(2, window),
-		moment4:  New(4, window),
-		config:   config,
+		variance:   New(2, window),
+		moment4:    New(4, window),
+		population: isPopulation(true, options),
+		tolerance:  varianceTolerance(options),
+		config:     config,
 	}
 }
 
 // NewGlobalKurtosis instantiates a global Kurtosis struct.
-// This is equivalent to calling NewKurtosis(0).
-func NewGlobalKurtosis() *Kurtosis {
-	return NewKurtosis(0)
+// This is equivalent to calling NewKurtosis(0, options...).
+func NewGlobalKurtosis(options ...Option) *Kurtosis {
+	return NewKurtosis(0, options...)
 }
 
 // SetCore sets the Core.
@@ -58,14 +64,20 @@ func (k *Kurtosis) Config() *CoreConfig {
 // String returns a string representation of the metric.
 func (k *Kurtosis) String() string {
 	name := "moment.Kurtosis"
-	window := fmt.Sprintf("window:%v", *k.config.Window)
-	return fmt.Sprintf("%s_{%s}", name, window)
+	params := []string{fmt.Sprintf("window:%v", *k.config.Window)}
+	if !k.population {
+		params = append(params, "population:false")
+	}
+	if k.tolerance != NegativeVarianceTolerance {
+		params = append(params, fmt.Sprintf("tolerance:%v", k.tolerance))
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
 }
 
 // Push adds a new value for Kurtosis to consume.
 func (k *Kurtosis) Push(x float64) error {
 	if !k.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
 	err := k.core.Push(x)
@@ -75,34 +87,53 @@ func (k *Kurtosis) Push(x float64) error {
 	return nil
 }
 
-// Value returns the value of the sample excess kurtosis.
+// Value returns the value of the population excess kurtosis g_2 = m_4/m_2^2 - 3,
+// where m_k = S_k/n is the kth population central moment, S_k is the sum of the
+// kth powers of the deviations from the mean and n is the number of values.
+// If SampleOption is provided, this returns the bias-corrected sample excess kurtosis
+// G_2 = ((n+1)g_2 + 6)(n-1)/((n-2)(n-3)) instead, which needs at least 4 values.
 func (k *Kurtosis) Value() (float64, error) {
 	if !k.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	}
 
 	k.core.RLock()
 	defer k.core.RUnlock()
 
-	count := float64(k.core.Count())
+	count := k.core.UnsafeWeightSum()
 	if count == 0 {
-		return 0, errors.New("no values seen yet")
+		return 0, ErrorNoValuesSeen
 	}
 
-	variance, err := k.variance.Value()
+	variance, err := k.variance.value()
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving 2nd moment")
+		return 0, fmt.Errorf("error retrieving 2nd moment: %w", err)
 	}
 
-	moment, err := k.moment4.Value()
+	moment, err := k.moment4.value()
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving 4th moment")
+		return 0, fmt.Errorf("error retrieving 4th moment: %w", err)
 	}
 
 	moment *= (count - 1) / count
 	variance *= (count - 1) / count
 
-	return moment/math.Pow(variance, 2) - 3, nil
+	variance, err = clampVariance(variance, k.tolerance)
+	if err != nil {
+		return 0, err
+	} else if variance == 0 {
+		return 0, errors.New("cannot compute kurtosis with zero variance")
+	}
+
+	kurtosis := moment/math.Pow(variance, 2) - 3
+	if k.population {
+		return kurtosis, nil
+	}
+
+	if count <= 3 {
+		return 0, errors.Errorf("sample excess kurtosis needs more than 3 values: %v <= 3", count)
+	}
+	return ((count+1)*kurtosis + 6) * (count - 1) / ((count - 2) * (count - 3)), nil
 }
 
 // Clear resets the metric.
@@ -15,8 +15,10 @@ func TestNewKurtosis(t *testing.T) {
 	window := 3
 	kurtosis := NewKurtosis(window)
 	assert.Equal(t, &Kurtosis{
-		variance: New(2, window),
-		moment4:  New(4, window),
+		variance:   New(2, window),
+		moment4:    New(4, window),
+		population: true,
+		tolerance:  NegativeVarianceTolerance,
 		config: &CoreConfig{
 			Sums: SumsConfig{
 				2: true,
@@ -57,6 +59,7 @@ func (s *KurtosisPushSuite) TestPushFailOnNullCore() {
 	kurtosis := NewKurtosis(3)
 	err := kurtosis.Push(0.)
 	testutil.ContainsError(s.T(), err, "Core is not set")
+	s.Assert().ErrorIs(err, ErrorCoreNotSet)
 }
 
 func (s *KurtosisPushSuite) TestPushFailOnQueueInsertionFailure() {
@@ -112,6 +115,20 @@ func (s *KurtosisValueSuite) TestValueSuccess() {
 	testutil.Approx(s.T(), moment/math.Pow(variance, 2.)-3., value)
 }
 
+func (s *KurtosisValueSuite) TestValueFailOnZeroVariance() {
+	kurtosis := NewGlobalKurtosis()
+	err := Init(kurtosis)
+	s.Require().NoError(err)
+
+	for i := 0; i < 4; i++ {
+		err := kurtosis.Push(3)
+		s.Require().NoError(err)
+	}
+
+	_, err = kurtosis.Value()
+	s.EqualError(err, "cannot compute kurtosis with zero variance")
+}
+
 func (s *KurtosisValueSuite) TestValueFailOnNullCore() {
 	kurtosis := NewKurtosis(3)
 	_, err := kurtosis.Value()
@@ -149,4 +166,8 @@ func TestKurtosisString(t *testing.T) {
 	kurtosis := NewKurtosis(3)
 	expectedString := "moment.Kurtosis_{window:3}"
 	assert.Equal(t, expectedString, kurtosis.String())
+
+	kurtosis = NewKurtosis(3, SampleOption())
+	expectedString = "moment.Kurtosis_{window:3,population:false}"
+	assert.Equal(t, expectedString, kurtosis.String())
 }
@@ -60,21 +60,32 @@ func (m *Mean) Push(x float64) error {
 	return nil
 }
 
-// Value returns the value of the mean.
+// PushBatch adds a batch of values for Mean to consume, in order.
+// This is equivalent to calling Push on each value, but more efficient.
+func (m *Mean) PushBatch(xs []float64) error {
+	if !m.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := m.core.PushBatch(xs)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the mean. Like Core.Mean, this does not lock.
 func (m *Mean) Value() (float64, error) {
 	if !m.IsSetCore() {
 		return 0, ErrorCoreNotSet
 	}
 
-	m.core.RLock()
-	defer m.core.RUnlock()
-
 	mean, err := m.core.Mean()
 	if err != nil {
 		if errors.Cause(err) == ErrorNoValuesSeen {
 			return 0, ErrorRetrievingSumDueToNoValuesSeen
 		}
-		return 0, errors.Wrap(err, "error retrieving sum")
+		return 0, fmt.Errorf("error retrieving sum: %w", err)
 	}
 	return mean, nil
 }
@@ -69,6 +69,26 @@ func (s *MeanPushSuite) TestPushFailOnQueueRetrievalFailure() {
 	testutil.ContainsError(s.T(), err, "error pushing to core")
 }
 
+func (s *MeanPushSuite) TestPushBatchSuccess() {
+	err := s.mean.PushBatch([]float64{1, 2, 3, 4})
+	s.Require().NoError(err)
+	s.Equal(3, s.mean.core.Count())
+}
+
+func (s *MeanPushSuite) TestPushBatchFailOnNullCore() {
+	mean := NewMean(3)
+	err := mean.PushBatch([]float64{0.})
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *MeanPushSuite) TestPushBatchFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.mean.core.queue.Dispose()
+
+	err := s.mean.PushBatch([]float64{3.})
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
 type MeanValueSuite struct {
 	suite.Suite
 	mean *Mean
@@ -3,29 +3,45 @@ package moment
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
-// Moment is a metric that tracks the kth sample central moment.
+// Moment is a metric that tracks the kth sample central moment,
+// or the kth population central moment if PopulationOption is provided.
 type Moment struct {
-	k      int
-	window int
-	core   *Core
+	k          int
+	window     int
+	duration   time.Duration
+	population bool
+	core       *Core
+	observers  observers
 }
 
 // New instantiates a Moment struct.
-func New(k int, window int) *Moment {
+func New(k int, window int, options ...Option) *Moment {
 	return &Moment{
-		k:      k,
-		window: window,
+		k:          k,
+		window:     window,
+		population: isPopulation(false, options),
 	}
 }
 
 // NewGlobal instantiates a global Moment struct.
-// This is equivalent to calling New(k, 0).
-func NewGlobal(k int) *Moment {
-	return New(k, 0)
+// This is equivalent to calling New(k, 0, options...).
+func NewGlobal(k int, options ...Option) *Moment {
+	return New(k, 0, options...)
+}
+
+// NewTimed instantiates a Moment struct that tracks values over a time window
+// of the given duration; values must be pushed with PushAt rather than Push.
+func NewTimed(k int, duration time.Duration, options ...Option) *Moment {
+	return &Moment{
+		k:          k,
+		duration:   duration,
+		population: isPopulation(false, options),
+	}
 }
 
 // SetCore sets the Core.
@@ -40,10 +56,14 @@ func (m *Moment) IsSetCore() bool {
 
 // Config returns the CoreConfig needed.
 func (m *Moment) Config() *CoreConfig {
-	return &CoreConfig{
+	config := &CoreConfig{
 		Sums:   SumsConfig{m.k: true},
 		Window: &m.window,
 	}
+	if m.duration != 0 {
+		config.Duration = &m.duration
+	}
+	return config
 }
 
 // String returns a string representation of the metric.
@@ -53,23 +73,86 @@ func (m *Moment) String() string {
 		fmt.Sprintf("k:%v", m.k),
 		fmt.Sprintf("window:%v", m.window),
 	}
+	if m.duration != 0 {
+		params = append(params, fmt.Sprintf("duration:%v", m.duration))
+	}
+	if m.population {
+		params = append(params, "population:true")
+	}
 	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
 }
 
 // Push adds a new value for Moment to consume.
 func (m *Moment) Push(x float64) error {
 	if !m.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
 	err := m.core.Push(x)
 	if err != nil {
 		return errors.Wrap(err, "error pushing to core")
 	}
+	m.observers.notify(m.Value)
+	return nil
+}
+
+// PushAt adds a new value with a timestamp for a Moment with a time window to consume.
+func (m *Moment) PushAt(x float64, t time.Time) error {
+	if !m.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := m.core.PushAt(x, t)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	m.observers.notify(m.Value)
+	return nil
+}
+
+// Advance moves the time window of a Moment with a time window forward to end at t,
+// evicting the values that have fallen out of it; see Core.Advance.
+func (m *Moment) Advance(t time.Time) error {
+	if !m.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := m.core.Advance(t)
+	if err != nil {
+		return errors.Wrap(err, "error advancing core")
+	}
+	return nil
+}
+
+// PushBatch adds a batch of values for Moment to consume, in order.
+// This is equivalent to calling Push on each value, but more efficient.
+func (m *Moment) PushBatch(xs []float64) error {
+	if !m.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := m.core.PushBatch(xs)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	m.observers.notify(m.Value)
 	return nil
 }
 
-// Value returns the value of the kth sample central moment.
+// OnUpdate registers a callback that is called with the value of the moment after
+// each successful push (once per call to PushBatch); multiple callbacks are called in
+// the order they were registered, and nil callbacks are ignored. The callbacks are
+// called after the Core is unlocked, so if other goroutines push concurrently, the
+// value may already reflect their pushes; if the value cannot be computed, the
+// callbacks are skipped.
+func (m *Moment) OnUpdate(fn func(value float64)) {
+	m.observers.add(fn)
+}
+
+// Value returns the value of the kth sample central moment, i.e. the sum of
+// the kth powers of the deviations from the mean divided by n-1 (Bessel's correction),
+// where n is the number of values. If PopulationOption is provided, this returns the
+// kth population central moment instead, i.e. the sum divided by n.
 func (m *Moment) Value() (float64, error) {
 	if !m.IsSetCore() {
 		return 0, ErrorCoreNotSet
@@ -78,13 +161,23 @@ func (m *Moment) Value() (float64, error) {
 	m.core.RLock()
 	defer m.core.RUnlock()
 
-	moment, err := m.core.Sum(m.k)
+	return m.value()
+}
+
+// value returns the value of the kth central moment, but does not lock the Core;
+// metrics sharing the Core use this to avoid acquiring the read lock recursively.
+func (m *Moment) value() (float64, error) {
+	moment, err := m.core.UnsafeSum(m.k)
 	if err != nil {
-		return 0, ErrorRetrievingSum
+		return 0, wrapSentinel(ErrorRetrievingSum, err)
 	}
 
-	count := m.core.Count()
-	moment /= (float64(count) - 1.)
+	count := m.core.UnsafeWeightSum()
+	if m.population {
+		moment /= count
+	} else {
+		moment /= (count - 1.)
+	}
 
 	return moment, nil
 }
@@ -102,6 +195,33 @@ var (
 	ErrorPoppingQueue                   = errors.New("error popping item from queue")
 	ErrorCoreNotSet                     = errors.New("Core is not set")
 	ErrorRetrievingSum                  = errors.New("error retrieving sum")
-	ErrorRetrievingSumDueToNoValuesSeen = errors.Wrap(ErrorNoValuesSeen, "error retrieving sum")
+	ErrorRetrievingSumDueToNoValuesSeen = fmt.Errorf("error retrieving sum: %w", ErrorNoValuesSeen)
 	ErrorRetrievingVariance             = errors.New("error retrieving variance")
+	ErrorNegativeVariance               = errors.New("variance is negative beyond tolerance due to numerical instability")
 )
+
+// sentinelError is an error annotated with a sentinel error, so that
+// errors.Is matches both the sentinel and the errors wrapped by the original.
+type sentinelError struct {
+	sentinel error
+	err      error
+}
+
+// wrapSentinel annotates the error with the sentinel error.
+func wrapSentinel(sentinel error, err error) error {
+	return &sentinelError{sentinel: sentinel, err: err}
+}
+
+func (e *sentinelError) Error() string {
+	return fmt.Sprintf("%v: %v", e.sentinel, e.err)
+}
+
+// Is reports whether the target is the sentinel error.
+func (e *sentinelError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+// Unwrap returns the original error.
+func (e *sentinelError) Unwrap() error {
+	return e.err
+}
@@ -1,12 +1,17 @@
 package moment
 
 import (
+	"errors"
+	"math"
+	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/K4Mobility/stream"
 	testutil "github.com/K4Mobility/stream/util/test"
 )
 
@@ -46,6 +51,7 @@ func (s *MomentPushSuite) TestPushFailOnNullCore() {
 	moment := New(2, 3)
 	err := moment.Push(0.)
 	testutil.ContainsError(s.T(), err, "Core is not set")
+	s.Assert().ErrorIs(err, ErrorCoreNotSet)
 }
 
 func (s *MomentPushSuite) TestPushFailOnQueueInsertionFailure() {
@@ -70,6 +76,68 @@ func (s *MomentPushSuite) TestPushFailOnQueueRetrievalFailure() {
 	testutil.ContainsError(s.T(), err, "error pushing to core")
 }
 
+func (s *MomentPushSuite) TestPushBatchSuccess() {
+	err := s.moment.PushBatch([]float64{1, 2, 3, 4})
+	s.Require().NoError(err)
+	s.Equal(3, s.moment.core.Count())
+}
+
+func (s *MomentPushSuite) TestPushBatchFailOnNullCore() {
+	moment := New(2, 3)
+	err := moment.PushBatch([]float64{0.})
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *MomentPushSuite) TestPushBatchFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.moment.core.queue.Dispose()
+
+	err := s.moment.PushBatch([]float64{3.})
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+func TestTimedMoment(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("pass: tracks moment over a time window", func(t *testing.T) {
+		moment := NewTimed(2, 3*time.Second)
+		err := Init(moment)
+		require.NoError(t, err)
+
+		for i, x := range []float64{1, 2, 3, 4, 8} {
+			err := moment.PushAt(x, start.Add(time.Duration(i)*time.Second))
+			require.NoError(t, err)
+		}
+
+		// window is {3, 4, 8}
+		value, err := moment.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 7., value)
+	})
+
+	t.Run("pass: String includes duration", func(t *testing.T) {
+		moment := NewTimed(2, 3*time.Second)
+		assert.Equal(t, "moment.Moment_{k:2,window:0,duration:3s}", moment.String())
+	})
+
+	t.Run("fail: PushAt fails on null core", func(t *testing.T) {
+		moment := NewTimed(2, 3*time.Second)
+		err := moment.PushAt(1, start)
+		testutil.ContainsError(t, err, "Core is not set")
+	})
+
+	t.Run("fail: PushAt fails on out of order timestamps", func(t *testing.T) {
+		moment := NewTimed(2, 3*time.Second)
+		err := Init(moment)
+		require.NoError(t, err)
+
+		err = moment.PushAt(1, start)
+		require.NoError(t, err)
+		err = moment.PushAt(1, start.Add(-time.Second))
+		testutil.ContainsError(t, err, "error pushing to core")
+	})
+}
+
 type MomentValueSuite struct {
 	suite.Suite
 	moment *Moment
@@ -134,4 +202,117 @@ func TestMomentString(t *testing.T) {
 	moment := New(2, 3)
 	expectedString := "moment.Moment_{k:2,window:3}"
 	assert.Equal(t, expectedString, moment.String())
+
+	moment = New(2, 3, PopulationOption())
+	expectedString = "moment.Moment_{k:2,window:3,population:true}"
+	assert.Equal(t, expectedString, moment.String())
+}
+
+func TestSentinelErrors(t *testing.T) {
+	t.Run("pass: untracked power sums match ErrorNotTracked", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{
+			Sums:   SumsConfig{1: true},
+			Window: stream.IntPtr(0),
+		})
+		require.NoError(t, err)
+		err = core.Push(1)
+		require.NoError(t, err)
+
+		_, err = core.Sum(3)
+		assert.True(t, errors.Is(err, ErrorNotTracked))
+		assert.EqualError(t, err, "3 is not a tracked power sum")
+
+		_, err = core.SumOfSquares()
+		assert.True(t, errors.Is(err, ErrorNotTracked))
+	})
+
+	t.Run("pass: metrics without values match ErrorNoValuesSeen", func(t *testing.T) {
+		moment := New(2, 3)
+		std := NewStd(3)
+		mean := NewMean(3)
+		skewness := NewSkewness(3)
+		for _, metric := range []Metric{moment, std, mean, skewness} {
+			err := Init(metric)
+			require.NoError(t, err)
+
+			_, err = metric.Value()
+			assert.True(t, errors.Is(err, ErrorNoValuesSeen), metric.String())
+		}
+
+		// the errors also match the sentinel describing what failed
+		_, err := moment.Value()
+		assert.True(t, errors.Is(err, ErrorRetrievingSum))
+		assert.EqualError(t, err, "error retrieving sum: no values seen yet")
+
+		_, err = std.Value()
+		assert.True(t, errors.Is(err, ErrorRetrievingVariance))
+		assert.EqualError(t, err, "error retrieving variance: error retrieving sum: no values seen yet")
+	})
+}
+
+// sampleStats returns the sample variance, skewness (G1) and excess kurtosis (G2) of xs.
+func sampleStats(xs []float64) (variance float64, skewness float64, kurtosis float64) {
+	n := float64(len(xs))
+	mean := 0.
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= n
+
+	var m2, m3, m4 float64
+	for _, x := range xs {
+		d := x - mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	variance = m2 / (n - 1)
+	m2, m3, m4 = m2/n, m3/n, m4/n
+
+	g1 := m3 / math.Pow(m2, 1.5)
+	skewness = g1 * math.Sqrt(n*(n-1)) / (n - 2)
+	g2 := m4/(m2*m2) - 3
+	kurtosis = ((n+1)*g2 + 6) * (n - 1) / ((n - 2) * (n - 3))
+	return variance, skewness, kurtosis
+}
+
+func TestWindowedSampleStatistics(t *testing.T) {
+	// the sample corrections must use the number of values in the window,
+	// not the number of values pushed, once the stream outgrows the window
+	window := 7
+	variance, std := NewVariance(window), NewStd(window)
+	skewness, kurtosis := NewSkewness(window), NewKurtosis(window, SampleOption())
+	for _, wrapper := range []CoreWrapper{variance, std, skewness, kurtosis} {
+		require.NoError(t, Init(wrapper))
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	xs := make([]float64, 100)
+	for i := range xs {
+		xs[i] = rng.Float64() * 10
+		for _, metric := range []stream.SimpleMetric{variance, std, skewness, kurtosis} {
+			require.NoError(t, metric.Push(xs[i]))
+		}
+		if i+1 < window {
+			continue
+		}
+
+		expectedVariance, expectedSkewness, expectedKurtosis := sampleStats(xs[i+1-window : i+1])
+
+		val, err := variance.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, expectedVariance, val, "variance after %d values", i+1)
+
+		val, err = std.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, math.Sqrt(expectedVariance), val, "std after %d values", i+1)
+
+		val, err = skewness.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, expectedSkewness, val, "skewness after %d values", i+1)
+
+		val, err = kurtosis.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, expectedKurtosis, val, "kurtosis after %d values", i+1)
+	}
 }
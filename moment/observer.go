@@ -0,0 +1,42 @@
+package moment
+
+import "sync"
+
+// observers holds the callbacks registered with the OnUpdate method of a metric.
+type observers struct {
+	mux sync.RWMutex
+	fns []func(float64)
+}
+
+// add registers a callback; nil callbacks are ignored.
+func (o *observers) add(fn func(float64)) {
+	if fn == nil {
+		return
+	}
+
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	o.fns = append(o.fns, fn)
+}
+
+// notify calls each registered callback, in the order they were registered, with
+// the result of value; value is only called if there are callbacks to notify, and
+// if it returns an error, the callbacks are not called. The callbacks are called
+// outside of any lock, so they may push to or read from the metric.
+func (o *observers) notify(value func() (float64, error)) {
+	o.mux.RLock()
+	fns := o.fns
+	o.mux.RUnlock()
+
+	if len(fns) == 0 {
+		return
+	}
+
+	v, err := value()
+	if err != nil {
+		return
+	}
+	for _, fn := range fns {
+		fn(v)
+	}
+}
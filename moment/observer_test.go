@@ -0,0 +1,110 @@
+package moment
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestOnUpdate(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 8}
+
+	t.Run("pass: callbacks observe each push's updated value", func(t *testing.T) {
+		moment := New(2, 3)
+		err := Init(moment)
+		require.NoError(t, err)
+
+		var observed []float64
+		moment.OnUpdate(func(value float64) {
+			observed = append(observed, value)
+		})
+
+		var expected []float64
+		for _, x := range xs {
+			err := moment.Push(x)
+			require.NoError(t, err)
+
+			value, err := moment.Value()
+			require.NoError(t, err)
+			expected = append(expected, value)
+		}
+
+		require.Len(t, observed, len(xs))
+		// the sample variance of a single value is NaN
+		assert.True(t, math.IsNaN(observed[0]))
+		for i := 1; i < len(xs); i++ {
+			testutil.Approx(t, expected[i], observed[i])
+		}
+	})
+
+	t.Run("pass: multiple callbacks are called in order", func(t *testing.T) {
+		moment := New(1, 0)
+		err := Init(moment)
+		require.NoError(t, err)
+
+		var calls []string
+		moment.OnUpdate(func(float64) { calls = append(calls, "first") })
+		moment.OnUpdate(nil)
+		moment.OnUpdate(func(float64) { calls = append(calls, "second") })
+
+		err = moment.Push(1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, calls)
+	})
+
+	t.Run("pass: batches notify once", func(t *testing.T) {
+		moment := New(2, 0)
+		err := Init(moment)
+		require.NoError(t, err)
+
+		var observed []float64
+		moment.OnUpdate(func(value float64) {
+			observed = append(observed, value)
+		})
+
+		err = moment.PushBatch(xs)
+		require.NoError(t, err)
+		require.Len(t, observed, 1)
+		testutil.Approx(t, 7.3, observed[0])
+	})
+
+	t.Run("pass: wrapping metrics observe their own values", func(t *testing.T) {
+		variance := NewVariance(0)
+		err := Init(variance)
+		require.NoError(t, err)
+		std := NewStd(0)
+		err = Init(std)
+		require.NoError(t, err)
+
+		var varianceObserved, stdObserved float64
+		variance.OnUpdate(func(value float64) { varianceObserved = value })
+		std.OnUpdate(func(value float64) { stdObserved = value })
+
+		for _, x := range xs {
+			err := variance.Push(x)
+			require.NoError(t, err)
+			err = std.Push(x)
+			require.NoError(t, err)
+		}
+
+		testutil.Approx(t, 7.3, varianceObserved)
+		testutil.Approx(t, math.Sqrt(7.3), stdObserved)
+	})
+
+	t.Run("fail: failed pushes do not notify", func(t *testing.T) {
+		moment := New(2, 3)
+		err := Init(moment)
+		require.NoError(t, err)
+
+		calls := 0
+		moment.OnUpdate(func(float64) { calls++ })
+
+		err = moment.Push(math.NaN())
+		assert.Error(t, err)
+		assert.Equal(t, 0, calls)
+	})
+}
@@ -0,0 +1,69 @@
+package moment
+
+// Option is an optional argument for creating moment-based metrics,
+// which selects whether a sample or a population statistic is computed.
+type Option func(*options)
+
+type options struct {
+	// nil if the default of the metric is used
+	population *bool
+	// nil if NegativeVarianceTolerance is used
+	tolerance *float64
+}
+
+// SampleOption creates an option that makes a metric compute the sample
+// statistic, i.e. apply Bessel's correction and the bias corrections for
+// skewness and kurtosis. This is the default for Moment, Std and Skewness.
+func SampleOption() Option {
+	return func(o *options) {
+		population := false
+		o.population = &population
+	}
+}
+
+// PopulationOption creates an option that makes a metric compute the
+// population statistic, i.e. normalize by the number of values without
+// any bias correction. This is the default for Kurtosis.
+func PopulationOption() Option {
+	return func(o *options) {
+		population := true
+		o.population = &population
+	}
+}
+
+// ToleranceOption creates an option that sets how negative a variance may be
+// before it is reported as ErrorNegativeVariance rather than clamped to zero,
+// overriding NegativeVarianceTolerance. It is accepted by Variance, Std, Skewness
+// and Kurtosis; since cancellation errors grow with the square of the magnitude
+// of the values, streams of large values may need a larger tolerance.
+func ToleranceOption(tolerance float64) Option {
+	return func(o *options) {
+		o.tolerance = &tolerance
+	}
+}
+
+// isPopulation returns whether the options select the population statistic,
+// falling back to the default of the metric if neither option is provided.
+func isPopulation(defaultPopulation bool, opts []Option) bool {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.population == nil {
+		return defaultPopulation
+	}
+	return *o.population
+}
+
+// varianceTolerance returns the tolerance for negative variances selected
+// by the options, falling back to NegativeVarianceTolerance.
+func varianceTolerance(opts []Option) float64 {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.tolerance == nil {
+		return NegativeVarianceTolerance
+	}
+	return *o.tolerance
+}
@@ -0,0 +1,86 @@
+package moment
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestIsPopulation(t *testing.T) {
+	assert.False(t, isPopulation(false, nil))
+	assert.True(t, isPopulation(true, nil))
+	assert.True(t, isPopulation(false, []Option{PopulationOption()}))
+	assert.False(t, isPopulation(true, []Option{SampleOption()}))
+	// the last option takes precedence
+	assert.False(t, isPopulation(false, []Option{PopulationOption(), SampleOption()}))
+}
+
+func TestSampleAndPopulation(t *testing.T) {
+	// for xs, the mean is 3.6 and the sums of the powers of the deviations
+	// from the mean are S_2 = 29.2, S_3 = 63.36 and S_4 = 427.216, with n = 5
+	xs := []float64{1, 2, 3, 4, 8}
+	m2 := 29.2 / 5
+	m3 := 63.36 / 5
+	m4 := 427.216 / 5
+	g1 := m3 / math.Pow(m2, 1.5)
+	g2 := m4/(m2*m2) - 3
+
+	value := func(t *testing.T, metric interface {
+		CoreWrapper
+		Push(float64) error
+		Value() (float64, error)
+	}) float64 {
+		err := Init(metric)
+		require.NoError(t, err)
+		for _, x := range xs {
+			err := metric.Push(x)
+			require.NoError(t, err)
+		}
+		value, err := metric.Value()
+		require.NoError(t, err)
+		return value
+	}
+
+	t.Run("pass: Moment uses n-1 for samples and n for populations", func(t *testing.T) {
+		testutil.Approx(t, 7.3, value(t, NewGlobal(2)))
+		testutil.Approx(t, 7.3, value(t, NewGlobal(2, SampleOption())))
+		testutil.Approx(t, 5.84, value(t, NewGlobal(2, PopulationOption())))
+		testutil.Approx(t, 15.84, value(t, NewGlobal(3)))
+		testutil.Approx(t, 12.672, value(t, NewGlobal(3, PopulationOption())))
+	})
+
+	t.Run("pass: Std uses n-1 for samples and n for populations", func(t *testing.T) {
+		testutil.Approx(t, math.Sqrt(7.3), value(t, NewGlobalStd()))
+		testutil.Approx(t, math.Sqrt(5.84), value(t, NewGlobalStd(PopulationOption())))
+	})
+
+	t.Run("pass: Skewness is adjusted for samples only", func(t *testing.T) {
+		testutil.Approx(t, 1.3385038869326567, value(t, NewGlobalSkewness()))
+		testutil.Approx(t, g1*math.Sqrt(20)/3, value(t, NewGlobalSkewness(SampleOption())))
+		testutil.Approx(t, 0.8978957037987337, value(t, NewGlobalSkewness(PopulationOption())))
+		testutil.Approx(t, g1, value(t, NewGlobalSkewness(PopulationOption())))
+	})
+
+	t.Run("pass: Kurtosis is bias-corrected for samples only", func(t *testing.T) {
+		testutil.Approx(t, -0.49474573090636165, value(t, NewGlobalKurtosis()))
+		testutil.Approx(t, g2, value(t, NewGlobalKurtosis(PopulationOption())))
+		testutil.Approx(t, 2.0210170763745534, value(t, NewGlobalKurtosis(SampleOption())))
+		testutil.Approx(t, ((6*g2+6)*4)/(3*2), value(t, NewGlobalKurtosis(SampleOption())))
+	})
+
+	t.Run("fail: sample Kurtosis needs more than 3 values", func(t *testing.T) {
+		kurtosis := NewGlobalKurtosis(SampleOption())
+		err := Init(kurtosis)
+		require.NoError(t, err)
+		for _, x := range xs[:3] {
+			err := kurtosis.Push(x)
+			require.NoError(t, err)
+		}
+		_, err = kurtosis.Value()
+		testutil.ContainsError(t, err, "sample excess kurtosis needs more than 3 values")
+	})
+}
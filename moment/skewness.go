@@ -3,20 +3,24 @@ package moment
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/pkg/errors"
 )
 
-// Skewness is a metric that tracks the adjusted Fisher-Pearson sample skewness.
+// Skewness is a metric that tracks the adjusted Fisher-Pearson sample skewness,
+// or the population skewness if PopulationOption is provided.
 type Skewness struct {
-	variance *Moment
-	moment3  *Moment
-	config   *CoreConfig
-	core     *Core
+	variance   *Moment
+	moment3    *Moment
+	population bool
+	tolerance  float64
+	config     *CoreConfig
+	core       *Core
 }
 
 // NewSkewness instantiates a Skewness struct.
-func NewSkewness(window int) *Skewness {
+func NewSkewness(window int, options ...Option) *Skewness {
 	config := &CoreConfig{
 		Sums: SumsConfig{
 			2: true,
@@ -26,16 +30,18 @@ func NewSkewness(window int) *Skewness {
 	}
 
 	return &Skewness{
-		variance: New(2, window),
-		moment3:  New(3, window),
-		config:   config,
+		variance:   New(2, window),
+		moment3:    New(3, window),
+		population: isPopulation(false, options),
+		tolerance:  varianceTolerance(options),
+		config:     config,
 	}
 }
 
 // NewGlobalSkewness instantiates a global Skewness struct.
-// This is equivalent to calling NewSkewness(0).
-func NewGlobalSkewness() *Skewness {
-	return NewSkewness(0)
+// This is equivalent to calling NewSkewness(0, options...).
+func NewGlobalSkewness(options ...Option) *Skewness {
+	return NewSkewness(0, options...)
 }
 
 // SetCore sets the Core.
@@ -58,14 +64,20 @@ func (s *Skewness) Config() *CoreConfig {
 // String returns a string representation of the metric.
 func (s *Skewness) String() string {
 	name := "moment.Skewness"
-	window := fmt.Sprintf("window:%v", *s.config.Window)
-	return fmt.Sprintf("%s_{%s}", name, window)
+	params := []string{fmt.Sprintf("window:%v", *s.config.Window)}
+	if s.population {
+		params = append(params, "population:true")
+	}
+	if s.tolerance != NegativeVarianceTolerance {
+		params = append(params, fmt.Sprintf("tolerance:%v", s.tolerance))
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
 }
 
 // Push adds a new value for Skewness to consume.
 func (s *Skewness) Push(x float64) error {
 	if !s.IsSetCore() {
-		return errors.New("Core is not set")
+		return ErrorCoreNotSet
 	}
 
 	err := s.core.Push(x)
@@ -75,34 +87,54 @@ func (s *Skewness) Push(x float64) error {
 	return nil
 }
 
-// Value returns the value of the adjusted Fisher-Pearson sample skewness.
+// Value returns the value of the adjusted Fisher-Pearson sample skewness
+// G_1 = g_1 sqrt(n(n-1))/(n-2), where g_1 = m_3/m_2^(3/2), m_k = S_k/n is the kth
+// population central moment, S_k is the sum of the kth powers of the deviations
+// from the mean and n is the number of values. If PopulationOption is provided,
+// this returns the population skewness g_1 instead. The sample skewness needs at
+// least 3 values, and neither is defined if all of the values are equal.
 func (s *Skewness) Value() (float64, error) {
 	if !s.IsSetCore() {
-		return 0, errors.New("Core is not set")
+		return 0, ErrorCoreNotSet
 	}
 
 	s.core.RLock()
 	defer s.core.RUnlock()
 
-	count := float64(s.core.Count())
+	count := s.core.UnsafeWeightSum()
 	if count == 0 {
-		return 0, errors.New("no values seen yet")
+		return 0, ErrorNoValuesSeen
 	}
 
-	variance, err := s.variance.Value()
+	variance, err := s.variance.value()
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving 2nd moment")
+		return 0, fmt.Errorf("error retrieving 2nd moment: %w", err)
 	}
 	variance *= (count - 1) / count
 
-	moment, err := s.moment3.Value()
+	moment, err := s.moment3.value()
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving 3rd moment")
+		return 0, fmt.Errorf("error retrieving 3rd moment: %w", err)
 	}
 	moment *= (count - 1) / count
 
+	variance, err = clampVariance(variance, s.tolerance)
+	if err != nil {
+		return 0, err
+	} else if variance == 0 {
+		return 0, errors.New("cannot compute skewness with zero variance")
+	}
+
+	skewness := moment / math.Pow(variance, 1.5)
+	if s.population {
+		return skewness, nil
+	}
+
+	if count <= 2 {
+		return 0, errors.Errorf("sample skewness needs more than 2 values: %v <= 2", count)
+	}
 	adjust := math.Sqrt(count*(count-1)) / (count - 2)
-	return adjust * moment / math.Pow(variance, 1.5), nil
+	return adjust * skewness, nil
 }
 
 // Clear resets the metric.
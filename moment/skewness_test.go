@@ -2,7 +2,9 @@ package moment
 
 import (
 	"math"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,8 +17,9 @@ func TestNewSkewness(t *testing.T) {
 	window := 3
 	skewness := NewSkewness(window)
 	assert.Equal(t, &Skewness{
-		variance: New(2, window),
-		moment3:  New(3, window),
+		variance:  New(2, window),
+		moment3:   New(3, window),
+		tolerance: NegativeVarianceTolerance,
 		config: &CoreConfig{
 			Sums: SumsConfig{
 				2: true,
@@ -57,6 +60,7 @@ func (s *SkewnessPushSuite) TestPushFailOnNullCore() {
 	skewness := NewSkewness(3)
 	err := skewness.Push(0.)
 	testutil.ContainsError(s.T(), err, "Core is not set")
+	s.Assert().ErrorIs(err, ErrorCoreNotSet)
 }
 
 func (s *SkewnessPushSuite) TestPushFailOnQueueInsertionFailure() {
@@ -113,6 +117,64 @@ func (s *SkewnessValueSuite) TestValueSuccess() {
 	testutil.Approx(s.T(), adjust*moment/math.Pow(variance, 1.5), value)
 }
 
+func (s *SkewnessValueSuite) TestValueRightSkewed() {
+	skewness := NewGlobalSkewness()
+	err := Init(skewness)
+	s.Require().NoError(err)
+
+	// mean 2, so S_2 = 4 * 2^2 + 8^2 = 80 and S_3 = 4 * (-2)^3 + 8^3 = 480;
+	// g_1 = (480/5) / (80/5)^(3/2) = 96 / 64 = 1.5, and the adjustment
+	// sqrt(5 * 4) / 3 gives G_1 = sqrt(5)
+	for _, x := range []float64{0, 0, 10, 0, 0} {
+		err := skewness.Push(x)
+		s.Require().NoError(err)
+	}
+
+	value, err := skewness.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), math.Sqrt(5), value)
+}
+
+func (s *SkewnessValueSuite) TestValueFailOnZeroVariance() {
+	skewness := NewGlobalSkewness()
+	err := Init(skewness)
+	s.Require().NoError(err)
+
+	for i := 0; i < 4; i++ {
+		err := skewness.Push(3)
+		s.Require().NoError(err)
+	}
+
+	_, err = skewness.Value()
+	s.EqualError(err, "cannot compute skewness with zero variance")
+}
+
+func (s *SkewnessValueSuite) TestValueFailOnTooFewValues() {
+	skewness := NewGlobalSkewness()
+	err := Init(skewness)
+	s.Require().NoError(err)
+
+	for _, x := range []float64{1, 2} {
+		err := skewness.Push(x)
+		s.Require().NoError(err)
+	}
+
+	_, err = skewness.Value()
+	s.EqualError(err, "sample skewness needs more than 2 values: 2 <= 2")
+
+	population := NewGlobalSkewness(PopulationOption())
+	err = Init(population)
+	s.Require().NoError(err)
+	for _, x := range []float64{1, 2} {
+		err := population.Push(x)
+		s.Require().NoError(err)
+	}
+
+	value, err := population.Value()
+	s.Require().NoError(err)
+	s.Equal(0., value)
+}
+
 func (s *SkewnessValueSuite) TestValueFailOnNullCore() {
 	skewness := NewSkewness(3)
 	_, err := skewness.Value()
@@ -150,4 +212,52 @@ func TestSkewnessString(t *testing.T) {
 	skewness := NewSkewness(3)
 	expectedString := "moment.Skewness_{window:3}"
 	assert.Equal(t, expectedString, skewness.String())
+
+	skewness = NewSkewness(3, PopulationOption())
+	expectedString = "moment.Skewness_{window:3,population:true}"
+	assert.Equal(t, expectedString, skewness.String())
+}
+
+func TestSkewnessConcurrentPushAndValue(t *testing.T) {
+	skewness := NewSkewness(10)
+	err := Init(skewness)
+	require.NoError(t, err)
+
+	for i := 0.; i < 10; i++ {
+		err := skewness.Push(i * i)
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0.; i < 1000; i++ {
+				assert.NoError(t, skewness.Push(i*i))
+			}
+		}()
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				_, err := skewness.Value()
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	done := make(chan bool)
+	go func() {
+		wg.Wait()
+		done <- true
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("concurrent pushes and reads deadlocked")
+	}
 }
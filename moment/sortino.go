@@ -0,0 +1,130 @@
+package moment
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Sortino is a metric that tracks the Sortino ratio of a stream of returns, i.e.
+// the mean excess return over a target divided by the downside deviation, which
+// is the root mean square of the shortfalls of the returns below the target.
+//
+// Since the shortfalls cannot be recovered from the centralized sums, Sortino keeps
+// its own Core that consumes the squared shortfalls, alongside the Core set by Init().
+type Sortino struct {
+	target   float64
+	window   int
+	core     *Core
+	downside *Core
+}
+
+// NewSortino instantiates a Sortino struct.
+func NewSortino(target float64, window int) (*Sortino, error) {
+	downside, err := NewCore(&CoreConfig{Window: &window})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Core")
+	}
+
+	return &Sortino{
+		target:   target,
+		window:   window,
+		downside: downside,
+	}, nil
+}
+
+// NewGlobalSortino instantiates a global Sortino struct.
+// This is equivalent to calling NewSortino(target, 0).
+func NewGlobalSortino(target float64) (*Sortino, error) {
+	return NewSortino(target, 0)
+}
+
+// SetCore sets the Core.
+func (s *Sortino) SetCore(c *Core) {
+	s.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (s *Sortino) IsSetCore() bool {
+	return s.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (s *Sortino) Config() *CoreConfig {
+	return &CoreConfig{
+		Window: &s.window,
+	}
+}
+
+// String returns a string representation of the metric.
+func (s *Sortino) String() string {
+	name := "moment.Sortino"
+	params := []string{
+		fmt.Sprintf("target:%v", s.target),
+		fmt.Sprintf("window:%v", s.window),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a new return for Sortino to consume.
+func (s *Sortino) Push(x float64) error {
+	if !s.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	// hold the lock on the shared core across both pushes,
+	// so that Value never sees one core updated without the other
+	s.core.Lock()
+	defer s.core.Unlock()
+
+	err := s.core.UnsafePush(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+
+	shortfall := math.Min(x-s.target, 0)
+	err = s.downside.Push(shortfall * shortfall)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to downside core")
+	}
+	return nil
+}
+
+// Value returns the value of the Sortino ratio.
+func (s *Sortino) Value() (float64, error) {
+	if !s.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	s.core.RLock()
+	defer s.core.RUnlock()
+
+	mean, err := s.core.UnsafeMean()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving mean: %w", err)
+	}
+
+	meanSquaredShortfall, err := s.downside.Mean()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving mean squared shortfall: %w", err)
+	}
+
+	downsideDeviation := math.Sqrt(meanSquaredShortfall)
+	if downsideDeviation == 0 {
+		return 0, errors.New("downside deviation is zero")
+	}
+
+	return (mean - s.target) / downsideDeviation, nil
+}
+
+// Clear resets the metric.
+func (s *Sortino) Clear() {
+	if s.IsSetCore() {
+		s.core.Lock()
+		defer s.core.Unlock()
+		s.core.UnsafeClear()
+	}
+	s.downside.Clear()
+}
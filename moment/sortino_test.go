@@ -0,0 +1,203 @@
+package moment
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewSortino(t *testing.T) {
+	t.Run("pass: valid Sortino is valid", func(t *testing.T) {
+		s, err := NewSortino(0.5, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, s.target)
+		assert.Equal(t, 3, s.window)
+		assert.Equal(t, 3, s.downside.window)
+		assert.Nil(t, s.core)
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewSortino(0.5, -1)
+		testutil.ContainsError(t, err, "error creating Core")
+	})
+}
+
+func TestNewGlobalSortino(t *testing.T) {
+	s, err := NewSortino(0.5, 0)
+	require.NoError(t, err)
+
+	globalS, err := NewGlobalSortino(0.5)
+	require.NoError(t, err)
+
+	assert.Equal(t, s, globalS)
+}
+
+type SortinoPushSuite struct {
+	suite.Suite
+	s *Sortino
+}
+
+func TestSortinoPushSuite(t *testing.T) {
+	suite.Run(t, &SortinoPushSuite{})
+}
+
+func (s *SortinoPushSuite) SetupTest() {
+	var err error
+	s.s, err = NewSortino(1, 3)
+	s.Require().NoError(err)
+	err = Init(s.s)
+	s.Require().NoError(err)
+}
+
+func (s *SortinoPushSuite) TestPushSuccess() {
+	xs := []float64{-1, 3}
+	for _, x := range xs {
+		err := s.s.Push(x)
+		s.Require().NoError(err)
+	}
+
+	mean, err := s.s.core.Mean()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 1., mean)
+
+	// only the first return falls short of the target
+	meanSquaredShortfall, err := s.s.downside.Mean()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 2., meanSquaredShortfall)
+}
+
+func (s *SortinoPushSuite) TestPushFailOnNullCore() {
+	sortino, err := NewSortino(1, 3)
+	s.Require().NoError(err)
+
+	err = sortino.Push(0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *SortinoPushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.s.core.queue.Dispose()
+
+	err := s.s.Push(3.)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+func (s *SortinoPushSuite) TestPushFailOnDownsideQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.s.downside.queue.Dispose()
+
+	err := s.s.Push(3.)
+	testutil.ContainsError(s.T(), err, "error pushing to downside core")
+}
+
+type SortinoValueSuite struct {
+	suite.Suite
+	s       *Sortino
+	globalS *Sortino
+}
+
+func TestSortinoValueSuite(t *testing.T) {
+	suite.Run(t, &SortinoValueSuite{})
+}
+
+func (s *SortinoValueSuite) SetupTest() {
+	var err error
+	s.s, err = NewSortino(1, 3)
+	s.Require().NoError(err)
+	err = Init(s.s)
+	s.Require().NoError(err)
+
+	s.globalS, err = NewGlobalSortino(1)
+	s.Require().NoError(err)
+	err = Init(s.globalS)
+	s.Require().NoError(err)
+
+	xs := []float64{-2, 4, -1, 3, 0}
+	for _, x := range xs {
+		err := s.s.Push(x)
+		s.Require().NoError(err)
+
+		err = s.globalS.Push(x)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *SortinoValueSuite) TestValueSuccess() {
+	// window is {-1, 3, 0}, shortfalls are {-2, 0, -1}
+	value, err := s.s.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), (2./3.-1.)/math.Sqrt(5./3.), value)
+}
+
+func (s *SortinoValueSuite) TestValueSuccessGlobal() {
+	// shortfalls are {-3, 0, -2, 0, -1}
+	value, err := s.globalS.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), (4./5.-1.)/math.Sqrt(14./5.), value)
+}
+
+func (s *SortinoValueSuite) TestValueFailOnNullCore() {
+	sortino, err := NewSortino(1, 3)
+	s.Require().NoError(err)
+
+	_, err = sortino.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *SortinoValueSuite) TestValueFailIfNoValuesSeen() {
+	sortino, err := NewSortino(1, 3)
+	s.Require().NoError(err)
+	err = Init(sortino)
+	s.Require().NoError(err)
+
+	_, err = sortino.Value()
+	testutil.ContainsError(s.T(), err, "no values seen yet")
+}
+
+func (s *SortinoValueSuite) TestValueFailIfDownsideDeviationIsZero() {
+	sortino, err := NewSortino(1, 3)
+	s.Require().NoError(err)
+	err = Init(sortino)
+	s.Require().NoError(err)
+
+	xs := []float64{1, 2, 3}
+	for _, x := range xs {
+		err := sortino.Push(x)
+		s.Require().NoError(err)
+	}
+
+	_, err = sortino.Value()
+	testutil.ContainsError(s.T(), err, "downside deviation is zero")
+}
+
+func TestSortinoClear(t *testing.T) {
+	s, err := NewSortino(1, 3)
+	require.NoError(t, err)
+	err = Init(s)
+	require.NoError(t, err)
+
+	xs := []float64{-2, 4, -1, 3, 0}
+	for _, x := range xs {
+		err := s.Push(x)
+		require.NoError(t, err)
+	}
+
+	s.Clear()
+	assert.Equal(t, int(0), s.core.count)
+	assert.Equal(t, uint64(0), s.core.queue.Len())
+	assert.Equal(t, int(0), s.downside.count)
+	assert.Equal(t, uint64(0), s.downside.queue.Len())
+}
+
+func TestSortinoString(t *testing.T) {
+	s, err := NewSortino(0.5, 3)
+	require.NoError(t, err)
+
+	expectedString := "moment.Sortino_{target:0.5,window:3}"
+	assert.Equal(t, expectedString, s.String())
+}
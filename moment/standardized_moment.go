@@ -0,0 +1,118 @@
+package moment
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// StandardizedMoment is a metric that tracks the kth standardized moment, i.e. the
+// kth population central moment divided by the kth power of the population standard
+// deviation. Skewness and Kurtosis specialize this for k = 3 and k = 4, respectively.
+type StandardizedMoment struct {
+	k      int
+	config *CoreConfig
+	core   *Core
+}
+
+// NewStandardizedMoment instantiates a StandardizedMoment struct.
+func NewStandardizedMoment(k int, window int) *StandardizedMoment {
+	// both configs have the same window and no initial state, so they always merge
+	config, _ := MergeConfigs(New(2, window).Config(), New(k, window).Config())
+
+	return &StandardizedMoment{
+		k:      k,
+		config: config,
+	}
+}
+
+// NewGlobalStandardizedMoment instantiates a global StandardizedMoment struct.
+// This is equivalent to calling NewStandardizedMoment(k, 0).
+func NewGlobalStandardizedMoment(k int) *StandardizedMoment {
+	return NewStandardizedMoment(k, 0)
+}
+
+// SetCore sets the Core.
+func (s *StandardizedMoment) SetCore(c *Core) {
+	s.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (s *StandardizedMoment) IsSetCore() bool {
+	return s.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (s *StandardizedMoment) Config() *CoreConfig {
+	return s.config
+}
+
+// String returns a string representation of the metric.
+func (s *StandardizedMoment) String() string {
+	name := "moment.StandardizedMoment"
+	params := []string{
+		fmt.Sprintf("k:%v", s.k),
+		fmt.Sprintf("window:%v", *s.config.Window),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a new value for StandardizedMoment to consume.
+func (s *StandardizedMoment) Push(x float64) error {
+	if !s.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := s.core.Push(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the kth standardized moment, i.e. m_k/m_2^(k/2), where
+// m_k = S_k/n is the kth population central moment, S_k is the sum of the kth powers
+// of the deviations from the mean and n is the number of values.
+func (s *StandardizedMoment) Value() (float64, error) {
+	if !s.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	s.core.RLock()
+	defer s.core.RUnlock()
+
+	count := s.core.UnsafeWeightSum()
+	if count == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	variance, err := s.core.UnsafeSum(2)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving 2nd moment: %w", err)
+	}
+	variance /= count
+
+	variance, err = clampVariance(variance, NegativeVarianceTolerance)
+	if err != nil {
+		return 0, err
+	} else if variance == 0 {
+		return 0, errors.New("cannot standardize a moment with zero variance")
+	}
+
+	moment, err := s.core.UnsafeSum(s.k)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving %dth moment: %w", s.k, err)
+	}
+	moment /= count
+
+	return moment / math.Pow(variance, float64(s.k)/2), nil
+}
+
+// Clear resets the metric.
+func (s *StandardizedMoment) Clear() {
+	if s.IsSetCore() {
+		s.core.Clear()
+	}
+}
@@ -0,0 +1,131 @@
+package moment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewStandardizedMoment(t *testing.T) {
+	window := 3
+	moment := NewStandardizedMoment(4, window)
+	assert.Equal(t, 4, moment.k)
+	assert.Equal(t, &CoreConfig{
+		Sums: SumsConfig{
+			2: true,
+			4: true,
+		},
+		Window: &window,
+	}, moment.Config())
+}
+
+func TestNewGlobalStandardizedMoment(t *testing.T) {
+	moment := NewStandardizedMoment(3, 0)
+	globalMoment := NewGlobalStandardizedMoment(3)
+	assert.Equal(t, moment, globalMoment)
+}
+
+func TestStandardizedMomentString(t *testing.T) {
+	moment := NewStandardizedMoment(3, 5)
+	expectedString := "moment.StandardizedMoment_{k:3,window:5}"
+	assert.Equal(t, expectedString, moment.String())
+}
+
+func TestStandardizedMomentValue(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 8, -1, 0.5}
+
+	push := func(t *testing.T, metric interface {
+		CoreWrapper
+		Push(float64) error
+	}) {
+		err := Init(metric)
+		require.NoError(t, err)
+		for _, x := range xs {
+			err := metric.Push(x)
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("pass: k = 3 matches the population Skewness", func(t *testing.T) {
+		for _, window := range []int{0, 4} {
+			moment := NewStandardizedMoment(3, window)
+			push(t, moment)
+			skewness := NewSkewness(window, PopulationOption())
+			push(t, skewness)
+
+			expected, err := skewness.Value()
+			require.NoError(t, err)
+			value, err := moment.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, expected, value)
+		}
+	})
+
+	t.Run("pass: k = 4 matches Kurtosis up to the excess kurtosis offset", func(t *testing.T) {
+		for _, window := range []int{0, 4} {
+			moment := NewStandardizedMoment(4, window)
+			push(t, moment)
+			kurtosis := NewKurtosis(window)
+			push(t, kurtosis)
+
+			expected, err := kurtosis.Value()
+			require.NoError(t, err)
+			value, err := moment.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, expected+3, value)
+		}
+	})
+
+	t.Run("pass: k = 2 is 1", func(t *testing.T) {
+		moment := NewGlobalStandardizedMoment(2)
+		push(t, moment)
+		value, err := moment.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 1, value)
+	})
+
+	t.Run("fail: zero variance is an error", func(t *testing.T) {
+		moment := NewGlobalStandardizedMoment(3)
+		err := Init(moment)
+		require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			err := moment.Push(2)
+			require.NoError(t, err)
+		}
+		_, err = moment.Value()
+		testutil.ContainsError(t, err, "cannot standardize a moment with zero variance")
+	})
+
+	t.Run("fail: no values seen is an error", func(t *testing.T) {
+		moment := NewGlobalStandardizedMoment(3)
+		err := Init(moment)
+		require.NoError(t, err)
+		_, err = moment.Value()
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+	})
+
+	t.Run("fail: null Core is an error", func(t *testing.T) {
+		moment := NewGlobalStandardizedMoment(3)
+		_, err := moment.Value()
+		assert.ErrorIs(t, err, ErrorCoreNotSet)
+		err = moment.Push(1)
+		assert.ErrorIs(t, err, ErrorCoreNotSet)
+	})
+}
+
+func TestStandardizedMomentClear(t *testing.T) {
+	moment := NewStandardizedMoment(3, 3)
+	err := Init(moment)
+	require.NoError(t, err)
+	for _, x := range []float64{1, 2, 4} {
+		err := moment.Push(x)
+		require.NoError(t, err)
+	}
+
+	moment.Clear()
+	assert.Equal(t, []float64{0, 0, 0, 0}, moment.core.sums)
+	assert.Equal(t, int(0), moment.core.count)
+}
@@ -3,24 +3,27 @@ package moment
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/pkg/errors"
 )
 
-// Std is a metric that tracks the sample standard deviation.
+// Std is a metric that tracks the sample standard deviation,
+// or the population standard deviation if PopulationOption is provided.
 type Std struct {
-	variance *Moment
+	variance  *Variance
+	observers observers
 }
 
 // NewStd instantiates an Std struct.
-func NewStd(window int) *Std {
-	return &Std{variance: New(2, window)}
+func NewStd(window int, options ...Option) *Std {
+	return &Std{variance: NewVariance(window, options...)}
 }
 
 // NewGlobalStd instantiates a global Std struct.
-// This is equivalent to calling NewStd(0).
-func NewGlobalStd() *Std {
-	return NewStd(0)
+// This is equivalent to calling NewStd(0, options...).
+func NewGlobalStd(options ...Option) *Std {
+	return NewStd(0, options...)
 }
 
 // SetCore sets the Core.
@@ -41,8 +44,14 @@ func (s *Std) Config() *CoreConfig {
 // String returns a string representation of the metric.
 func (s *Std) String() string {
 	name := "moment.Std"
-	window := fmt.Sprintf("window:%v", *s.variance.Config().Window)
-	return fmt.Sprintf("%s_{%s}", name, window)
+	params := []string{fmt.Sprintf("window:%v", s.variance.moment.window)}
+	if s.variance.moment.population {
+		params = append(params, "population:true")
+	}
+	if s.variance.tolerance != NegativeVarianceTolerance {
+		params = append(params, fmt.Sprintf("tolerance:%v", s.variance.tolerance))
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
 }
 
 // Push adds a new value for Std to consume.
@@ -55,10 +64,34 @@ func (s *Std) Push(x float64) error {
 	if err != nil {
 		return errors.Wrap(err, "error pushing to core")
 	}
+	s.observers.notify(s.Value)
+	return nil
+}
+
+// PushBatch adds a batch of values for Std to consume, in order.
+// This is equivalent to calling Push on each value, but more efficient.
+func (s *Std) PushBatch(xs []float64) error {
+	if !s.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := s.variance.PushBatch(xs)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	s.observers.notify(s.Value)
 	return nil
 }
 
-// Value returns the value of the sample standard deviation.
+// OnUpdate registers a callback that is called with the value of the standard
+// deviation after each successful push; see Moment.OnUpdate.
+func (s *Std) OnUpdate(fn func(value float64)) {
+	s.observers.add(fn)
+}
+
+// Value returns the value of the sample standard deviation, i.e. sqrt(S_2/(n-1)),
+// where S_2 is the sum of squared deviations from the mean and n is the number of values.
+// If PopulationOption is provided, this returns sqrt(S_2/n) instead.
 func (s *Std) Value() (float64, error) {
 	if !s.IsSetCore() {
 		return 0, ErrorCoreNotSet
@@ -66,7 +99,7 @@ func (s *Std) Value() (float64, error) {
 
 	variance, err := s.variance.Value()
 	if err != nil {
-		return 0, ErrorRetrievingVariance
+		return 0, wrapSentinel(ErrorRetrievingVariance, err)
 	}
 	return math.Sqrt(variance), nil
 }
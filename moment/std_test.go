@@ -13,7 +13,7 @@ import (
 
 func TestNewStd(t *testing.T) {
 	std := NewStd(3)
-	assert.Equal(t, New(2, 3), std.variance)
+	assert.Equal(t, NewVariance(3), std.variance)
 }
 
 func TestNewGlobalStd(t *testing.T) {
@@ -50,7 +50,7 @@ func (s *StdPushSuite) TestPushFailOnNullCore() {
 
 func (s *StdPushSuite) TestPushFailOnQueueInsertionFailure() {
 	// dispose the queue to simulate an error when we try to insert into the queue
-	s.std.variance.core.queue.Dispose()
+	s.std.variance.moment.core.queue.Dispose()
 
 	err := s.std.Push(3.)
 	testutil.ContainsError(s.T(), err, "error pushing to core")
@@ -64,12 +64,32 @@ func (s *StdPushSuite) TestPushFailOnQueueRetrievalFailure() {
 	}
 
 	// dispose the queue to simulate an error when we try to retrieve from the queue
-	s.std.variance.core.queue.Dispose()
+	s.std.variance.moment.core.queue.Dispose()
 
 	err := s.std.Push(3.)
 	testutil.ContainsError(s.T(), err, "error pushing to core")
 }
 
+func (s *StdPushSuite) TestPushBatchSuccess() {
+	err := s.std.PushBatch([]float64{1, 2, 3, 4})
+	s.Require().NoError(err)
+	s.Equal(3, s.std.variance.moment.core.Count())
+}
+
+func (s *StdPushSuite) TestPushBatchFailOnNullCore() {
+	std := NewStd(3)
+	err := std.PushBatch([]float64{0.})
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *StdPushSuite) TestPushBatchFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.std.variance.moment.core.queue.Dispose()
+
+	err := s.std.PushBatch([]float64{3.})
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
 type StdValueSuite struct {
 	suite.Suite
 	std *Std
@@ -125,13 +145,21 @@ func TestStdClear(t *testing.T) {
 
 	std.Clear()
 	expectedSums := []float64{0, 0, 0}
-	assert.Equal(t, expectedSums, std.variance.core.sums)
-	assert.Equal(t, int(0), std.variance.core.count)
-	assert.Equal(t, uint64(0), std.variance.core.queue.Len())
+	assert.Equal(t, expectedSums, std.variance.moment.core.sums)
+	assert.Equal(t, int(0), std.variance.moment.core.count)
+	assert.Equal(t, uint64(0), std.variance.moment.core.queue.Len())
 }
 
 func TestStdString(t *testing.T) {
 	std := NewStd(3)
 	expectedString := "moment.Std_{window:3}"
 	assert.Equal(t, expectedString, std.String())
+
+	std = NewStd(3, PopulationOption())
+	expectedString = "moment.Std_{window:3,population:true}"
+	assert.Equal(t, expectedString, std.String())
+
+	std = NewStd(3, ToleranceOption(1e-6))
+	expectedString = "moment.Std_{window:3,tolerance:1e-06}"
+	assert.Equal(t, expectedString, std.String())
 }
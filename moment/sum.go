@@ -0,0 +1,98 @@
+package moment
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Sum is a metric that tracks the sum of the values, i.e. a rolling sum if a window
+// is set. The sum is maintained directly by the Core, rather than derived from the mean.
+type Sum struct {
+	window int
+	core   *Core
+}
+
+// NewSum instantiates a Sum struct.
+func NewSum(window int) *Sum {
+	return &Sum{window: window}
+}
+
+// NewGlobalSum instantiates a global Sum struct.
+// This is equivalent to calling NewSum(0).
+func NewGlobalSum() *Sum {
+	return NewSum(0)
+}
+
+// SetCore sets the Core.
+func (s *Sum) SetCore(c *Core) {
+	s.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (s *Sum) IsSetCore() bool {
+	return s.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (s *Sum) Config() *CoreConfig {
+	return &CoreConfig{
+		Window: &s.window,
+	}
+}
+
+// String returns a string representation of the metric.
+func (s *Sum) String() string {
+	name := "moment.Sum"
+	window := fmt.Sprintf("window:%v", s.window)
+	return fmt.Sprintf("%s_{%s}", name, window)
+}
+
+// Push adds a new value for Sum to consume.
+func (s *Sum) Push(x float64) error {
+	if !s.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := s.core.Push(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// PushBatch adds a batch of values for Sum to consume, in order.
+// This is equivalent to calling Push on each value, but more efficient.
+func (s *Sum) PushBatch(xs []float64) error {
+	if !s.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := s.core.PushBatch(xs)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the sum.
+func (s *Sum) Value() (float64, error) {
+	if !s.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	s.core.RLock()
+	defer s.core.RUnlock()
+
+	if s.core.UnsafeCount() == 0 {
+		return 0, ErrorRetrievingSumDueToNoValuesSeen
+	}
+	return s.core.UnsafeSumRaw(), nil
+}
+
+// Clear resets the metric.
+func (s *Sum) Clear() {
+	if s.IsSetCore() {
+		s.core.Clear()
+	}
+}
@@ -0,0 +1,111 @@
+package moment
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewSum(t *testing.T) {
+	sum := NewSum(3)
+	assert.Equal(t, 3, sum.window)
+}
+
+func TestNewGlobalSum(t *testing.T) {
+	sum := NewSum(0)
+	globalSum := NewGlobalSum()
+	assert.Equal(t, sum, globalSum)
+}
+
+func TestSumValue(t *testing.T) {
+	t.Run("pass: rolling sum matches brute force sum of the window", func(t *testing.T) {
+		window := 5
+		sum := NewSum(window)
+		require.NoError(t, Init(sum))
+
+		rng := rand.New(rand.NewSource(0))
+		xs := make([]float64, 200)
+		for i := range xs {
+			xs[i] = rng.NormFloat64() * 100
+			require.NoError(t, sum.Push(xs[i]))
+
+			expected := 0.
+			for j := i; j >= 0 && j > i-window; j-- {
+				expected += xs[j]
+			}
+
+			val, err := sum.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, expected, val, "sum after %d values", i+1)
+		}
+	})
+
+	t.Run("pass: global sum matches brute force sum", func(t *testing.T) {
+		sum := NewGlobalSum()
+		require.NoError(t, Init(sum))
+
+		xs := []float64{1, 2, 3, 4, 8, -3, 0.5}
+		require.NoError(t, sum.PushBatch(xs))
+
+		val, err := sum.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 15.5, val)
+	})
+
+	t.Run("pass: eviction of a large value leaves no residue", func(t *testing.T) {
+		// the sum is not derived from the mean, so once 1e16 leaves
+		// the window, the small values are summed exactly
+		sum := NewSum(3)
+		require.NoError(t, Init(sum))
+
+		for _, x := range []float64{1e16, 1, 2, 3} {
+			require.NoError(t, sum.Push(x))
+		}
+
+		val, err := sum.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 6., val)
+	})
+
+	t.Run("fail: no values seen", func(t *testing.T) {
+		sum := NewSum(3)
+		require.NoError(t, Init(sum))
+
+		_, err := sum.Value()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+
+	t.Run("fail: Core is not set", func(t *testing.T) {
+		sum := NewSum(3)
+		err := sum.Push(1)
+		testutil.ContainsError(t, err, "Core is not set")
+		err = sum.PushBatch([]float64{1})
+		testutil.ContainsError(t, err, "Core is not set")
+		_, err = sum.Value()
+		testutil.ContainsError(t, err, "Core is not set")
+	})
+}
+
+func TestSumClear(t *testing.T) {
+	sum := NewSum(3)
+	require.NoError(t, Init(sum))
+
+	for _, x := range []float64{1, 2, 3, 4, 8} {
+		require.NoError(t, sum.Push(x))
+	}
+
+	sum.Clear()
+	assert.Equal(t, 0, sum.core.count)
+	assert.Equal(t, 0., sum.core.total)
+	assert.Equal(t, uint64(0), sum.core.queue.Len())
+}
+
+func TestSumString(t *testing.T) {
+	sum := NewSum(3)
+	expectedString := "moment.Sum_{window:3}"
+	assert.Equal(t, expectedString, sum.String())
+}
@@ -0,0 +1,81 @@
+package moment
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// timedValue is a value pushed to a Core with a time window, along with its timestamp.
+type timedValue struct {
+	x float64
+	t time.Time
+}
+
+// PushAt adds a new value with a timestamp for a Core object with a time window
+// to consume. Values with timestamps at or before t minus the duration of the window
+// are evicted first, so that the window covers the half-open interval (t - duration, t].
+// Timestamps must be pushed in nondecreasing order.
+func (c *Core) PushAt(x float64, t time.Time) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.UnsafePushAt(x, t)
+}
+
+// UnsafePushAt adds a new value with a timestamp for a Core object with a
+// time window to consume, but does not lock. This should only be used if the user
+// plans to make use of the Lock()/Unlock() Core methods.
+func (c *Core) UnsafePushAt(x float64, t time.Time) error {
+	if c.duration == 0 {
+		return errors.New("Core does not have a time window")
+	}
+	if t.Before(c.latest) {
+		return errors.Errorf("timestamp %v precedes the latest timestamp %v", t, c.latest)
+	}
+
+	ok, err := c.nonFinite.Admit(x)
+	if err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	c.evict(t)
+	c.timed.PushBack(timedValue{x: x, t: t})
+	c.latest = t
+	c.add(x)
+	return nil
+}
+
+// Advance moves the time window of a Core forward to end at t without pushing a value,
+// evicting the values with timestamps at or before t minus the duration of the window;
+// this keeps the window current when no values arrive. Since the window then ends at t,
+// values pushed afterwards must not have timestamps before t.
+func (c *Core) Advance(t time.Time) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.UnsafeAdvance(t)
+}
+
+// UnsafeAdvance moves the time window of a Core forward to end at t, but does not lock.
+// This should only be used if the user plans to make use of the Lock()/Unlock() Core methods.
+func (c *Core) UnsafeAdvance(t time.Time) error {
+	if c.duration == 0 {
+		return errors.New("Core does not have a time window")
+	}
+	if t.Before(c.latest) {
+		return errors.Errorf("timestamp %v precedes the latest timestamp %v", t, c.latest)
+	}
+
+	c.evict(t)
+	c.latest = t
+	return nil
+}
+
+// evict removes all values that have fallen out of the time window ending at t.
+func (c *Core) evict(t time.Time) {
+	cutoff := t.Add(-c.duration)
+	for c.timed.Len() > 0 && !c.timed.Front().t.After(cutoff) {
+		c.remove(c.timed.PopFront().x)
+	}
+}
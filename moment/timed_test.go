@@ -0,0 +1,201 @@
+package moment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/K4Mobility/stream"
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func newTimedCore(t *testing.T, duration time.Duration) *Core {
+	core, err := NewCore(&CoreConfig{
+		Sums:     SumsConfig{2: true, 3: true},
+		Window:   stream.IntPtr(0),
+		Duration: &duration,
+	})
+	require.NoError(t, err)
+	return core
+}
+
+func TestPushAt(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("pass: evicts values outside of the time window", func(t *testing.T) {
+		core := newTimedCore(t, 5*time.Minute)
+
+		// the value at 0m is evicted once 5m is reached, and the
+		// one at 1m is evicted once 6m is reached
+		offsets := []time.Duration{0, time.Minute, 3 * time.Minute, 5 * time.Minute, 6 * time.Minute}
+		xs := []float64{100, 1, 2, 4, 8}
+		for i, x := range xs {
+			err := core.PushAt(x, start.Add(offsets[i]))
+			require.NoError(t, err)
+		}
+
+		expected, err := NewCore(&CoreConfig{Sums: SumsConfig{2: true, 3: true}, Window: stream.IntPtr(0)})
+		require.NoError(t, err)
+		err = expected.PushBatch([]float64{2, 4, 8})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, core.Count())
+		assert.Equal(t, 3, core.timed.Len())
+		testutil.Approx(t, expected.mean, core.mean)
+		for k := range expected.sums {
+			testutil.Approx(t, expected.sums[k], core.sums[k])
+		}
+	})
+
+	t.Run("pass: long gap empties the window before pushing", func(t *testing.T) {
+		core := newTimedCore(t, time.Minute)
+
+		for i, x := range []float64{1, 2, 3} {
+			err := core.PushAt(x, start.Add(time.Duration(i)*time.Second))
+			require.NoError(t, err)
+		}
+
+		err := core.PushAt(10, start.Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, core.Count())
+		assert.Equal(t, 1, core.timed.Len())
+		mean, err := core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 10., mean)
+		sum, err := core.Sum(2)
+		require.NoError(t, err)
+		testutil.Approx(t, 0., sum)
+	})
+
+	t.Run("pass: equal timestamps are accepted", func(t *testing.T) {
+		core := newTimedCore(t, time.Minute)
+
+		err := core.PushAt(1, start)
+		require.NoError(t, err)
+		err = core.PushAt(2, start)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, core.Count())
+	})
+
+	t.Run("fail: out of order timestamp returns error", func(t *testing.T) {
+		core := newTimedCore(t, time.Minute)
+
+		err := core.PushAt(1, start)
+		require.NoError(t, err)
+
+		err = core.PushAt(2, start.Add(-time.Second))
+		testutil.ContainsError(t, err, "precedes the latest timestamp")
+		assert.Equal(t, 1, core.Count())
+	})
+
+	t.Run("fail: core without a time window returns error", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+
+		err = core.PushAt(1, start)
+		testutil.ContainsError(t, err, "Core does not have a time window")
+	})
+
+	t.Run("fail: pushing without a timestamp returns error", func(t *testing.T) {
+		core := newTimedCore(t, time.Minute)
+
+		err := core.Push(1)
+		testutil.ContainsError(t, err, "values must be pushed with PushAt to a Core with a time window")
+	})
+}
+
+func TestAdvance(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("pass: evicts values outside of the time window without pushing", func(t *testing.T) {
+		core := newTimedCore(t, 5*time.Minute)
+
+		for i, x := range []float64{1, 2, 4} {
+			err := core.PushAt(x, start.Add(time.Duration(i)*time.Minute))
+			require.NoError(t, err)
+		}
+
+		// the values at 0m and 1m are evicted
+		err := core.Advance(start.Add(6 * time.Minute))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, core.Count())
+		assert.Equal(t, 1, core.timed.Len())
+		mean, err := core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 4., mean)
+
+		// the value at 2m is evicted too, leaving the window empty
+		err = core.Advance(start.Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, core.Count())
+		_, err = core.Mean()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+
+	t.Run("pass: advances the window of a Moment", func(t *testing.T) {
+		m := NewTimed(1, time.Minute)
+		err := Init(m)
+		require.NoError(t, err)
+
+		err = m.PushAt(3, start)
+		require.NoError(t, err)
+		err = m.PushAt(5, start.Add(30*time.Second))
+		require.NoError(t, err)
+
+		err = m.Advance(start.Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, m.core.Count())
+		mean, err := m.core.Mean()
+		require.NoError(t, err)
+		testutil.Approx(t, 5., mean)
+	})
+
+	t.Run("fail: values before the end of the window are rejected", func(t *testing.T) {
+		core := newTimedCore(t, time.Minute)
+
+		err := core.Advance(start.Add(time.Hour))
+		require.NoError(t, err)
+
+		err = core.PushAt(1, start)
+		testutil.ContainsError(t, err, "precedes the latest timestamp")
+		err = core.Advance(start)
+		testutil.ContainsError(t, err, "precedes the latest timestamp")
+	})
+
+	t.Run("fail: core without a time window returns error", func(t *testing.T) {
+		core, err := NewCore(&CoreConfig{Window: stream.IntPtr(3)})
+		require.NoError(t, err)
+
+		err = core.Advance(start)
+		testutil.ContainsError(t, err, "Core does not have a time window")
+	})
+
+	t.Run("fail: moment without a core returns error", func(t *testing.T) {
+		m := NewTimed(1, time.Minute)
+		err := m.Advance(start)
+		testutil.ContainsError(t, err, "Core is not set")
+	})
+}
+
+func TestTimedClear(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	core := newTimedCore(t, time.Minute)
+
+	err := core.PushAt(1, start.Add(time.Hour))
+	require.NoError(t, err)
+
+	core.Clear()
+	assert.Equal(t, 0, core.Count())
+	assert.Equal(t, 0, core.timed.Len())
+
+	// timestamps before those pushed prior to clearing are accepted again
+	err = core.PushAt(1, start)
+	require.NoError(t, err)
+}
@@ -0,0 +1,77 @@
+package moment
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// TrackingError is a metric that tracks the tracking error between a portfolio
+// and a benchmark, i.e. the sample standard deviation of the differences between
+// their returns. It does not satisfy the Metric interface, since it consumes a pair
+// of returns at a time, but it is a CoreWrapper and should be passed into Init().
+type TrackingError struct {
+	std *Std
+}
+
+// NewTrackingError instantiates a TrackingError struct.
+func NewTrackingError(window int) *TrackingError {
+	return &TrackingError{std: NewStd(window)}
+}
+
+// NewGlobalTrackingError instantiates a global TrackingError struct.
+// This is equivalent to calling NewTrackingError(0).
+func NewGlobalTrackingError() *TrackingError {
+	return NewTrackingError(0)
+}
+
+// SetCore sets the Core.
+func (t *TrackingError) SetCore(c *Core) {
+	t.std.SetCore(c)
+}
+
+// IsSetCore returns if the core has been set.
+func (t *TrackingError) IsSetCore() bool {
+	return t.std.IsSetCore()
+}
+
+// Config returns the CoreConfig needed.
+func (t *TrackingError) Config() *CoreConfig {
+	return t.std.Config()
+}
+
+// String returns a string representation of the metric.
+func (t *TrackingError) String() string {
+	name := "moment.TrackingError"
+	window := fmt.Sprintf("window:%v", *t.std.Config().Window)
+	return fmt.Sprintf("%s_{%s}", name, window)
+}
+
+// Push adds a new pair of portfolio and benchmark returns for TrackingError to consume.
+func (t *TrackingError) Push(portfolio float64, benchmark float64) error {
+	if !t.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := t.std.Push(portfolio - benchmark)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	return nil
+}
+
+// Value returns the value of the tracking error.
+func (t *TrackingError) Value() (float64, error) {
+	if !t.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	return t.std.Value()
+}
+
+// Clear resets the metric.
+func (t *TrackingError) Clear() {
+	if t.IsSetCore() {
+		t.std.Clear()
+	}
+}
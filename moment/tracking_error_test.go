@@ -0,0 +1,119 @@
+package moment
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewTrackingError(t *testing.T) {
+	te := NewTrackingError(3)
+	assert.Equal(t, NewStd(3), te.std)
+}
+
+func TestNewGlobalTrackingError(t *testing.T) {
+	te := NewTrackingError(0)
+	globalTE := NewGlobalTrackingError()
+	assert.Equal(t, te, globalTE)
+}
+
+type TrackingErrorPushSuite struct {
+	suite.Suite
+	te *TrackingError
+}
+
+func TestTrackingErrorPushSuite(t *testing.T) {
+	suite.Run(t, &TrackingErrorPushSuite{})
+}
+
+func (s *TrackingErrorPushSuite) SetupTest() {
+	s.te = NewTrackingError(3)
+	err := Init(s.te)
+	s.Require().NoError(err)
+}
+
+func (s *TrackingErrorPushSuite) TestPushSuccess() {
+	err := s.te.Push(3., 1.)
+	s.Require().NoError(err)
+
+	mean, err := s.te.std.variance.moment.core.Mean()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), 2., mean)
+}
+
+func (s *TrackingErrorPushSuite) TestPushFailOnNullCore() {
+	te := NewTrackingError(3)
+	err := te.Push(0., 0.)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *TrackingErrorPushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.te.std.variance.moment.core.queue.Dispose()
+
+	err := s.te.Push(3., 1.)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+type TrackingErrorValueSuite struct {
+	suite.Suite
+	te *TrackingError
+}
+
+func TestTrackingErrorValueSuite(t *testing.T) {
+	suite.Run(t, &TrackingErrorValueSuite{})
+}
+
+func (s *TrackingErrorValueSuite) SetupTest() {
+	s.te = NewTrackingError(3)
+	err := Init(s.te)
+	s.Require().NoError(err)
+
+	// differences are 1, 2, 3, 4, 8
+	portfolio := []float64{2, 1, 5, 3, 10}
+	benchmark := []float64{1, -1, 2, -1, 2}
+	for i := range portfolio {
+		err := s.te.Push(portfolio[i], benchmark[i])
+		s.Require().NoError(err)
+	}
+}
+
+func (s *TrackingErrorValueSuite) TestValueSuccess() {
+	value, err := s.te.Value()
+	s.Require().NoError(err)
+	testutil.Approx(s.T(), math.Sqrt(7.), value)
+}
+
+func (s *TrackingErrorValueSuite) TestValueFailOnNullCore() {
+	te := NewTrackingError(3)
+	_, err := te.Value()
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func TestTrackingErrorClear(t *testing.T) {
+	te := NewTrackingError(3)
+	err := Init(te)
+	require.NoError(t, err)
+
+	for i := 0.; i < 5; i++ {
+		err := te.Push(i*i, i)
+		require.NoError(t, err)
+	}
+
+	te.Clear()
+	expectedSums := []float64{0, 0, 0}
+	assert.Equal(t, expectedSums, te.std.variance.moment.core.sums)
+	assert.Equal(t, int(0), te.std.variance.moment.core.count)
+	assert.Equal(t, uint64(0), te.std.variance.moment.core.queue.Len())
+}
+
+func TestTrackingErrorString(t *testing.T) {
+	te := NewTrackingError(3)
+	expectedString := "moment.TrackingError_{window:3}"
+	assert.Equal(t, expectedString, te.String())
+}
@@ -0,0 +1,133 @@
+package moment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NegativeVarianceTolerance is the most negative value of a variance that is attributed
+// to floating-point cancellation in the power sum updates, which can leave a variance
+// that should be zero slightly negative after many values have been added and removed.
+// Variances in [-NegativeVarianceTolerance, 0) are clamped to zero; more negative ones
+// are reported as ErrorNegativeVariance. The tolerance can be set with ToleranceOption.
+const NegativeVarianceTolerance = 1e-9
+
+// Variance is a metric that tracks the sample variance,
+// or the population variance if PopulationOption is provided.
+type Variance struct {
+	moment    *Moment
+	tolerance float64
+	observers observers
+}
+
+// NewVariance instantiates a Variance struct.
+func NewVariance(window int, options ...Option) *Variance {
+	return &Variance{
+		moment:    New(2, window, options...),
+		tolerance: varianceTolerance(options),
+	}
+}
+
+// NewGlobalVariance instantiates a global Variance struct.
+// This is equivalent to calling NewVariance(0, options...).
+func NewGlobalVariance(options ...Option) *Variance {
+	return NewVariance(0, options...)
+}
+
+// SetCore sets the Core.
+func (v *Variance) SetCore(c *Core) {
+	v.moment.SetCore(c)
+}
+
+// IsSetCore returns if the core has been set.
+func (v *Variance) IsSetCore() bool {
+	return v.moment.IsSetCore()
+}
+
+// Config returns the CoreConfig needed.
+func (v *Variance) Config() *CoreConfig {
+	return v.moment.Config()
+}
+
+// String returns a string representation of the metric.
+func (v *Variance) String() string {
+	name := "moment.Variance"
+	params := []string{fmt.Sprintf("window:%v", v.moment.window)}
+	if v.moment.population {
+		params = append(params, "population:true")
+	}
+	if v.tolerance != NegativeVarianceTolerance {
+		params = append(params, fmt.Sprintf("tolerance:%v", v.tolerance))
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a new value for Variance to consume.
+func (v *Variance) Push(x float64) error {
+	if !v.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := v.moment.Push(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	v.observers.notify(v.Value)
+	return nil
+}
+
+// PushBatch adds a batch of values for Variance to consume, in order.
+// This is equivalent to calling Push on each value, but more efficient.
+func (v *Variance) PushBatch(xs []float64) error {
+	if !v.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	err := v.moment.PushBatch(xs)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+	v.observers.notify(v.Value)
+	return nil
+}
+
+// OnUpdate registers a callback that is called with the value of the variance
+// after each successful push; see Moment.OnUpdate.
+func (v *Variance) OnUpdate(fn func(value float64)) {
+	v.observers.add(fn)
+}
+
+// Value returns the value of the sample variance, i.e. S_2/(n-1), where S_2 is the
+// sum of squared deviations from the mean and n is the number of values.
+// If PopulationOption is provided, this returns S_2/n instead. A marginally negative
+// variance is clamped to zero; see NegativeVarianceTolerance and ToleranceOption.
+func (v *Variance) Value() (float64, error) {
+	// the 2nd central moment is exactly the variance, so its errors
+	// need no further context
+	variance, err := v.moment.Value()
+	if err != nil {
+		return 0, err
+	}
+	return clampVariance(variance, v.tolerance)
+}
+
+// Clear resets the metric.
+func (v *Variance) Clear() {
+	if v.IsSetCore() {
+		v.moment.Clear()
+	}
+}
+
+// clampVariance clamps a marginally negative variance to zero, and returns
+// ErrorNegativeVariance if it is negative beyond the tolerance.
+func clampVariance(variance float64, tolerance float64) (float64, error) {
+	// NaNs (e.g. the sample variance of a single value) are passed through
+	if !(variance < 0) {
+		return variance, nil
+	} else if variance >= -tolerance {
+		return 0, nil
+	}
+	return 0, fmt.Errorf("%w: %v", ErrorNegativeVariance, variance)
+}
@@ -0,0 +1,288 @@
+package moment
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewVariance(t *testing.T) {
+	variance := NewVariance(3)
+	assert.Equal(t, New(2, 3), variance.moment)
+
+	variance = NewVariance(3, PopulationOption())
+	assert.Equal(t, New(2, 3, PopulationOption()), variance.moment)
+}
+
+func TestNewGlobalVariance(t *testing.T) {
+	variance := NewVariance(0)
+	globalVariance := NewGlobalVariance()
+	assert.Equal(t, variance, globalVariance)
+}
+
+func TestVariancePush(t *testing.T) {
+	t.Run("pass: successfully pushes values", func(t *testing.T) {
+		variance := NewVariance(3)
+		err := Init(variance)
+		require.NoError(t, err)
+
+		err = variance.Push(3.)
+		assert.NoError(t, err)
+
+		err = variance.PushBatch([]float64{1, 2, 3, 4})
+		require.NoError(t, err)
+		assert.Equal(t, 3, variance.moment.core.Count())
+	})
+
+	t.Run("fail: null Core returns error", func(t *testing.T) {
+		variance := NewVariance(3)
+		err := variance.Push(0.)
+		assert.ErrorIs(t, err, ErrorCoreNotSet)
+
+		err = variance.PushBatch([]float64{0.})
+		assert.ErrorIs(t, err, ErrorCoreNotSet)
+	})
+
+	t.Run("fail: if queue insertion fails, return error", func(t *testing.T) {
+		variance := NewVariance(3)
+		err := Init(variance)
+		require.NoError(t, err)
+
+		// dispose the queue to simulate an error when we try to insert into the queue
+		variance.moment.core.queue.Dispose()
+		err = variance.Push(3.)
+		testutil.ContainsError(t, err, "error pushing to core")
+	})
+}
+
+func TestVarianceValue(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 8}
+
+	t.Run("pass: matches the square of Std", func(t *testing.T) {
+		for _, options := range [][]Option{nil, {PopulationOption()}} {
+			for _, window := range []int{0, 3} {
+				variance := NewVariance(window, options...)
+				std := NewStd(window, options...)
+				err := Init(variance)
+				require.NoError(t, err)
+				err = Init(std)
+				require.NoError(t, err)
+
+				for _, x := range xs {
+					err := variance.Push(x)
+					require.NoError(t, err)
+					err = std.Push(x)
+					require.NoError(t, err)
+				}
+
+				value, err := variance.Value()
+				require.NoError(t, err)
+				stdValue, err := std.Value()
+				require.NoError(t, err)
+				testutil.Approx(t, stdValue*stdValue, value)
+			}
+		}
+	})
+
+	t.Run("pass: applies Bessel's correction by default", func(t *testing.T) {
+		variance := NewVariance(3)
+		err := Init(variance)
+		require.NoError(t, err)
+		for _, x := range xs {
+			err := variance.Push(x)
+			require.NoError(t, err)
+		}
+
+		value, err := variance.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 7., value)
+
+		population := NewGlobalVariance(PopulationOption())
+		err = Init(population)
+		require.NoError(t, err)
+		for _, x := range xs {
+			err := population.Push(x)
+			require.NoError(t, err)
+		}
+
+		value, err = population.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 29.2/5, value)
+	})
+
+	t.Run("fail: null Core returns error", func(t *testing.T) {
+		variance := NewVariance(3)
+		_, err := variance.Value()
+		assert.ErrorIs(t, err, ErrorCoreNotSet)
+	})
+
+	t.Run("fail: if no values seen, return error", func(t *testing.T) {
+		variance := NewVariance(3)
+		err := Init(variance)
+		require.NoError(t, err)
+
+		_, err = variance.Value()
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+	})
+}
+
+func TestClampVariance(t *testing.T) {
+	t.Run("pass: nonnegative variances are unchanged", func(t *testing.T) {
+		for _, variance := range []float64{0, 1e-20, 3.5} {
+			value, err := clampVariance(variance, NegativeVarianceTolerance)
+			require.NoError(t, err)
+			assert.Equal(t, variance, value)
+		}
+
+		value, err := clampVariance(math.NaN(), NegativeVarianceTolerance)
+		require.NoError(t, err)
+		assert.True(t, math.IsNaN(value))
+	})
+
+	t.Run("pass: marginally negative variances are clamped to zero", func(t *testing.T) {
+		for _, variance := range []float64{-1e-20, -NegativeVarianceTolerance} {
+			value, err := clampVariance(variance, NegativeVarianceTolerance)
+			require.NoError(t, err)
+			assert.Equal(t, 0., value)
+		}
+	})
+
+	t.Run("fail: clearly negative variances are an error", func(t *testing.T) {
+		_, err := clampVariance(-1e-3, NegativeVarianceTolerance)
+		assert.ErrorIs(t, err, ErrorNegativeVariance)
+		assert.EqualError(t, err, "variance is negative beyond tolerance due to numerical instability: -0.001")
+	})
+
+	t.Run("pass: metrics clamp the variance of the Core", func(t *testing.T) {
+		variance := NewVariance(3)
+		err := Init(variance)
+		require.NoError(t, err)
+		std := NewStd(3)
+		err = Init(std)
+		require.NoError(t, err)
+
+		for _, metric := range []interface {
+			Push(float64) error
+			Value() (float64, error)
+		}{variance, std} {
+			for _, x := range []float64{1, 1, 1} {
+				err := metric.Push(x)
+				require.NoError(t, err)
+			}
+		}
+
+		// simulate cancellation leaving the sum of squares slightly negative
+		variance.moment.core.sums[2] = -1e-12
+		std.variance.moment.core.sums[2] = -1e-12
+
+		value, err := variance.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 0., value)
+
+		value, err = std.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 0., value)
+
+		std.variance.moment.core.sums[2] = -1
+		_, err = std.Value()
+		assert.ErrorIs(t, err, ErrorNegativeVariance)
+		assert.ErrorIs(t, err, ErrorRetrievingVariance)
+	})
+}
+
+func TestVarianceNoNaNAfterManyWindowCycles(t *testing.T) {
+	variance := NewVariance(3)
+	err := Init(variance)
+	require.NoError(t, err)
+	std := NewStd(3)
+	err = Init(std)
+	require.NoError(t, err)
+
+	rng := rand.New(rand.NewSource(1))
+	negative := 0
+	for i := 0; i < 100000; i++ {
+		// runs of a constant value after random ones should have a variance
+		// of exactly zero, but cancellation can leave the sums slightly negative
+		x := 100 * rng.Float64()
+		if i%50 >= 40 {
+			x = 0.1
+		}
+		err := variance.Push(x)
+		require.NoError(t, err)
+		err = std.Push(x)
+		require.NoError(t, err)
+		if i == 0 {
+			// the sample variance of a single value is undefined
+			continue
+		}
+		if variance.moment.core.sums[2] < 0 {
+			negative++
+		}
+
+		value, err := variance.Value()
+		require.NoError(t, err)
+		require.False(t, math.IsNaN(value), "variance is NaN after %d values", i+1)
+		require.GreaterOrEqual(t, value, 0.)
+
+		value, err = std.Value()
+		require.NoError(t, err)
+		require.False(t, math.IsNaN(value), "std is NaN after %d values", i+1)
+	}
+
+	// make sure that the guard was actually exercised
+	assert.Greater(t, negative, 0)
+}
+
+func TestToleranceOption(t *testing.T) {
+	variance := NewVariance(3, ToleranceOption(1e-3))
+	err := Init(variance)
+	require.NoError(t, err)
+	for _, x := range []float64{1, 1, 1} {
+		err := variance.Push(x)
+		require.NoError(t, err)
+	}
+
+	variance.moment.core.sums[2] = -1e-4
+	value, err := variance.Value()
+	require.NoError(t, err)
+	assert.Equal(t, 0., value)
+
+	variance.moment.core.sums[2] = -1e-2
+	_, err = variance.Value()
+	assert.ErrorIs(t, err, ErrorNegativeVariance)
+
+	assert.Equal(t, NegativeVarianceTolerance, NewSkewness(3).tolerance)
+	assert.Equal(t, 1e-3, NewSkewness(3, ToleranceOption(1e-3)).tolerance)
+	assert.Equal(t, 1e-3, NewKurtosis(3, ToleranceOption(1e-3)).tolerance)
+	assert.Equal(t, 1e-3, NewStd(3, ToleranceOption(1e-3)).variance.tolerance)
+}
+
+func TestVarianceClear(t *testing.T) {
+	variance := NewVariance(3)
+	err := Init(variance)
+	require.NoError(t, err)
+
+	err = variance.PushBatch([]float64{1, 2, 3, 4, 8})
+	require.NoError(t, err)
+
+	variance.Clear()
+	assert.Equal(t, []float64{0, 0, 0}, variance.moment.core.sums)
+	assert.Equal(t, int(0), variance.moment.core.count)
+	assert.Equal(t, uint64(0), variance.moment.core.queue.Len())
+}
+
+func TestVarianceString(t *testing.T) {
+	variance := NewVariance(3)
+	assert.Equal(t, "moment.Variance_{window:3}", variance.String())
+
+	variance = NewVariance(3, PopulationOption())
+	assert.Equal(t, "moment.Variance_{window:3,population:true}", variance.String())
+
+	variance = NewVariance(3, ToleranceOption(1e-6))
+	assert.Equal(t, "moment.Variance_{window:3,tolerance:1e-06}", variance.String())
+}
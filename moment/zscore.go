@@ -0,0 +1,135 @@
+package moment
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// ZScore is a metric that tracks the z-score of the most recently pushed value, i.e.
+// its distance from the mean in units of the sample standard deviation, both taken over
+// the values seen so far (including the most recent one). It can track either the
+// global statistics, or over a rolling window; it can also track exponentially
+// weighted statistics, like EWMA and EWMStd.
+type ZScore struct {
+	window int
+	decay  *float64
+	core   *Core
+	last   float64
+}
+
+// NewZScore instantiates a ZScore struct.
+func NewZScore(window int) *ZScore {
+	return &ZScore{window: window}
+}
+
+// NewGlobalZScore instantiates a global ZScore struct.
+// This is equivalent to calling NewZScore(0).
+func NewGlobalZScore() *ZScore {
+	return NewZScore(0)
+}
+
+// NewEWZScore instantiates an exponentially weighted ZScore struct.
+func NewEWZScore(decay float64) *ZScore {
+	return &ZScore{decay: &decay}
+}
+
+// SetCore sets the Core.
+func (z *ZScore) SetCore(c *Core) {
+	z.core = c
+}
+
+// IsSetCore returns if the core has been set.
+func (z *ZScore) IsSetCore() bool {
+	return z.core != nil
+}
+
+// Config returns the CoreConfig needed.
+func (z *ZScore) Config() *CoreConfig {
+	return &CoreConfig{
+		Sums:   SumsConfig{2: true},
+		Window: &z.window,
+		Decay:  z.decay,
+	}
+}
+
+// String returns a string representation of the metric.
+func (z *ZScore) String() string {
+	name := "moment.ZScore"
+	if z.decay != nil {
+		return fmt.Sprintf("%s_{decay:%v}", name, *z.decay)
+	}
+	return fmt.Sprintf("%s_{window:%v}", name, z.window)
+}
+
+// Push adds a new value for ZScore to consume.
+func (z *ZScore) Push(x float64) error {
+	if !z.IsSetCore() {
+		return ErrorCoreNotSet
+	}
+
+	// hold the lock on the core while remembering the value,
+	// so that Value never sees the value without its statistics
+	z.core.Lock()
+	defer z.core.Unlock()
+
+	err := z.core.UnsafePush(x)
+	if err != nil {
+		return errors.Wrap(err, "error pushing to core")
+	}
+
+	// values skipped by the core are not remembered either
+	if ok, _ := z.core.nonFinite.Admit(x); ok {
+		z.last = x
+	}
+	return nil
+}
+
+// Value returns the z-score of the most recently pushed value.
+func (z *ZScore) Value() (float64, error) {
+	if !z.IsSetCore() {
+		return 0, ErrorCoreNotSet
+	}
+
+	z.core.RLock()
+	defer z.core.RUnlock()
+
+	count := z.core.UnsafeCount()
+	if count < 2 {
+		return 0, errors.Errorf("ZScore needs at least 2 values: got %d", count)
+	}
+
+	mean, err := z.core.UnsafeMean()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving mean: %w", err)
+	}
+
+	variance, err := z.core.UnsafeSum(2)
+	if err != nil {
+		return 0, wrapSentinel(ErrorRetrievingVariance, err)
+	}
+	// exponentially weighted sums are already normalized
+	if z.decay == nil {
+		variance /= z.core.UnsafeWeightSum() - 1
+	}
+
+	variance, err = clampVariance(variance, NegativeVarianceTolerance)
+	if err != nil {
+		return 0, err
+	} else if variance == 0 {
+		return 0, errors.New("standard deviation is zero")
+	}
+
+	return (z.last - mean) / math.Sqrt(variance), nil
+}
+
+// Clear resets the metric.
+func (z *ZScore) Clear() {
+	if z.IsSetCore() {
+		z.core.Lock()
+		defer z.core.Unlock()
+		z.core.UnsafeClear()
+		z.last = 0
+	}
+}
@@ -0,0 +1,214 @@
+package moment
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/K4Mobility/stream"
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewZScore(t *testing.T) {
+	z := NewZScore(3)
+	assert.Equal(t, 3, z.window)
+	assert.Nil(t, z.decay)
+	assert.Nil(t, z.core)
+}
+
+func TestNewGlobalZScore(t *testing.T) {
+	z := NewZScore(0)
+	globalZ := NewGlobalZScore()
+	assert.Equal(t, z, globalZ)
+}
+
+func TestNewEWZScore(t *testing.T) {
+	z := NewEWZScore(0.3)
+	assert.Equal(t, 0, z.window)
+	assert.Equal(t, 0.3, *z.decay)
+}
+
+type ZScorePushSuite struct {
+	suite.Suite
+	z *ZScore
+}
+
+func TestZScorePushSuite(t *testing.T) {
+	suite.Run(t, &ZScorePushSuite{})
+}
+
+func (s *ZScorePushSuite) SetupTest() {
+	s.z = NewZScore(3)
+	err := Init(s.z)
+	s.Require().NoError(err)
+}
+
+func (s *ZScorePushSuite) TestPushSuccess() {
+	err := s.z.Push(3)
+	s.Require().NoError(err)
+	s.Equal(3., s.z.last)
+}
+
+func (s *ZScorePushSuite) TestPushFailOnNullCore() {
+	z := NewZScore(3)
+	err := z.Push(0)
+	testutil.ContainsError(s.T(), err, "Core is not set")
+}
+
+func (s *ZScorePushSuite) TestPushFailOnQueueInsertionFailure() {
+	// dispose the queue to simulate an error when we try to insert into the queue
+	s.z.core.queue.Dispose()
+
+	err := s.z.Push(3)
+	testutil.ContainsError(s.T(), err, "error pushing to core")
+}
+
+func (s *ZScorePushSuite) TestPushSkippedValueIsNotRemembered() {
+	s.z.core.nonFinite = stream.NonFiniteSkip
+
+	err := s.z.Push(3)
+	s.Require().NoError(err)
+	err = s.z.Push(math.NaN())
+	s.Require().NoError(err)
+	s.Equal(3., s.z.last)
+}
+
+func TestZScoreValue(t *testing.T) {
+	t.Run("pass: windowed z-score uses the statistics of the window", func(t *testing.T) {
+		z := NewZScore(3)
+		err := Init(z)
+		require.NoError(t, err)
+
+		for _, x := range []float64{1, 2, 3} {
+			err := z.Push(x)
+			require.NoError(t, err)
+		}
+
+		value, err := z.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 1, value)
+
+		// the window is now {2, 3, 3}, with a mean of 8/3 and a variance of 1/3
+		err = z.Push(3)
+		require.NoError(t, err)
+
+		value, err = z.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 1/math.Sqrt(3), value)
+	})
+
+	t.Run("pass: z-score of an outlier is large", func(t *testing.T) {
+		z := NewGlobalZScore()
+		err := Init(z)
+		require.NoError(t, err)
+
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 1000; i++ {
+			err := z.Push(r.NormFloat64())
+			require.NoError(t, err)
+
+			value, err := z.Value()
+			if i > 0 {
+				require.NoError(t, err)
+				assert.Less(t, math.Abs(value), 5.)
+			}
+		}
+
+		err = z.Push(20)
+		require.NoError(t, err)
+
+		value, err := z.Value()
+		require.NoError(t, err)
+		assert.Greater(t, value, 10.)
+	})
+
+	t.Run("pass: exponentially weighted z-score matches EWMA and EWMStd", func(t *testing.T) {
+		z := NewEWZScore(0.3)
+		ewma := NewEWMA(0.3)
+		ewmstd := NewEWMStd(0.3)
+		for _, metric := range []CoreWrapper{z, ewma, ewmstd} {
+			err := Init(metric)
+			require.NoError(t, err)
+		}
+
+		xs := []float64{4, -1, 7, 2, 0, 3}
+		for _, x := range xs {
+			for _, metric := range []Metric{z, ewma, ewmstd} {
+				err := metric.Push(x)
+				require.NoError(t, err)
+			}
+		}
+
+		mean, err := ewma.Value()
+		require.NoError(t, err)
+		std, err := ewmstd.Value()
+		require.NoError(t, err)
+
+		value, err := z.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, (3-mean)/std, value)
+	})
+
+	t.Run("fail: fewer than 2 values fails", func(t *testing.T) {
+		z := NewGlobalZScore()
+		err := Init(z)
+		require.NoError(t, err)
+
+		_, err = z.Value()
+		assert.EqualError(t, err, "ZScore needs at least 2 values: got 0")
+
+		err = z.Push(1)
+		require.NoError(t, err)
+
+		_, err = z.Value()
+		assert.EqualError(t, err, "ZScore needs at least 2 values: got 1")
+	})
+
+	t.Run("fail: zero standard deviation fails", func(t *testing.T) {
+		z := NewGlobalZScore()
+		err := Init(z)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			err := z.Push(1)
+			require.NoError(t, err)
+		}
+
+		_, err = z.Value()
+		assert.EqualError(t, err, "standard deviation is zero")
+	})
+
+	t.Run("fail: null core fails", func(t *testing.T) {
+		z := NewGlobalZScore()
+		_, err := z.Value()
+		assert.EqualError(t, err, "Core is not set")
+	})
+}
+
+func TestZScoreClear(t *testing.T) {
+	z := NewZScore(3)
+	err := Init(z)
+	require.NoError(t, err)
+
+	for _, x := range []float64{1, 2, 3} {
+		err := z.Push(x)
+		require.NoError(t, err)
+	}
+
+	z.Clear()
+	assert.Equal(t, 0., z.last)
+	assert.Equal(t, 0, z.core.Count())
+	assert.Equal(t, uint64(0), z.core.queue.Len())
+}
+
+func TestZScoreString(t *testing.T) {
+	z := NewZScore(3)
+	assert.Equal(t, "moment.ZScore_{window:3}", z.String())
+
+	z = NewEWZScore(0.3)
+	assert.Equal(t, "moment.ZScore_{decay:0.3}", z.String())
+}
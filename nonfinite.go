@@ -0,0 +1,49 @@
+package stream
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// NonFiniteMode configures how a Core handles non-finite values
+// (i.e. NaN or ±Inf), which would otherwise corrupt its state for good.
+type NonFiniteMode int
+
+// The zero value NonFiniteError is the default mode.
+const (
+	NonFiniteError     NonFiniteMode = iota // reject non-finite values with an error
+	NonFiniteSkip                           // silently ignore non-finite values
+	NonFinitePropagate                      // consume non-finite values like any other
+)
+
+func (m NonFiniteMode) String() string {
+	switch m {
+	case NonFiniteError:
+		return "Error"
+	case NonFiniteSkip:
+		return "Skip"
+	case NonFinitePropagate:
+		return "Propagate"
+	default:
+		return "Unknown"
+	}
+}
+
+// Admit reports whether the values should be consumed under the mode;
+// it returns an error if any value is non-finite and the mode is NonFiniteError.
+func (m NonFiniteMode) Admit(xs ...float64) (bool, error) {
+	if m == NonFinitePropagate {
+		return true, nil
+	}
+
+	for _, x := range xs {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			if m == NonFiniteSkip {
+				return false, nil
+			}
+			return false, errors.Errorf("%f is not a finite value", x)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,53 @@
+package stream
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonFiniteModeAdmit(t *testing.T) {
+	nonFinite := []float64{math.NaN(), math.Inf(1), math.Inf(-1)}
+
+	t.Run("pass: finite values are admitted in every mode", func(t *testing.T) {
+		for _, mode := range []NonFiniteMode{NonFiniteError, NonFiniteSkip, NonFinitePropagate} {
+			ok, err := mode.Admit(1, -2.5, 0)
+			require.NoError(t, err)
+			assert.True(t, ok, mode.String())
+		}
+	})
+
+	t.Run("fail: error mode rejects non-finite values", func(t *testing.T) {
+		for _, x := range nonFinite {
+			ok, err := NonFiniteError.Admit(1, x)
+			assert.EqualError(t, err, fmt.Sprintf("%f is not a finite value", x))
+			assert.False(t, ok)
+		}
+	})
+
+	t.Run("pass: skip mode ignores non-finite values", func(t *testing.T) {
+		for _, x := range nonFinite {
+			ok, err := NonFiniteSkip.Admit(x, 1)
+			require.NoError(t, err)
+			assert.False(t, ok)
+		}
+	})
+
+	t.Run("pass: propagate mode admits non-finite values", func(t *testing.T) {
+		for _, x := range nonFinite {
+			ok, err := NonFinitePropagate.Admit(x)
+			require.NoError(t, err)
+			assert.True(t, ok)
+		}
+	})
+}
+
+func TestNonFiniteModeString(t *testing.T) {
+	assert.Equal(t, "Error", NonFiniteError.String())
+	assert.Equal(t, "Skip", NonFiniteSkip.String())
+	assert.Equal(t, "Propagate", NonFinitePropagate.String())
+	assert.Equal(t, "Unknown", NonFiniteMode(3).String())
+}
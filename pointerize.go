@@ -11,3 +11,6 @@ func IntPtr(v int) *int { return &v }
 
 // FloatPtr returns a pointer to a float.
 func FloatPtr(v float64) *float64 { return &v }
+
+// NonFiniteModePtr returns a pointer to a NonFiniteMode.
+func NonFiniteModePtr(v NonFiniteMode) *NonFiniteMode { return &v }
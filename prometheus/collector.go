@@ -0,0 +1,79 @@
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/K4Mobility/stream"
+	"github.com/K4Mobility/stream/joint"
+	"github.com/K4Mobility/stream/minmax"
+	"github.com/K4Mobility/stream/moment"
+	"github.com/K4Mobility/stream/quantile"
+	"github.com/K4Mobility/stream/signal"
+)
+
+// collector is a prometheus.Collector that reports the value of a metric as a gauge.
+type collector struct {
+	desc   *prom.Desc
+	metric stream.Valuable
+}
+
+// NewCollector instantiates a prometheus.Collector that reports the value of the metric
+// as a gauge with the given name, reading the value anew on each scrape. Any metric with
+// a Value method can be collected, including both moment and joint metrics. A metric that
+// has not seen any values yet is reported as NaN; any other error in retrieving the value
+// is reported to the registry as an invalid metric.
+func NewCollector(name string, m stream.Valuable) prom.Collector {
+	help := fmt.Sprintf("Value of the %s metric", name)
+	if s, ok := m.(fmt.Stringer); ok {
+		help = fmt.Sprintf("Value of the %s metric", s.String())
+	}
+
+	return &collector{
+		desc:   prom.NewDesc(name, help, nil, nil),
+		metric: m,
+	}
+}
+
+// Describe sends the description of the gauge. It satisfies the prometheus.Collector interface.
+func (c *collector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.desc
+}
+
+// Collect sends the current value of the metric as a gauge.
+// It satisfies the prometheus.Collector interface.
+func (c *collector) Collect(ch chan<- prom.Metric) {
+	value, err := c.metric.Value()
+	if err != nil {
+		if !isNoValuesSeen(err) {
+			ch <- prom.NewInvalidMetric(c.desc, err)
+			return
+		}
+		value = math.NaN()
+	}
+
+	ch <- prom.MustNewConstMetric(c.desc, prom.GaugeValue, value)
+}
+
+// noValuesSeen lists the errors returned by the stream packages for metrics that have not
+// seen any values yet.
+var noValuesSeen = []error{
+	moment.ErrorNoValuesSeen,
+	joint.ErrorNoValuesSeen,
+	quantile.ErrorNoValuesSeen,
+	minmax.ErrorNoValuesSeen,
+	signal.ErrorNoValuesSeen,
+}
+
+// isNoValuesSeen returns whether the error is due to the metric not having seen any values yet.
+func isNoValuesSeen(err error) bool {
+	for _, target := range noValuesSeen {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
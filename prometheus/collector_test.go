@@ -0,0 +1,99 @@
+package prometheus
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/K4Mobility/stream"
+	"github.com/K4Mobility/stream/joint"
+	"github.com/K4Mobility/stream/minmax"
+	"github.com/K4Mobility/stream/moment"
+	"github.com/K4Mobility/stream/quantile"
+	"github.com/K4Mobility/stream/signal"
+)
+
+func TestCollector(t *testing.T) {
+	t.Run("pass: gauge reflects the value of a moment metric", func(t *testing.T) {
+		mean := moment.NewMean(3)
+		err := moment.Init(mean)
+		require.NoError(t, err)
+
+		c := NewCollector("stream_mean", mean)
+		reg := prom.NewPedanticRegistry()
+		reg.MustRegister(c)
+
+		for _, x := range []float64{1, 2, 3, 4} {
+			err := mean.Push(x)
+			require.NoError(t, err)
+		}
+
+		expected := `
+# HELP stream_mean Value of the moment.Mean_{window:3} metric
+# TYPE stream_mean gauge
+stream_mean 3
+`
+		err = testutil.GatherAndCompare(reg, strings.NewReader(expected), "stream_mean")
+		assert.NoError(t, err)
+
+		// the value is read anew on each scrape
+		err = mean.Push(8)
+		require.NoError(t, err)
+		assert.Equal(t, 5., testutil.ToFloat64(c))
+	})
+
+	t.Run("pass: gauge reflects the value of a joint metric", func(t *testing.T) {
+		cov := joint.NewGlobalCov()
+		err := joint.Init(cov)
+		require.NoError(t, err)
+
+		for _, x := range []float64{1, 2, 3} {
+			err := cov.Push(x, 2*x)
+			require.NoError(t, err)
+		}
+
+		c := NewCollector("stream_cov", cov)
+		assert.Equal(t, 2., testutil.ToFloat64(c))
+	})
+
+	t.Run("pass: metric without values is reported as NaN", func(t *testing.T) {
+		mean := moment.NewGlobalMean()
+		err := moment.Init(mean)
+		require.NoError(t, err)
+
+		c := NewCollector("stream_mean", mean)
+		assert.True(t, math.IsNaN(testutil.ToFloat64(c)))
+	})
+
+	t.Run("pass: metrics without values from every package are reported as NaN", func(t *testing.T) {
+		cov := joint.NewGlobalCov()
+		err := joint.Init(cov)
+		require.NoError(t, err)
+
+		for name, m := range map[string]stream.Valuable{
+			"stream_cov":            cov,
+			"stream_median":         quantile.NewGlobalHeapMedian(),
+			"stream_min":            minmax.NewGlobalMin(),
+			"stream_crossing_count": signal.NewGlobalCrossingCount(0),
+		} {
+			c := NewCollector(name, m)
+			assert.True(t, math.IsNaN(testutil.ToFloat64(c)), name)
+		}
+	})
+
+	t.Run("fail: other errors are reported as an invalid metric", func(t *testing.T) {
+		// the Core is never set, so retrieving the value fails
+		c := NewCollector("stream_mean", moment.NewGlobalMean())
+		reg := prom.NewPedanticRegistry()
+		reg.MustRegister(c)
+
+		_, err := reg.Gather()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Core is not set")
+	})
+}
@@ -0,0 +1,4 @@
+// Package prometheus exposes the metrics of this library as Prometheus gauges.
+// It is a separate module, so that the Prometheus client is only a dependency
+// of those who use it.
+package prometheus
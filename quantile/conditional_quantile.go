@@ -0,0 +1,87 @@
+package quantile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ConditionalQuantile keeps track of the quantiles of a stream restricted to one of
+// its tails, e.g. the median of the values beyond the 0.9 quantile, using order statistics.
+type ConditionalQuantile struct {
+	threshold float64
+	side      Side
+	quantile  *Quantile
+}
+
+// NewConditionalQuantile instantiates a ConditionalQuantile struct. The threshold
+// is the quantile beyond which values are kept, and the side determines
+// which tail of the distribution the values are kept from.
+func NewConditionalQuantile(threshold float64, side Side, window int, options ...Option) (*ConditionalQuantile, error) {
+	if threshold <= 0 || threshold >= 1 {
+		return nil, errors.Errorf("threshold %f not in (0, 1)", threshold)
+	} else if !side.Valid() {
+		return nil, errors.Errorf("%d is not a valid Side", side)
+	}
+
+	quantile, err := New(window, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Quantile")
+	}
+
+	return &ConditionalQuantile{
+		threshold: threshold,
+		side:      side,
+		quantile:  quantile,
+	}, nil
+}
+
+// NewGlobalConditionalQuantile instantiates a global ConditionalQuantile struct.
+// This is equivalent to calling NewConditionalQuantile(threshold, side, 0, options...).
+func NewGlobalConditionalQuantile(threshold float64, side Side, options ...Option) (*ConditionalQuantile, error) {
+	return NewConditionalQuantile(threshold, side, 0, options...)
+}
+
+// String returns a string representation of the metric.
+func (c *ConditionalQuantile) String() string {
+	name := "quantile.ConditionalQuantile"
+	params := []string{
+		fmt.Sprintf("threshold:%v", c.threshold),
+		fmt.Sprintf("side:%v", c.side),
+		fmt.Sprintf("quantile:%v", c.quantile.String()),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a number for calculating the conditional quantile.
+func (c *ConditionalQuantile) Push(x float64) error {
+	err := c.quantile.Push(x)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing %f to Quantile", x)
+	}
+	return nil
+}
+
+// Value returns the value of the quantile of the values in the tail.
+func (c *ConditionalQuantile) Value(quantile float64) (float64, error) {
+	if quantile <= 0 || quantile >= 1 {
+		return 0, errors.Errorf("quantile %f not in (0, 1)", quantile)
+	}
+
+	c.quantile.RLock()
+	defer c.quantile.RUnlock()
+
+	size := c.quantile.statistic.Size()
+	if size == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	offset, n := c.side.tail(c.threshold, size)
+	return c.quantile.rangeValue(quantile, offset, n), nil
+}
+
+// Clear resets the metric.
+func (c *ConditionalQuantile) Clear() {
+	c.quantile.Clear()
+}
@@ -0,0 +1,204 @@
+package quantile
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestSideTail(t *testing.T) {
+	t.Run("pass: left tail includes boundary index", func(t *testing.T) {
+		offset, n := Left.tail(0.5, 10)
+		assert.Equal(t, 0, offset)
+		assert.Equal(t, 5, n)
+
+		offset, n = Left.tail(0.9, 11)
+		assert.Equal(t, 0, offset)
+		assert.Equal(t, 10, n)
+	})
+
+	t.Run("pass: right tail includes boundary index", func(t *testing.T) {
+		offset, n := Right.tail(0.5, 10)
+		assert.Equal(t, 5, offset)
+		assert.Equal(t, 5, n)
+
+		offset, n = Right.tail(0.9, 11)
+		assert.Equal(t, 9, offset)
+		assert.Equal(t, 2, n)
+	})
+
+	t.Run("pass: tails are nonempty for a single element", func(t *testing.T) {
+		offset, n := Left.tail(0.5, 1)
+		assert.Equal(t, 0, offset)
+		assert.Equal(t, 1, n)
+
+		offset, n = Right.tail(0.5, 1)
+		assert.Equal(t, 0, offset)
+		assert.Equal(t, 1, n)
+	})
+}
+
+func TestNewConditionalQuantile(t *testing.T) {
+	t.Run("pass: valid ConditionalQuantile is valid", func(t *testing.T) {
+		c, err := NewConditionalQuantile(0.9, Right, 3, InterpolationOption(Lower))
+		require.NoError(t, err)
+		assert.Equal(t, 0.9, c.threshold)
+		assert.Equal(t, Right, c.side)
+		assert.Equal(t, 3, c.quantile.window)
+		assert.Equal(t, Lower, c.quantile.interpolation)
+	})
+
+	t.Run("fail: threshold outside (0, 1) is invalid", func(t *testing.T) {
+		_, err := NewConditionalQuantile(0, Right, 3)
+		testutil.ContainsError(t, err, fmt.Sprintf("threshold %f not in (0, 1)", 0.))
+
+		_, err = NewConditionalQuantile(1, Right, 3)
+		testutil.ContainsError(t, err, fmt.Sprintf("threshold %f not in (0, 1)", 1.))
+	})
+
+	t.Run("fail: invalid Side is invalid", func(t *testing.T) {
+		_, err := NewConditionalQuantile(0.9, -1, 3)
+		testutil.ContainsError(t, err, "-1 is not a valid Side")
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewConditionalQuantile(0.9, Right, -1)
+		testutil.ContainsError(t, err, "error creating Quantile")
+	})
+}
+
+func TestNewGlobalConditionalQuantile(t *testing.T) {
+	c, err := NewConditionalQuantile(0.9, Right, 0)
+	require.NoError(t, err)
+
+	globalC, err := NewGlobalConditionalQuantile(0.9, Right)
+	require.NoError(t, err)
+
+	assert.Equal(t, c, globalC)
+}
+
+func TestConditionalQuantileString(t *testing.T) {
+	expectedString := fmt.Sprintf(
+		"quantile.ConditionalQuantile_{threshold:0.9,side:%d,quantile:quantile.Quantile_{window:3,interpolation:%d}}",
+		Right,
+		Linear,
+	)
+	c, err := NewConditionalQuantile(0.9, Right, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedString, c.String())
+}
+
+func TestConditionalQuantilePush(t *testing.T) {
+	t.Run("pass: successfully pushes values", func(t *testing.T) {
+		c, err := NewConditionalQuantile(0.5, Left, 3)
+		require.NoError(t, err)
+		for i := 0.; i < 5; i++ {
+			err := c.Push(i)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, c.quantile.statistic.Size())
+	})
+
+	t.Run("fail: if queue insertion fails, return error", func(t *testing.T) {
+		c, err := NewConditionalQuantile(0.5, Left, 3)
+		require.NoError(t, err)
+
+		// dispose the queue to simulate an error when we try to insert into the queue
+		c.quantile.queue.Dispose()
+		val := 3.
+		err = c.Push(val)
+		testutil.ContainsError(t, err, fmt.Sprintf("error pushing %f to Quantile", val))
+	})
+}
+
+func TestConditionalQuantileValue(t *testing.T) {
+	t.Run("pass: returns quantile of left tail", func(t *testing.T) {
+		c, err := NewGlobalConditionalQuantile(0.5, Left)
+		require.NoError(t, err)
+		for i := 1.; i <= 10; i++ {
+			err = c.Push(i)
+			require.NoError(t, err)
+		}
+
+		// tail is {1, 2, 3, 4, 5}
+		value, err := c.Value(0.5)
+		require.NoError(t, err)
+		testutil.Approx(t, 3, value)
+
+		value, err = c.Value(0.1)
+		require.NoError(t, err)
+		testutil.Approx(t, 1.4, value)
+	})
+
+	t.Run("pass: returns quantile of right tail", func(t *testing.T) {
+		c, err := NewGlobalConditionalQuantile(0.9, Right)
+		require.NoError(t, err)
+		for i := 0.; i <= 10; i++ {
+			err = c.Push(i)
+			require.NoError(t, err)
+		}
+
+		// tail is {9, 10}
+		value, err := c.Value(0.5)
+		require.NoError(t, err)
+		testutil.Approx(t, 9.5, value)
+	})
+
+	t.Run("pass: returns quantile of tail over window", func(t *testing.T) {
+		left, err := NewConditionalQuantile(0.5, Left, 5)
+		require.NoError(t, err)
+		right, err := NewConditionalQuantile(0.5, Right, 5)
+		require.NoError(t, err)
+		for i := 0.; i < 10; i++ {
+			err = left.Push(i)
+			require.NoError(t, err)
+			err = right.Push(i)
+			require.NoError(t, err)
+		}
+
+		// window is {5, 6, 7, 8, 9}
+		value, err := left.Value(0.5)
+		require.NoError(t, err)
+		testutil.Approx(t, 6, value)
+
+		value, err = right.Value(0.5)
+		require.NoError(t, err)
+		testutil.Approx(t, 8, value)
+	})
+
+	t.Run("fail: quantile outside (0, 1) is invalid", func(t *testing.T) {
+		c, err := NewConditionalQuantile(0.5, Left, 3)
+		require.NoError(t, err)
+
+		_, err = c.Value(1)
+		testutil.ContainsError(t, err, fmt.Sprintf("quantile %f not in (0, 1)", 1.))
+	})
+
+	t.Run("fail: if no values seen, return error", func(t *testing.T) {
+		c, err := NewConditionalQuantile(0.5, Left, 3)
+		require.NoError(t, err)
+
+		_, err = c.Value(0.5)
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+}
+
+func TestConditionalQuantileClear(t *testing.T) {
+	c, err := NewConditionalQuantile(0.5, Left, 3)
+	require.NoError(t, err)
+
+	for i := 0.; i < 10; i++ {
+		err = c.Push(i * i)
+		require.NoError(t, err)
+	}
+
+	c.Clear()
+	assert.Equal(t, uint64(0), c.quantile.queue.Len())
+	assert.Equal(t, 0, c.quantile.statistic.Size())
+}
@@ -0,0 +1,112 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ECDF keeps track of the empirical cumulative distribution function of a stream
+// using order statistics, i.e. the fraction of the values that are less than or
+// equal to a given value, which estimates P(X <= v).
+type ECDF struct {
+	quantile *Quantile
+	last     float64
+	mux      sync.RWMutex
+}
+
+// NewECDF instantiates an ECDF struct.
+func NewECDF(window int, options ...Option) (*ECDF, error) {
+	quantile, err := New(window, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Quantile")
+	}
+
+	return &ECDF{quantile: quantile}, nil
+}
+
+// NewGlobalECDF instantiates a global ECDF struct.
+// This is equivalent to calling NewECDF(0, options...).
+func NewGlobalECDF(options ...Option) (*ECDF, error) {
+	return NewECDF(0, options...)
+}
+
+// String returns a string representation of the metric.
+func (e *ECDF) String() string {
+	name := "quantile.ECDF"
+	quantile := fmt.Sprintf("quantile:%v", e.quantile.String())
+	return fmt.Sprintf("%s_{%s}", name, quantile)
+}
+
+// Push adds a number for calculating the empirical CDF; if a window is set,
+// the oldest value is removed once the window is full.
+func (e *ECDF) Push(x float64) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	err := e.quantile.Push(x)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing %f to Quantile", x)
+	}
+
+	e.last = x
+	return nil
+}
+
+// Value returns the value of the empirical CDF at the most recently pushed value,
+// i.e. the fraction of the values that are less than or equal to it.
+func (e *ECDF) Value() (float64, error) {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	e.quantile.RLock()
+	defer e.quantile.RUnlock()
+
+	return e.quantile.percentileRank(e.last)
+}
+
+// CDF returns the value of the empirical CDF at v, i.e. RankInclusive(v)/Size()
+// for the underlying order statistic.
+func (e *ECDF) CDF(v float64) (float64, error) {
+	if math.IsNaN(v) {
+		return 0, errors.New("cannot compute the CDF at NaN")
+	}
+
+	e.quantile.RLock()
+	defer e.quantile.RUnlock()
+
+	return e.quantile.percentileRank(v)
+}
+
+// CDFBatch returns the values of the empirical CDF at each of vs; the values are
+// computed from the same snapshot of the stream, even if values are pushed concurrently.
+func (e *ECDF) CDFBatch(vs []float64) ([]float64, error) {
+	for i, v := range vs {
+		if math.IsNaN(v) {
+			return nil, errors.Errorf("cannot compute the CDF at NaN (index %d)", i)
+		}
+	}
+
+	e.quantile.RLock()
+	defer e.quantile.RUnlock()
+
+	cdfs := make([]float64, len(vs))
+	for i, v := range vs {
+		cdf, err := e.quantile.percentileRank(v)
+		if err != nil {
+			return nil, err
+		}
+		cdfs[i] = cdf
+	}
+	return cdfs, nil
+}
+
+// Clear resets the metric.
+func (e *ECDF) Clear() {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.quantile.Clear()
+	e.last = 0
+}
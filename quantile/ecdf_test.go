@@ -0,0 +1,167 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewECDF(t *testing.T) {
+	t.Run("pass: valid window is valid", func(t *testing.T) {
+		e, err := NewECDF(5, ImplOption(SkipList))
+		require.NoError(t, err)
+		assert.Equal(t, 5, e.quantile.window)
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewECDF(-1)
+		testutil.ContainsError(t, err, "error creating Quantile")
+	})
+}
+
+func TestNewGlobalECDF(t *testing.T) {
+	e, err := NewECDF(0)
+	require.NoError(t, err)
+
+	globalE, err := NewGlobalECDF()
+	require.NoError(t, err)
+
+	assert.Equal(t, e, globalE)
+}
+
+func TestECDFString(t *testing.T) {
+	expectedString := fmt.Sprintf(
+		"quantile.ECDF_{quantile:quantile.Quantile_{window:3,interpolation:%d}}",
+		Linear,
+	)
+	e, err := NewECDF(3)
+	require.NoError(t, err)
+	assert.Equal(t, expectedString, e.String())
+}
+
+func TestECDFCDF(t *testing.T) {
+	t.Run("pass: is approximately linear on a uniform dataset", func(t *testing.T) {
+		for _, impl := range []Impl{AVL, RedBlack, SkipList} {
+			e, err := NewGlobalECDF(ImplOption(impl))
+			require.NoError(t, err)
+
+			// 0, 0.001, ..., 0.999 in a scrambled order
+			for i := 0; i < 1000; i++ {
+				err := e.Push(float64((i*337)%1000) / 1000)
+				require.NoError(t, err)
+			}
+
+			vs := []float64{-1, 0.1, 0.25, 0.5, 0.75, 0.9, 2}
+			cdfs, err := e.CDFBatch(vs)
+			require.NoError(t, err)
+			for i, v := range vs {
+				expected := math.Min(math.Max(v, 0), 1)
+				assert.InDelta(t, expected, cdfs[i], 0.002, "CDF(%v) for %v", v, impl)
+
+				cdf, err := e.CDF(v)
+				require.NoError(t, err)
+				assert.Equal(t, cdfs[i], cdf)
+			}
+		}
+	})
+
+	t.Run("pass: counts duplicates inclusively", func(t *testing.T) {
+		e, err := NewGlobalECDF()
+		require.NoError(t, err)
+		for _, x := range []float64{1, 2, 2, 2, 3} {
+			err := e.Push(x)
+			require.NoError(t, err)
+		}
+
+		cdfs, err := e.CDFBatch([]float64{1.5, 2, 3})
+		require.NoError(t, err)
+		assert.Equal(t, []float64{0.2, 0.8, 1}, cdfs)
+	})
+
+	t.Run("pass: slides with the window", func(t *testing.T) {
+		e, err := NewECDF(3)
+		require.NoError(t, err)
+		for _, x := range []float64{10, 20, 1, 2, 3} {
+			err := e.Push(x)
+			require.NoError(t, err)
+		}
+
+		cdf, err := e.CDF(3)
+		require.NoError(t, err)
+		assert.Equal(t, 1., cdf)
+
+		cdf, err = e.CDF(1.5)
+		require.NoError(t, err)
+		testutil.Approx(t, 1./3, cdf)
+	})
+
+	t.Run("fail: NaN is invalid", func(t *testing.T) {
+		e, err := NewGlobalECDF()
+		require.NoError(t, err)
+		err = e.Push(1)
+		require.NoError(t, err)
+
+		_, err = e.CDF(math.NaN())
+		assert.EqualError(t, err, "cannot compute the CDF at NaN")
+
+		_, err = e.CDFBatch([]float64{1, math.NaN()})
+		assert.EqualError(t, err, "cannot compute the CDF at NaN (index 1)")
+	})
+
+	t.Run("fail: no values seen is an error", func(t *testing.T) {
+		e, err := NewGlobalECDF()
+		require.NoError(t, err)
+
+		_, err = e.CDF(1)
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+
+		_, err = e.CDFBatch([]float64{1})
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+
+		_, err = e.Value()
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+	})
+}
+
+func TestECDFValue(t *testing.T) {
+	e, err := NewECDF(4)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		x        float64
+		expected float64
+	}{
+		{5, 1},
+		{1, 0.5},
+		{3, 2. / 3},
+		{4, 0.75},
+		// evicts 5
+		{0, 0.25},
+	} {
+		err := e.Push(tt.x)
+		require.NoError(t, err)
+
+		value, err := e.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, tt.expected, value)
+	}
+}
+
+func TestECDFClear(t *testing.T) {
+	e, err := NewECDF(3)
+	require.NoError(t, err)
+	for _, x := range []float64{1, 2, 3, 4} {
+		err := e.Push(x)
+		require.NoError(t, err)
+	}
+
+	e.Clear()
+	assert.Equal(t, 0, e.quantile.statistic.Size())
+	assert.Equal(t, uint64(0), e.quantile.queue.Len())
+	assert.Equal(t, 0., e.last)
+}
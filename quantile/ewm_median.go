@@ -0,0 +1,88 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// EWMMedian keeps track of an exponentially weighted moving median of a stream,
+// so that recent values weigh more than older ones. It is an approximation: rather
+// than storing values, it keeps a single estimate and nudges it towards each new
+// value by a step of decay * s, where s is the exponentially weighted mean
+// absolute deviation from the estimate.
+//
+// Since a step up is as large as a step down, the estimate settles where new values
+// are equally likely to lie above or below it, i.e. at the median of the recent
+// distribution. In steady state it fluctuates around that median by roughly
+// decay * s; after a shift in the distribution, s grows with the deviation, so the
+// estimate catches up within a small multiple of 1 / decay values.
+type EWMMedian struct {
+	decay  float64
+	median float64
+	scale  float64
+	count  int
+	mux    sync.Mutex
+}
+
+// NewEWMMedian instantiates an EWMMedian struct.
+func NewEWMMedian(decay float64) (*EWMMedian, error) {
+	if !(decay > 0 && decay < 1) {
+		return nil, errors.Errorf("decay %f not in (0, 1)", decay)
+	}
+	return &EWMMedian{decay: decay}, nil
+}
+
+// String returns a string representation of the metric.
+func (m *EWMMedian) String() string {
+	name := "quantile.EWMMedian"
+	decay := fmt.Sprintf("decay:%v", m.decay)
+	return fmt.Sprintf("%s_{%s}", name, decay)
+}
+
+// Push adds a number for calculating the exponentially weighted moving median.
+func (m *EWMMedian) Push(x float64) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.count == 0 {
+		m.median = x
+		m.count++
+		return nil
+	}
+
+	dev := x - m.median
+	m.scale = (1-m.decay)*m.scale + m.decay*math.Abs(dev)
+	switch {
+	case dev > 0:
+		m.median += m.decay * m.scale
+	case dev < 0:
+		m.median -= m.decay * m.scale
+	}
+	m.count++
+
+	return nil
+}
+
+// Value returns the value of the exponentially weighted moving median.
+func (m *EWMMedian) Value() (float64, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.count == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+	return m.median, nil
+}
+
+// Clear resets the metric.
+func (m *EWMMedian) Clear() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.median = 0
+	m.scale = 0
+	m.count = 0
+}
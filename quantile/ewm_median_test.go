@@ -0,0 +1,114 @@
+package quantile
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewEWMMedian(t *testing.T) {
+	t.Run("pass: decay in (0, 1) is valid", func(t *testing.T) {
+		m, err := NewEWMMedian(0.3)
+		require.NoError(t, err)
+		assert.Equal(t, 0.3, m.decay)
+	})
+
+	t.Run("fail: decay outside (0, 1) is invalid", func(t *testing.T) {
+		for _, decay := range []float64{-0.1, 0, 1, 1.5, math.NaN()} {
+			_, err := NewEWMMedian(decay)
+			testutil.ContainsError(t, err, "not in (0, 1)")
+		}
+	})
+}
+
+func TestEWMMedianString(t *testing.T) {
+	m, err := NewEWMMedian(0.3)
+	require.NoError(t, err)
+	assert.Equal(t, "quantile.EWMMedian_{decay:0.3}", m.String())
+}
+
+func TestEWMMedianValue(t *testing.T) {
+	t.Run("pass: steps towards new values", func(t *testing.T) {
+		m, err := NewEWMMedian(0.5)
+		require.NoError(t, err)
+
+		for _, tt := range []struct {
+			x        float64
+			expected float64
+		}{
+			// the first value seeds the estimate
+			{4, 4},
+			// scale = 0.5 * 4 = 2, so the estimate rises by 0.5 * 2
+			{8, 5},
+			// scale = 0.5 * 2 + 0.5 * 5 = 3.5, so the estimate falls by 0.5 * 3.5
+			{0, 3.25},
+			// values equal to the estimate only shrink the scale
+			{3.25, 3.25},
+		} {
+			err := m.Push(tt.x)
+			require.NoError(t, err)
+
+			value, err := m.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, tt.expected, value)
+		}
+	})
+
+	t.Run("pass: tracks a step change faster than a large window", func(t *testing.T) {
+		ewm, err := NewEWMMedian(0.05)
+		require.NoError(t, err)
+		windowed, err := NewHeapMedian(1000)
+		require.NoError(t, err)
+
+		rng := rand.New(rand.NewSource(1))
+		push := func(mean float64, n int) {
+			for i := 0; i < n; i++ {
+				x := mean + rng.NormFloat64()
+				require.NoError(t, ewm.Push(x))
+				require.NoError(t, windowed.Push(x))
+			}
+		}
+
+		push(0, 1000)
+		value, err := ewm.Value()
+		require.NoError(t, err)
+		assert.InDelta(t, 0, value, 0.5)
+
+		push(10, 200)
+		value, err = ewm.Value()
+		require.NoError(t, err)
+		assert.InDelta(t, 10, value, 0.5)
+
+		windowedValue, err := windowed.Value()
+		require.NoError(t, err)
+		assert.Less(t, windowedValue, 5.)
+	})
+
+	t.Run("fail: no values seen is an error", func(t *testing.T) {
+		m, err := NewEWMMedian(0.3)
+		require.NoError(t, err)
+
+		_, err = m.Value()
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+	})
+}
+
+func TestEWMMedianClear(t *testing.T) {
+	m, err := NewEWMMedian(0.3)
+	require.NoError(t, err)
+	require.NoError(t, m.Push(1))
+	require.NoError(t, m.Push(2))
+
+	m.Clear()
+	assert.Equal(t, 0., m.median)
+	assert.Equal(t, 0., m.scale)
+	assert.Equal(t, 0, m.count)
+
+	_, err = m.Value()
+	assert.ErrorIs(t, err, ErrorNoValuesSeen)
+}
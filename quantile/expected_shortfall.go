@@ -0,0 +1,85 @@
+package quantile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ExpectedShortfall keeps track of the expected shortfall (also known as the
+// conditional value at risk) of a stream using order statistics, i.e. the mean of
+// the values in the lower tail of the stream that lies at or below the alpha quantile.
+// Returns are expected to be pushed, so that the lower tail represents the worst outcomes.
+type ExpectedShortfall struct {
+	alpha    float64
+	quantile *Quantile
+}
+
+// NewExpectedShortfall instantiates an ExpectedShortfall struct.
+func NewExpectedShortfall(alpha float64, window int, impl Impl) (*ExpectedShortfall, error) {
+	if alpha <= 0 || alpha >= 1 {
+		return nil, errors.Errorf("alpha %f not in (0, 1)", alpha)
+	}
+
+	quantile, err := New(window, ImplOption(impl))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Quantile")
+	}
+
+	return &ExpectedShortfall{
+		alpha:    alpha,
+		quantile: quantile,
+	}, nil
+}
+
+// NewGlobalExpectedShortfall instantiates a global ExpectedShortfall struct.
+// This is equivalent to calling NewExpectedShortfall(alpha, 0, impl).
+func NewGlobalExpectedShortfall(alpha float64, impl Impl) (*ExpectedShortfall, error) {
+	return NewExpectedShortfall(alpha, 0, impl)
+}
+
+// String returns a string representation of the metric.
+func (e *ExpectedShortfall) String() string {
+	name := "quantile.ExpectedShortfall"
+	params := []string{
+		fmt.Sprintf("alpha:%v", e.alpha),
+		fmt.Sprintf("quantile:%v", e.quantile.String()),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a number for calculating the expected shortfall.
+func (e *ExpectedShortfall) Push(x float64) error {
+	err := e.quantile.Push(x)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing %f to Quantile", x)
+	}
+	return nil
+}
+
+// Value returns the value of the expected shortfall. Let k be the number
+// of values in the lower tail and n be the number of values tracked; then
+// this takes O(k log n) time.
+func (e *ExpectedShortfall) Value() (float64, error) {
+	e.quantile.RLock()
+	defer e.quantile.RUnlock()
+
+	size := e.quantile.statistic.Size()
+	if size == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	_, n := Left.tail(e.alpha, size)
+	sum := 0.
+	for i := 0; i < n; i++ {
+		sum += e.quantile.statistic.Select(i).Value()
+	}
+
+	return sum / float64(n), nil
+}
+
+// Clear resets the metric.
+func (e *ExpectedShortfall) Clear() {
+	e.quantile.Clear()
+}
@@ -0,0 +1,140 @@
+package quantile
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/K4Mobility/stream/quantile/skiplist"
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewExpectedShortfall(t *testing.T) {
+	t.Run("pass: valid ExpectedShortfall is valid", func(t *testing.T) {
+		e, err := NewExpectedShortfall(0.05, 3, SkipList)
+		require.NoError(t, err)
+		assert.Equal(t, 0.05, e.alpha)
+		assert.Equal(t, 3, e.quantile.window)
+		_, ok := e.quantile.statistic.(*skiplist.SkipList)
+		assert.True(t, ok)
+	})
+
+	t.Run("fail: alpha outside (0, 1) is invalid", func(t *testing.T) {
+		_, err := NewExpectedShortfall(0, 3, AVL)
+		testutil.ContainsError(t, err, fmt.Sprintf("alpha %f not in (0, 1)", 0.))
+
+		_, err = NewExpectedShortfall(1, 3, AVL)
+		testutil.ContainsError(t, err, fmt.Sprintf("alpha %f not in (0, 1)", 1.))
+	})
+
+	t.Run("fail: invalid Impl is invalid", func(t *testing.T) {
+		_, err := NewExpectedShortfall(0.05, 3, -1)
+		testutil.ContainsError(t, err, "error creating Quantile")
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewExpectedShortfall(0.05, -1, AVL)
+		testutil.ContainsError(t, err, "error creating Quantile")
+	})
+}
+
+func TestNewGlobalExpectedShortfall(t *testing.T) {
+	e, err := NewExpectedShortfall(0.05, 0, AVL)
+	require.NoError(t, err)
+
+	globalE, err := NewGlobalExpectedShortfall(0.05, AVL)
+	require.NoError(t, err)
+
+	assert.Equal(t, e, globalE)
+}
+
+func TestExpectedShortfallString(t *testing.T) {
+	expectedString := fmt.Sprintf(
+		"quantile.ExpectedShortfall_{alpha:0.05,quantile:quantile.Quantile_{window:3,interpolation:%d}}",
+		Linear,
+	)
+	e, err := NewExpectedShortfall(0.05, 3, AVL)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedString, e.String())
+}
+
+func TestExpectedShortfallPush(t *testing.T) {
+	t.Run("pass: successfully pushes values", func(t *testing.T) {
+		e, err := NewExpectedShortfall(0.5, 3, AVL)
+		require.NoError(t, err)
+		for i := 0.; i < 5; i++ {
+			err := e.Push(i)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, e.quantile.statistic.Size())
+	})
+
+	t.Run("fail: if queue insertion fails, return error", func(t *testing.T) {
+		e, err := NewExpectedShortfall(0.5, 3, AVL)
+		require.NoError(t, err)
+
+		// dispose the queue to simulate an error when we try to insert into the queue
+		e.quantile.queue.Dispose()
+		val := 3.
+		err = e.Push(val)
+		testutil.ContainsError(t, err, fmt.Sprintf("error pushing %f to Quantile", val))
+	})
+}
+
+func TestExpectedShortfallValue(t *testing.T) {
+	for _, impl := range []Impl{AVL, RedBlack, SkipList} {
+		t.Run(fmt.Sprintf("pass: returns mean of lower tail for Impl %d", impl), func(t *testing.T) {
+			e, err := NewGlobalExpectedShortfall(0.2, impl)
+			require.NoError(t, err)
+			for _, x := range []float64{5, -3, 8, 1, -7, 2, 9, 4, 6, 3} {
+				err = e.Push(x)
+				require.NoError(t, err)
+			}
+
+			// tail is {-7, -3}
+			value, err := e.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, -5, value)
+		})
+	}
+
+	t.Run("pass: returns mean of lower tail over window", func(t *testing.T) {
+		e, err := NewExpectedShortfall(0.5, 5, AVL)
+		require.NoError(t, err)
+		for i := 0.; i < 10; i++ {
+			err = e.Push(i)
+			require.NoError(t, err)
+		}
+
+		// window is {5, 6, 7, 8, 9}, tail is {5, 6, 7}
+		value, err := e.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 6, value)
+	})
+
+	t.Run("fail: if no values seen, return error", func(t *testing.T) {
+		e, err := NewExpectedShortfall(0.5, 3, AVL)
+		require.NoError(t, err)
+
+		_, err = e.Value()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+}
+
+func TestExpectedShortfallClear(t *testing.T) {
+	e, err := NewExpectedShortfall(0.5, 3, AVL)
+	require.NoError(t, err)
+
+	for i := 0.; i < 10; i++ {
+		err = e.Push(i * i)
+		require.NoError(t, err)
+	}
+
+	e.Clear()
+	assert.Equal(t, uint64(0), e.quantile.queue.Len())
+	assert.Equal(t, 0, e.quantile.statistic.Size())
+}
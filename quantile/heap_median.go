@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/Workiva/go-datastructures/queue"
 	"github.com/pkg/errors"
 
 	"github.com/K4Mobility/stream/quantile/heap"
+	"github.com/K4Mobility/stream/util/ring"
 )
 
 // HeapMedian keeps track of the median of an entire stream using heaps.
@@ -16,7 +16,7 @@ type HeapMedian struct {
 	window   int
 	lowHeap  *heap.Heap
 	highHeap *heap.Heap
-	queue    *queue.RingBuffer
+	queue    ring.Queue[*heap.Item]
 	mux      sync.Mutex
 }
 
@@ -38,7 +38,7 @@ func NewHeapMedian(window int) (*HeapMedian, error) {
 		window:   window,
 		lowHeap:  heap.New("low", []float64{}, fmax),
 		highHeap: heap.New("high", []float64{}, fmin),
-		queue:    queue.NewRingBuffer(uint64(window)),
+		queue:    ring.New[*heap.Item](uint64(window)),
 	}, nil
 }
 
@@ -49,7 +49,7 @@ func NewGlobalHeapMedian() *HeapMedian {
 		window:   0,
 		lowHeap:  heap.New("low", []float64{}, fmax),
 		highHeap: heap.New("high", []float64{}, fmin),
-		queue:    queue.NewRingBuffer(uint64(0)),
+		queue:    ring.New[*heap.Item](uint64(0)),
 	}
 }
 
@@ -65,42 +65,34 @@ func (m *HeapMedian) Push(x float64) error {
 	m.mux.Lock()
 	defer m.mux.Unlock()
 
-	var item *heap.Item
-	// if queue is full, we need to remove old item
+	item := &heap.Item{}
+	// if queue is full, we need to remove old item; it is removed from
+	// the heap it is currently in (which is tracked by its HeapID, since
+	// rebalancing can move it between heaps) and reused for the new value
 	if m.window != 0 && m.queue.Len() == uint64(m.window) {
 		tail, err := m.queue.Get()
 		if err != nil {
 			return errors.Wrap(err, "error popping item from queue")
 		}
 
-		item = tail.(*heap.Item)
-		low := item.HeapID == m.lowHeap.ID
-		switch {
-		// if new/old items are in the same heap, just replace the old item
-		case low && x <= m.lowHeap.Peek():
-			m.lowHeap.Update(item, x)
-		case !low && x > m.lowHeap.Peek():
-			m.highHeap.Update(item, x)
-		// otherwise, remove old item and push new value to other heap
-		case low && x > m.lowHeap.Peek():
+		item = tail
+		if item.HeapID == m.lowHeap.ID {
 			m.lowHeap.Remove(item)
-			item.Val = x
-			heapops.Push(m.highHeap, item)
-		default:
+		} else {
 			m.highHeap.Remove(item)
-			item.Val = x
-			heapops.Push(m.lowHeap, item)
 		}
-		item = m.rebalance(item)
+	}
+
+	// the low heap can be empty after an eviction even if the high heap
+	// is not, so the new value is checked against the tops of both heaps
+	item.Val = x
+	if (m.lowHeap.Len() > 0 && x > m.lowHeap.Peek()) ||
+		(m.highHeap.Len() > 0 && x > m.highHeap.Peek()) {
+		heapops.Push(m.highHeap, item)
 	} else {
-		item = &heap.Item{Val: x}
-		if m.lowHeap.Len() == 0 || x <= m.lowHeap.Peek() {
-			heapops.Push(m.lowHeap, item)
-		} else {
-			heapops.Push(m.highHeap, item)
-		}
-		item = m.rebalance(item)
+		heapops.Push(m.lowHeap, item)
 	}
+	m.rebalance()
 
 	if m.window != 0 {
 		err := m.queue.Put(item)
@@ -112,15 +104,13 @@ func (m *HeapMedian) Push(x float64) error {
 	return nil
 }
 
-func (m *HeapMedian) rebalance(item *heap.Item) *heap.Item {
+// rebalance moves an item between the heaps if their sizes differ by more than one.
+func (m *HeapMedian) rebalance() {
 	if m.lowHeap.Len()+1 < m.highHeap.Len() {
-		item = heapops.Pop(m.highHeap).(*heap.Item)
-		heapops.Push(m.lowHeap, item)
+		heapops.Push(m.lowHeap, heapops.Pop(m.highHeap))
 	} else if m.lowHeap.Len() > m.highHeap.Len()+1 {
-		item = heapops.Pop(m.lowHeap).(*heap.Item)
-		heapops.Push(m.highHeap, item)
+		heapops.Push(m.highHeap, heapops.Pop(m.lowHeap))
 	}
-	return item
 }
 
 // Value returns the value of the median.
@@ -129,7 +119,7 @@ func (m *HeapMedian) Value() (float64, error) {
 	defer m.mux.Unlock()
 
 	if m.lowHeap.Len()+m.highHeap.Len() == 0 {
-		return 0, errors.New("no values seen yet")
+		return 0, ErrorNoValuesSeen
 	}
 
 	// return top of the larger of the heaps
@@ -145,12 +135,19 @@ func (m *HeapMedian) Value() (float64, error) {
 	}
 }
 
+// Count returns the number of values currently tracked, i.e. the number
+// of values in the window if one is set, or else the number of values seen.
+func (m *HeapMedian) Count() int {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.lowHeap.Len() + m.highHeap.Len()
+}
+
 // Clear resets the metric.
 func (m *HeapMedian) Clear() {
 	m.mux.Lock()
 	defer m.mux.Unlock()
-	m.queue.Dispose()
-	m.queue = queue.NewRingBuffer(uint64(m.window))
+	m.queue.Reset()
 	m.lowHeap = heap.New("low", []float64{}, fmax)
 	m.highHeap = heap.New("high", []float64{}, fmin)
 }
@@ -183,6 +183,37 @@ func TestHeapMedianValue(t *testing.T) {
 	})
 }
 
+func TestHeapMedianCount(t *testing.T) {
+	t.Run("pass: count is capped by the window", func(t *testing.T) {
+		median, err := NewHeapMedian(3)
+		require.NoError(t, err)
+		assert.Equal(t, 0, median.Count())
+
+		for i := 0.; i < 5; i++ {
+			err = median.Push(i)
+			require.NoError(t, err)
+
+			expected := int(i) + 1
+			if expected > 3 {
+				expected = 3
+			}
+			assert.Equal(t, expected, median.Count())
+		}
+	})
+
+	t.Run("pass: global count tracks all pushes", func(t *testing.T) {
+		median := NewGlobalHeapMedian()
+		for i := 0.; i < 5; i++ {
+			err := median.Push(i)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 5, median.Count())
+
+		median.Clear()
+		assert.Equal(t, 0, median.Count())
+	})
+}
+
 func TestHeapMedianClear(t *testing.T) {
 	median, err := NewHeapMedian(10)
 	require.NoError(t, err)
@@ -196,6 +227,50 @@ func TestHeapMedianClear(t *testing.T) {
 	assert.Equal(t, 0, median.lowHeap.Len())
 	assert.Equal(t, 0, median.highHeap.Len())
 	assert.Equal(t, uint64(0), median.queue.Len())
+
+	_, err = median.Value()
+	assert.EqualError(t, err, "no values seen yet")
+
+	// the cleared metric should behave as if fresh
+	for i := 20.; i < 25; i++ {
+		err = median.Push(i)
+		require.NoError(t, err)
+	}
+
+	value, err := median.Value()
+	require.NoError(t, err)
+	testutil.Approx(t, 22., value)
+}
+
+func TestHeapMedianWindowStress(t *testing.T) {
+	for _, window := range []int{1, 2, 3, 10, 33} {
+		t.Run(fmt.Sprintf("pass: window %d matches brute force", window), func(t *testing.T) {
+			median, err := NewHeapMedian(window)
+			require.NoError(t, err)
+
+			// rounding produces plenty of ties, which exercise
+			// the comparisons against the top of the low heap
+			r := rand.New(rand.NewSource(int64(window)))
+			xs := make([]float64, 2000)
+			for i := range xs {
+				xs[i] = math.Round(20*r.Float64() - 10)
+			}
+
+			for i, x := range xs {
+				err := median.Push(x)
+				require.NoError(t, err)
+
+				start := i + 1 - window
+				if start < 0 {
+					start = 0
+				}
+				val, err := median.Value()
+				require.NoError(t, err)
+				testutil.Approx(t, linearQuantile(xs[start:i+1], 0.5), val, "index %d", i)
+				require.Equal(t, i+1-start, median.Count(), "index %d", i)
+			}
+		})
+	}
 }
 
 func BenchmarkHeapMedianPush(b *testing.B) {
@@ -0,0 +1,150 @@
+package quantile
+
+import (
+	heapops "container/heap"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream/quantile/heap"
+	"github.com/K4Mobility/stream/util/ring"
+)
+
+// HeapQuantile keeps track of a fixed quantile of a stream using heaps. It generalizes
+// HeapMedian: if φ is the quantile and n is the number of values tracked, the low heap
+// holds the smallest floor(φ * (n - 1)) + 1 values, so that the φ-quantile lies between
+// the tops of the two heaps. The value is linearly interpolated between them, matching
+// Quantile with Linear interpolation.
+type HeapQuantile struct {
+	phi      float64
+	window   int
+	lowHeap  *heap.Heap
+	highHeap *heap.Heap
+	queue    ring.Queue[*heap.Item]
+	mux      sync.Mutex
+}
+
+// NewHeapQuantile instantiates a HeapQuantile struct for the φ-quantile.
+func NewHeapQuantile(phi float64, window int) (*HeapQuantile, error) {
+	if phi <= 0 || phi >= 1 {
+		return nil, errors.Errorf("quantile %f not in (0, 1)", phi)
+	} else if window < 0 {
+		return nil, errors.Errorf("%d is a negative window", window)
+	}
+
+	return &HeapQuantile{
+		phi:      phi,
+		window:   window,
+		lowHeap:  heap.New("low", []float64{}, fmax),
+		highHeap: heap.New("high", []float64{}, fmin),
+		queue:    ring.New[*heap.Item](uint64(window)),
+	}, nil
+}
+
+// NewGlobalHeapQuantile instantiates a global HeapQuantile struct.
+// This is equivalent to calling NewHeapQuantile(phi, 0).
+func NewGlobalHeapQuantile(phi float64) (*HeapQuantile, error) {
+	return NewHeapQuantile(phi, 0)
+}
+
+// String returns a string representation of the metric.
+func (q *HeapQuantile) String() string {
+	name := "quantile.HeapQuantile"
+	params := []string{
+		fmt.Sprintf("quantile:%v", q.phi),
+		fmt.Sprintf("window:%v", q.window),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a number for calculating the quantile.
+func (q *HeapQuantile) Push(x float64) error {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	// if queue is full, we need to remove old item
+	if q.window != 0 && q.queue.Len() == uint64(q.window) {
+		tail, err := q.queue.Get()
+		if err != nil {
+			return errors.Wrap(err, "error popping item from queue")
+		}
+
+		item := tail
+		if item.HeapID == q.lowHeap.ID {
+			q.lowHeap.Remove(item)
+		} else {
+			q.highHeap.Remove(item)
+		}
+	}
+
+	item := &heap.Item{Val: x}
+	if q.highHeap.Len() > 0 && x > q.highHeap.Peek() {
+		heapops.Push(q.highHeap, item)
+	} else {
+		heapops.Push(q.lowHeap, item)
+	}
+	q.rebalance()
+
+	if q.window != 0 {
+		err := q.queue.Put(item)
+		if err != nil {
+			return errors.Wrapf(err, "error pushing %f to queue", x)
+		}
+	}
+
+	return nil
+}
+
+// rebalance moves items between the heaps until the low heap holds the
+// smallest floor(φ * (n - 1)) + 1 values.
+func (q *HeapQuantile) rebalance() {
+	n := q.lowHeap.Len() + q.highHeap.Len()
+	target := int(math.Floor(q.phi*float64(n-1))) + 1
+	for q.lowHeap.Len() > target {
+		heapops.Push(q.highHeap, heapops.Pop(q.lowHeap))
+	}
+	for q.lowHeap.Len() < target {
+		heapops.Push(q.lowHeap, heapops.Pop(q.highHeap))
+	}
+}
+
+// Value returns the value of the quantile.
+func (q *HeapQuantile) Value() (float64, error) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	n := q.lowHeap.Len() + q.highHeap.Len()
+	if n == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	idx := q.phi * float64(n-1)
+	delta := idx - math.Floor(idx)
+	low := q.lowHeap.Peek()
+	if delta == 0 {
+		return low, nil
+	}
+
+	high := q.highHeap.Peek()
+	return (1-delta)*low + delta*high, nil
+}
+
+// Count returns the number of values currently tracked, i.e. the number
+// of values in the window if one is set, or else the number of values seen.
+func (q *HeapQuantile) Count() int {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	return q.lowHeap.Len() + q.highHeap.Len()
+}
+
+// Clear resets the metric.
+func (q *HeapQuantile) Clear() {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	q.queue.Reset()
+	q.lowHeap = heap.New("low", []float64{}, fmax)
+	q.highHeap = heap.New("high", []float64{}, fmin)
+}
@@ -0,0 +1,141 @@
+package quantile
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+// linearQuantile returns the φ-quantile of the values using linear interpolation.
+func linearQuantile(vals []float64, phi float64) float64 {
+	sorted := make([]float64, len(vals))
+	copy(sorted, vals)
+	sort.Float64s(sorted)
+
+	idx := phi * float64(len(sorted)-1)
+	i := int(math.Floor(idx))
+	delta := idx - float64(i)
+	if delta == 0 {
+		return sorted[i]
+	}
+	return (1-delta)*sorted[i] + delta*sorted[i+1]
+}
+
+func TestNewHeapQuantile(t *testing.T) {
+	t.Run("pass: valid HeapQuantile is valid", func(t *testing.T) {
+		q, err := NewHeapQuantile(0.9, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 0.9, q.phi)
+		assert.Equal(t, 3, q.window)
+	})
+
+	t.Run("fail: quantile must be in (0, 1)", func(t *testing.T) {
+		_, err := NewHeapQuantile(0, 3)
+		testutil.ContainsError(t, err, "quantile 0.000000 not in (0, 1)")
+
+		_, err = NewHeapQuantile(1, 3)
+		testutil.ContainsError(t, err, "quantile 1.000000 not in (0, 1)")
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewHeapQuantile(0.9, -1)
+		testutil.ContainsError(t, err, "-1 is a negative window")
+	})
+}
+
+func TestNewGlobalHeapQuantile(t *testing.T) {
+	q, err := NewHeapQuantile(0.9, 0)
+	require.NoError(t, err)
+	globalQ, err := NewGlobalHeapQuantile(0.9)
+	require.NoError(t, err)
+	globalQ.lowHeap = q.lowHeap
+	globalQ.highHeap = q.highHeap
+	assert.Equal(t, q, globalQ)
+}
+
+func TestHeapQuantileString(t *testing.T) {
+	q, err := NewHeapQuantile(0.9, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "quantile.HeapQuantile_{quantile:0.9,window:3}", q.String())
+}
+
+func TestHeapQuantileValue(t *testing.T) {
+	for _, phi := range []float64{0.1, 0.5, 0.9, 0.99} {
+		phi := phi
+
+		t.Run("pass: global quantile matches sorted data", func(t *testing.T) {
+			q, err := NewGlobalHeapQuantile(phi)
+			require.NoError(t, err)
+
+			r := rand.New(rand.NewSource(1))
+			vals := []float64{}
+			for i := 0; i < 1000; i++ {
+				x := r.NormFloat64()
+				vals = append(vals, x)
+
+				err := q.Push(x)
+				require.NoError(t, err)
+
+				value, err := q.Value()
+				require.NoError(t, err)
+				testutil.Approx(t, linearQuantile(vals, phi), value)
+			}
+		})
+
+		t.Run("pass: windowed quantile matches sorted window", func(t *testing.T) {
+			window := 100
+			q, err := NewHeapQuantile(phi, window)
+			require.NoError(t, err)
+
+			r := rand.New(rand.NewSource(2))
+			vals := []float64{}
+			for i := 0; i < 1000; i++ {
+				// round values so that duplicates are exercised as well
+				x := math.Round(10 * r.NormFloat64())
+				vals = append(vals, x)
+				if len(vals) > window {
+					vals = vals[1:]
+				}
+
+				err := q.Push(x)
+				require.NoError(t, err)
+				assert.Equal(t, len(vals), q.Count())
+
+				value, err := q.Value()
+				require.NoError(t, err)
+				testutil.Approx(t, linearQuantile(vals, phi), value)
+			}
+		})
+	}
+
+	t.Run("fail: no values seen fails", func(t *testing.T) {
+		q, err := NewGlobalHeapQuantile(0.9)
+		require.NoError(t, err)
+
+		_, err = q.Value()
+		assert.EqualError(t, err, "no values seen yet")
+	})
+}
+
+func TestHeapQuantileClear(t *testing.T) {
+	q, err := NewHeapQuantile(0.9, 10)
+	require.NoError(t, err)
+
+	for i := 0.; i < 10; i++ {
+		err = q.Push(i)
+		require.NoError(t, err)
+	}
+
+	q.Clear()
+	assert.Equal(t, 0, q.Count())
+	assert.Equal(t, uint64(0), q.queue.Len())
+
+	_, err = q.Value()
+	assert.EqualError(t, err, "no values seen yet")
+}
@@ -0,0 +1,144 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream/util/ring"
+)
+
+// Histogram keeps track of the number of values of a stream that fall into each of a
+// set of buckets, whose boundaries are fixed up front. For boundaries b_0 < ... < b_{m-1},
+// there are m+1 buckets: bucket 0 counts the values x <= b_0, bucket i counts the values
+// b_{i-1} < x <= b_i, and bucket m counts the values x > b_{m-1}.
+type Histogram struct {
+	window     int
+	boundaries []float64
+	counts     []uint64
+	total      uint64
+	// holds the buckets of the values in the window, if one is set
+	queue ring.Queue[int]
+	mux   sync.RWMutex
+}
+
+// NewHistogram instantiates a Histogram struct with the provided bucket
+// boundaries, which must be nonempty and strictly increasing.
+func NewHistogram(window int, boundaries []float64) (*Histogram, error) {
+	if window < 0 {
+		return nil, errors.Errorf("attempted to set negative window of %d", window)
+	} else if len(boundaries) == 0 {
+		return nil, errors.New("no bucket boundaries provided")
+	}
+
+	for i, b := range boundaries {
+		if math.IsNaN(b) {
+			return nil, errors.Errorf("bucket boundary %d is NaN", i)
+		} else if i > 0 && b <= boundaries[i-1] {
+			return nil, errors.Errorf(
+				"bucket boundaries are not strictly increasing: %f <= %f at index %d",
+				b,
+				boundaries[i-1],
+				i,
+			)
+		}
+	}
+
+	return &Histogram{
+		window:     window,
+		boundaries: append([]float64{}, boundaries...),
+		counts:     make([]uint64, len(boundaries)+1),
+		queue:      ring.New[int](uint64(window)),
+	}, nil
+}
+
+// NewGlobalHistogram instantiates a global Histogram struct.
+// This is equivalent to calling NewHistogram(0, boundaries).
+func NewGlobalHistogram(boundaries []float64) (*Histogram, error) {
+	return NewHistogram(0, boundaries)
+}
+
+// String returns a string representation of the metric.
+func (h *Histogram) String() string {
+	name := "quantile.Histogram"
+	params := []string{
+		fmt.Sprintf("window:%v", h.window),
+		fmt.Sprintf("boundaries:%v", h.boundaries),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a number to the count of the bucket it falls into, and
+// removes the oldest value in the window from its bucket if it is full.
+func (h *Histogram) Push(x float64) error {
+	if math.IsNaN(x) {
+		return errors.New("cannot push NaN to Histogram")
+	}
+
+	// the first boundary that is >= x is the upper boundary of the bucket
+	bucket := sort.SearchFloat64s(h.boundaries, x)
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if h.window != 0 {
+		if h.queue.Len() == uint64(h.window) {
+			val, err := h.queue.Get()
+			if err != nil {
+				return errors.Wrap(err, "error popping item from queue")
+			}
+
+			h.counts[val]--
+			h.total--
+		}
+
+		err := h.queue.Put(bucket)
+		if err != nil {
+			return errors.Wrapf(err, "error pushing %f to queue", x)
+		}
+	}
+
+	h.counts[bucket]++
+	h.total++
+	return nil
+}
+
+// Value returns the total number of values counted, i.e. the
+// number of values in the window if one is set; see Total.
+func (h *Histogram) Value() (float64, error) {
+	return float64(h.Total()), nil
+}
+
+// Counts returns a copy of the number of values counted in each bucket.
+func (h *Histogram) Counts() []uint64 {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return append([]uint64{}, h.counts...)
+}
+
+// Total returns the total number of values counted across all buckets.
+func (h *Histogram) Total() uint64 {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return h.total
+}
+
+// Boundaries returns a copy of the bucket boundaries.
+func (h *Histogram) Boundaries() []float64 {
+	return append([]float64{}, h.boundaries...)
+}
+
+// Clear resets the metric.
+func (h *Histogram) Clear() {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.queue.Reset()
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+	h.total = 0
+}
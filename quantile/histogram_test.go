@@ -0,0 +1,132 @@
+package quantile
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHistogram(t *testing.T) {
+	t.Run("pass: valid Histogram is valid", func(t *testing.T) {
+		boundaries := []float64{1, 2, 3}
+		h, err := NewHistogram(3, boundaries)
+		require.NoError(t, err)
+		assert.Equal(t, 3, h.window)
+		assert.Equal(t, []float64{1, 2, 3}, h.Boundaries())
+		assert.Equal(t, []uint64{0, 0, 0, 0}, h.Counts())
+
+		// the boundaries are copied
+		boundaries[0] = 0
+		assert.Equal(t, []float64{1, 2, 3}, h.Boundaries())
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewHistogram(-1, []float64{1})
+		assert.EqualError(t, err, "attempted to set negative window of -1")
+	})
+
+	t.Run("fail: empty boundaries are invalid", func(t *testing.T) {
+		_, err := NewHistogram(3, nil)
+		assert.EqualError(t, err, "no bucket boundaries provided")
+	})
+
+	t.Run("fail: unsorted boundaries are invalid", func(t *testing.T) {
+		_, err := NewHistogram(3, []float64{1, 3, 2})
+		assert.EqualError(t, err, "bucket boundaries are not strictly increasing: 2.000000 <= 3.000000 at index 2")
+
+		_, err = NewHistogram(3, []float64{1, 1})
+		assert.EqualError(t, err, "bucket boundaries are not strictly increasing: 1.000000 <= 1.000000 at index 1")
+
+		_, err = NewHistogram(3, []float64{1, math.NaN()})
+		assert.EqualError(t, err, "bucket boundary 1 is NaN")
+	})
+}
+
+func TestNewGlobalHistogram(t *testing.T) {
+	h, err := NewHistogram(0, []float64{1, 2})
+	require.NoError(t, err)
+
+	globalH, err := NewGlobalHistogram([]float64{1, 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, h, globalH)
+}
+
+func TestHistogramString(t *testing.T) {
+	h, err := NewHistogram(3, []float64{1, 2.5})
+	require.NoError(t, err)
+	assert.Equal(t, "quantile.Histogram_{window:3,boundaries:[1 2.5]}", h.String())
+}
+
+func TestHistogramPush(t *testing.T) {
+	t.Run("pass: counts values per bucket", func(t *testing.T) {
+		h, err := NewGlobalHistogram([]float64{0, 10, 100})
+		require.NoError(t, err)
+
+		for _, x := range []float64{-5, 0, 0.5, 10, 11, 99, 100, 100.5, 1e9, math.Inf(-1), math.Inf(1)} {
+			err := h.Push(x)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, []uint64{3, 2, 3, 3}, h.Counts())
+		assert.Equal(t, uint64(11), h.Total())
+
+		value, err := h.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 11., value)
+	})
+
+	t.Run("pass: decrements the bucket of the evicted value", func(t *testing.T) {
+		h, err := NewHistogram(3, []float64{0, 10})
+		require.NoError(t, err)
+
+		for _, x := range []float64{-1, 5, 20} {
+			err := h.Push(x)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []uint64{1, 1, 1}, h.Counts())
+
+		// evicts -1
+		err = h.Push(30)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{0, 1, 2}, h.Counts())
+
+		// evicts 5
+		err = h.Push(-2)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1, 0, 2}, h.Counts())
+
+		// evicts 20 and 30
+		for _, x := range []float64{7, 8} {
+			err := h.Push(x)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []uint64{1, 2, 0}, h.Counts())
+		assert.Equal(t, uint64(3), h.Total())
+	})
+
+	t.Run("fail: NaN is not counted", func(t *testing.T) {
+		h, err := NewGlobalHistogram([]float64{0})
+		require.NoError(t, err)
+
+		err = h.Push(math.NaN())
+		assert.EqualError(t, err, "cannot push NaN to Histogram")
+		assert.Equal(t, uint64(0), h.Total())
+	})
+}
+
+func TestHistogramClear(t *testing.T) {
+	h, err := NewHistogram(3, []float64{0})
+	require.NoError(t, err)
+	for _, x := range []float64{-1, 1, 2, 3} {
+		err := h.Push(x)
+		require.NoError(t, err)
+	}
+
+	h.Clear()
+	assert.Equal(t, []uint64{0, 0}, h.Counts())
+	assert.Equal(t, uint64(0), h.Total())
+	assert.Equal(t, uint64(0), h.queue.Len())
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/K4Mobility/stream/quantile/order"
 	"github.com/K4Mobility/stream/quantile/skiplist"
 )
 
@@ -44,6 +45,59 @@ func TestImplInit(t *testing.T) {
 	})
 }
 
+func TestImplsAgreeOnDuplicates(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	impls := []Impl{AVL, RedBlack, SkipList}
+	stats := make([]order.Statistic, len(impls))
+	for i, impl := range impls {
+		statistic, err := impl.init(skiplist.SeedOption(1))
+		require.NoError(t, err)
+		stats[i] = statistic
+	}
+
+	check := func() {
+		size := stats[0].Size()
+		for i := range impls {
+			require.Equal(t, size, stats[i].Size(), "size for %v", impls[i])
+		}
+
+		for k := 0; k < size; k++ {
+			expected := stats[0].Select(k).Value()
+			for i := range impls[1:] {
+				assert.Equal(t, expected, stats[i+1].Select(k).Value(), "Select(%d) for %v", k, impls[i+1])
+			}
+		}
+
+		for v := -1.; v <= 10; v += 0.5 {
+			rank := stats[0].Rank(v)
+			inclusive := stats[0].RankInclusive(v)
+			for i := range impls[1:] {
+				assert.Equal(t, rank, stats[i+1].Rank(v), "Rank(%v) for %v", v, impls[i+1])
+				assert.Equal(t, inclusive, stats[i+1].RankInclusive(v), "RankInclusive(%v) for %v", v, impls[i+1])
+			}
+		}
+	}
+
+	// only 10 distinct values, so every value is heavily duplicated
+	var added []float64
+	for i := 0; i < 500; i++ {
+		x := float64(rng.Intn(10))
+		added = append(added, x)
+		for _, statistic := range stats {
+			statistic.Add(x)
+		}
+	}
+	check()
+
+	// removing duplicates should keep the implementations in step
+	for _, j := range rng.Perm(len(added))[:300] {
+		for _, statistic := range stats {
+			statistic.Remove(added[j])
+		}
+	}
+	check()
+}
+
 func BenchmarkImplAdd(b *testing.B) {
 	for k := 3.; k < 20; k++ {
 		n := int(math.Pow(2, k))
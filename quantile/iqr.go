@@ -48,14 +48,14 @@ func (i *IQR) Value() (float64, error) {
 	i.quantile.RLock()
 	defer i.quantile.RUnlock()
 
-	q25, err := i.quantile.Value(0.25)
+	q25, err := i.quantile.value(0.25)
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving 1st quartile")
+		return 0, fmt.Errorf("error retrieving 1st quartile: %w", err)
 	}
 
-	q75, err := i.quantile.Value(0.75)
+	q75, err := i.quantile.value(0.75)
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving 3rd quartile")
+		return 0, fmt.Errorf("error retrieving 3rd quartile: %w", err)
 	}
 
 	return q75 - q25, nil
@@ -0,0 +1,160 @@
+package quantile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// KthSmallest keeps track of the kth smallest value of a stream using order
+// statistics, e.g. the 3rd lowest latency seen (or in the window, if one is set).
+// k is 1-based, so the 1st smallest value is the minimum.
+type KthSmallest struct {
+	k        int
+	quantile *Quantile
+}
+
+// NewKthSmallest instantiates a KthSmallest struct. The implementation of the underlying
+// data structure for tracking order statistics can be configured by passing in a constant
+// of type Impl.
+func NewKthSmallest(k int, window int, options ...Option) (*KthSmallest, error) {
+	quantile, err := newKth(k, window, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &KthSmallest{k: k, quantile: quantile}, nil
+}
+
+// NewGlobalKthSmallest instantiates a global KthSmallest struct.
+// This is equivalent to calling NewKthSmallest(k, 0, options...).
+func NewGlobalKthSmallest(k int, options ...Option) (*KthSmallest, error) {
+	return NewKthSmallest(k, 0, options...)
+}
+
+// String returns a string representation of the metric.
+func (s *KthSmallest) String() string {
+	return kthString("quantile.KthSmallest", s.k, s.quantile)
+}
+
+// Push adds a number for calculating the kth smallest value.
+func (s *KthSmallest) Push(x float64) error {
+	err := s.quantile.Push(x)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing %f to Quantile", x)
+	}
+	return nil
+}
+
+// Value returns the kth smallest value, or an error if fewer than k values are tracked.
+func (s *KthSmallest) Value() (float64, error) {
+	s.quantile.RLock()
+	defer s.quantile.RUnlock()
+
+	_, err := kthSize(s.k, s.quantile)
+	if err != nil {
+		return 0, err
+	}
+	return s.quantile.statistic.Select(s.k - 1).Value(), nil
+}
+
+// Clear resets the metric.
+func (s *KthSmallest) Clear() {
+	s.quantile.Clear()
+}
+
+// KthLargest keeps track of the kth largest value of a stream using order
+// statistics, e.g. the 3rd highest latency seen (or in the window, if one is set).
+// k is 1-based, so the 1st largest value is the maximum.
+type KthLargest struct {
+	k        int
+	quantile *Quantile
+}
+
+// NewKthLargest instantiates a KthLargest struct. The implementation of the underlying
+// data structure for tracking order statistics can be configured by passing in a constant
+// of type Impl.
+func NewKthLargest(k int, window int, options ...Option) (*KthLargest, error) {
+	quantile, err := newKth(k, window, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &KthLargest{k: k, quantile: quantile}, nil
+}
+
+// NewGlobalKthLargest instantiates a global KthLargest struct.
+// This is equivalent to calling NewKthLargest(k, 0, options...).
+func NewGlobalKthLargest(k int, options ...Option) (*KthLargest, error) {
+	return NewKthLargest(k, 0, options...)
+}
+
+// String returns a string representation of the metric.
+func (l *KthLargest) String() string {
+	return kthString("quantile.KthLargest", l.k, l.quantile)
+}
+
+// Push adds a number for calculating the kth largest value.
+func (l *KthLargest) Push(x float64) error {
+	err := l.quantile.Push(x)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing %f to Quantile", x)
+	}
+	return nil
+}
+
+// Value returns the kth largest value, or an error if fewer than k values are tracked.
+func (l *KthLargest) Value() (float64, error) {
+	l.quantile.RLock()
+	defer l.quantile.RUnlock()
+
+	size, err := kthSize(l.k, l.quantile)
+	if err != nil {
+		return 0, err
+	}
+	return l.quantile.statistic.Select(size - l.k).Value(), nil
+}
+
+// Clear resets the metric.
+func (l *KthLargest) Clear() {
+	l.quantile.Clear()
+}
+
+// newKth validates k and creates the Quantile wrapped by KthSmallest and KthLargest.
+func newKth(k int, window int, options ...Option) (*Quantile, error) {
+	if k < 1 {
+		return nil, errors.Errorf("k %d is not positive", k)
+	}
+
+	if window > 0 && k > window {
+		return nil, errors.Errorf("k %d is greater than the window %d", k, window)
+	}
+
+	quantile, err := New(window, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Quantile")
+	}
+	return quantile, nil
+}
+
+// kthString returns the string representation of KthSmallest and KthLargest.
+func kthString(name string, k int, quantile *Quantile) string {
+	params := []string{
+		fmt.Sprintf("k:%v", k),
+		fmt.Sprintf("quantile:%v", quantile.String()),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// kthSize returns the number of values tracked by the quantile, or an error
+// if there are fewer than k of them; it does not lock.
+func kthSize(k int, quantile *Quantile) (int, error) {
+	size := quantile.statistic.Size()
+	if size == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	if k > size {
+		return 0, errors.Errorf("k %d is out of range for %d values", k, size)
+	}
+	return size, nil
+}
@@ -0,0 +1,175 @@
+package quantile
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/K4Mobility/stream/quantile/skiplist"
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewKth(t *testing.T) {
+	t.Run("pass: valid KthSmallest and KthLargest are valid", func(t *testing.T) {
+		s, err := NewKthSmallest(2, 3, ImplOption(SkipList))
+		require.NoError(t, err)
+		assert.Equal(t, 2, s.k)
+		assert.Equal(t, 3, s.quantile.window)
+		_, ok := s.quantile.statistic.(*skiplist.SkipList)
+		assert.True(t, ok)
+
+		l, err := NewKthLargest(3, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 3, l.k)
+		assert.Equal(t, 3, l.quantile.window)
+	})
+
+	t.Run("pass: global metrics accept any positive k", func(t *testing.T) {
+		s, err := NewGlobalKthSmallest(100)
+		require.NoError(t, err)
+		expected, err := NewKthSmallest(100, 0)
+		require.NoError(t, err)
+		assert.Equal(t, expected, s)
+
+		l, err := NewGlobalKthLargest(100)
+		require.NoError(t, err)
+		expectedL, err := NewKthLargest(100, 0)
+		require.NoError(t, err)
+		assert.Equal(t, expectedL, l)
+	})
+
+	t.Run("fail: non-positive k is invalid", func(t *testing.T) {
+		_, err := NewKthSmallest(0, 3)
+		testutil.ContainsError(t, err, "k 0 is not positive")
+		_, err = NewKthLargest(-1, 3)
+		testutil.ContainsError(t, err, "k -1 is not positive")
+	})
+
+	t.Run("fail: k greater than the window is invalid", func(t *testing.T) {
+		_, err := NewKthSmallest(4, 3)
+		testutil.ContainsError(t, err, "k 4 is greater than the window 3")
+		_, err = NewKthLargest(4, 3)
+		testutil.ContainsError(t, err, "k 4 is greater than the window 3")
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewKthSmallest(1, -1)
+		testutil.ContainsError(t, err, "error creating Quantile")
+	})
+}
+
+func TestKthString(t *testing.T) {
+	s, err := NewKthSmallest(2, 3)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		fmt.Sprintf("quantile.KthSmallest_{k:2,quantile:quantile.Quantile_{window:3,interpolation:%d}}", Linear),
+		s.String(),
+	)
+
+	l, err := NewKthLargest(2, 3)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		fmt.Sprintf("quantile.KthLargest_{k:2,quantile:quantile.Quantile_{window:3,interpolation:%d}}", Linear),
+		l.String(),
+	)
+}
+
+func TestKthValue(t *testing.T) {
+	for _, impl := range []Impl{AVL, RedBlack, SkipList} {
+		t.Run(fmt.Sprintf("pass: matches sorted window for %v", impl), func(t *testing.T) {
+			window, k := 10, 3
+			s, err := NewKthSmallest(k, window, ImplOption(impl))
+			require.NoError(t, err)
+			l, err := NewKthLargest(k, window, ImplOption(impl))
+			require.NoError(t, err)
+
+			r := rand.New(rand.NewSource(1))
+			xs := make([]float64, 200)
+			for i := range xs {
+				xs[i] = float64(r.Intn(20))
+			}
+
+			for i, x := range xs {
+				require.NoError(t, s.Push(x))
+				require.NoError(t, l.Push(x))
+
+				start := i + 1 - window
+				if start < 0 {
+					start = 0
+				}
+				sorted := append([]float64{}, xs[start:i+1]...)
+				sort.Float64s(sorted)
+
+				if len(sorted) < k {
+					_, err := s.Value()
+					testutil.ContainsError(t, err, fmt.Sprintf("k %d is out of range for %d values", k, len(sorted)))
+					_, err = l.Value()
+					testutil.ContainsError(t, err, fmt.Sprintf("k %d is out of range for %d values", k, len(sorted)))
+					continue
+				}
+
+				val, err := s.Value()
+				require.NoError(t, err)
+				assert.Equal(t, sorted[k-1], val, "index %d", i)
+
+				val, err = l.Value()
+				require.NoError(t, err)
+				assert.Equal(t, sorted[len(sorted)-k], val, "index %d", i)
+			}
+		})
+	}
+
+	t.Run("pass: first smallest and largest are the min and max", func(t *testing.T) {
+		s, err := NewGlobalKthSmallest(1)
+		require.NoError(t, err)
+		l, err := NewGlobalKthLargest(1)
+		require.NoError(t, err)
+		for _, x := range []float64{3, -1, 7, 2} {
+			require.NoError(t, s.Push(x))
+			require.NoError(t, l.Push(x))
+		}
+
+		val, err := s.Value()
+		require.NoError(t, err)
+		assert.Equal(t, -1., val)
+		val, err = l.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 7., val)
+	})
+
+	t.Run("fail: no values seen", func(t *testing.T) {
+		s, err := NewGlobalKthSmallest(1)
+		require.NoError(t, err)
+		_, err = s.Value()
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+
+		l, err := NewGlobalKthLargest(1)
+		require.NoError(t, err)
+		_, err = l.Value()
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+	})
+}
+
+func TestKthClear(t *testing.T) {
+	s, err := NewKthSmallest(1, 3)
+	require.NoError(t, err)
+	l, err := NewKthLargest(1, 3)
+	require.NoError(t, err)
+	for i := 0.; i < 5; i++ {
+		require.NoError(t, s.Push(i))
+		require.NoError(t, l.Push(i))
+	}
+
+	s.Clear()
+	l.Clear()
+	assert.Equal(t, 0, s.quantile.statistic.Size())
+	assert.Equal(t, 0, l.quantile.statistic.Size())
+	assert.Equal(t, uint64(0), s.quantile.queue.Len())
+	assert.Equal(t, uint64(0), l.quantile.queue.Len())
+}
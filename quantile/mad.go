@@ -0,0 +1,85 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// MAD keeps track of the median absolute deviation of a stream using order statistics,
+// i.e. the median of the absolute deviations of the values from their median.
+type MAD struct {
+	quantile *Quantile
+}
+
+// NewMAD instantiates a MAD struct. The implementation of the underlying data
+// structure for tracking order statistics can be configured by passing in a constant
+// of type Impl.
+func NewMAD(window int, options ...Option) (*MAD, error) {
+	quantile, err := New(window, append(options, InterpolationOption(Midpoint))...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Quantile")
+	}
+
+	return &MAD{quantile: quantile}, nil
+}
+
+// NewGlobalMAD instantiates a global MAD struct.
+// This is equivalent to calling NewMAD(0, options...).
+func NewGlobalMAD(options ...Option) (*MAD, error) {
+	return NewMAD(0, options...)
+}
+
+// String returns a string representation of the metric.
+func (m *MAD) String() string {
+	name := "quantile.MAD"
+	quantile := fmt.Sprintf("quantile:%v", m.quantile.String())
+	return fmt.Sprintf("%s_{%s}", name, quantile)
+}
+
+// Push adds a number for calculating the median absolute deviation.
+func (m *MAD) Push(x float64) error {
+	err := m.quantile.Push(x)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing %f to Quantile", x)
+	}
+	return nil
+}
+
+// Value returns the value of the median absolute deviation. The deviations
+// depend on the current median, so they are recomputed from the tracked values
+// on each call; this takes O(n log n) time, where n is the number of values tracked.
+func (m *MAD) Value() (float64, error) {
+	m.quantile.RLock()
+	defer m.quantile.RUnlock()
+
+	median, err := m.quantile.value(0.5)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving median: %w", err)
+	}
+
+	return medianAbsDev(m.quantile, median), nil
+}
+
+// Clear resets the metric.
+func (m *MAD) Clear() {
+	m.quantile.Clear()
+}
+
+// medianAbsDev returns the median of the absolute deviations of the values
+// tracked by a Quantile from the provided median, but does not lock.
+func medianAbsDev(q *Quantile, median float64) float64 {
+	size := q.statistic.Size()
+	deviations := make([]float64, size)
+	for i := 0; i < size; i++ {
+		deviations[i] = math.Abs(q.statistic.Select(i).Value() - median)
+	}
+	sort.Float64s(deviations)
+
+	if size%2 == 1 {
+		return deviations[size/2]
+	}
+	return (deviations[size/2-1] + deviations[size/2]) / 2
+}
@@ -0,0 +1,135 @@
+package quantile
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewMAD(t *testing.T) {
+	t.Run("pass: nonnegative window is valid", func(t *testing.T) {
+		mad, err := NewMAD(0)
+		require.NoError(t, err)
+		assert.Equal(t, 0, mad.quantile.window)
+
+		mad, err = NewMAD(5, ImplOption(SkipList))
+		require.NoError(t, err)
+		assert.Equal(t, 5, mad.quantile.window)
+		assert.Equal(t, Midpoint, mad.quantile.interpolation)
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewMAD(-1)
+		testutil.ContainsError(t, err, "error creating Quantile")
+	})
+
+	t.Run("fail: invalid Option is invalid", func(t *testing.T) {
+		_, err := NewMAD(3, ImplOption(-1))
+		testutil.ContainsError(t, err, "error creating Quantile")
+	})
+}
+
+func TestNewGlobalMAD(t *testing.T) {
+	mad, err := NewMAD(0)
+	require.NoError(t, err)
+
+	globalMAD, err := NewGlobalMAD()
+	require.NoError(t, err)
+
+	assert.Equal(t, mad, globalMAD)
+}
+
+func TestMADString(t *testing.T) {
+	expectedString := fmt.Sprintf(
+		"quantile.MAD_{quantile:quantile.Quantile_{window:3,interpolation:%d}}",
+		Midpoint,
+	)
+	mad, err := NewMAD(3)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedString, mad.String())
+}
+
+func TestMADPush(t *testing.T) {
+	t.Run("pass: successfully pushes values", func(t *testing.T) {
+		mad, err := NewMAD(3)
+		require.NoError(t, err)
+		for i := 0.; i < 5; i++ {
+			err := mad.Push(i)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, mad.quantile.statistic.Size())
+	})
+
+	t.Run("fail: if queue insertion fails, return error", func(t *testing.T) {
+		mad, err := NewMAD(3)
+		require.NoError(t, err)
+
+		// dispose the queue to simulate an error when we try to insert into the queue
+		mad.quantile.queue.Dispose()
+		val := 3.
+		err = mad.Push(val)
+		testutil.ContainsError(t, err, fmt.Sprintf("error pushing %f to Quantile", val))
+	})
+}
+
+func TestMADValue(t *testing.T) {
+	xs := []float64{1, 1, 2, 2, 4, 6, 9}
+
+	for _, impl := range []Impl{AVL, RedBlack, SkipList} {
+		t.Run(fmt.Sprintf("pass: returns global MAD for Impl %d", impl), func(t *testing.T) {
+			mad, err := NewGlobalMAD(ImplOption(impl))
+			require.NoError(t, err)
+			for _, x := range xs {
+				err := mad.Push(x)
+				require.NoError(t, err)
+			}
+
+			// median is 2, sorted deviations are {0, 0, 1, 1, 2, 4, 7}
+			value, err := mad.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, 1, value)
+		})
+	}
+
+	t.Run("pass: returns MAD over window", func(t *testing.T) {
+		mad, err := NewMAD(4)
+		require.NoError(t, err)
+		for _, x := range xs {
+			err := mad.Push(x)
+			require.NoError(t, err)
+		}
+
+		// window is {2, 4, 6, 9}, median is 5, sorted deviations are {1, 1, 3, 4}
+		value, err := mad.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 2, value)
+	})
+
+	t.Run("fail: if no values seen, return error", func(t *testing.T) {
+		mad, err := NewMAD(3)
+		require.NoError(t, err)
+
+		_, err = mad.Value()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+}
+
+func TestMADClear(t *testing.T) {
+	mad, err := NewMAD(3)
+	require.NoError(t, err)
+
+	for i := 0.; i < 10; i++ {
+		err = mad.Push(i * i)
+		require.NoError(t, err)
+	}
+
+	mad.Clear()
+	assert.Equal(t, uint64(0), mad.quantile.queue.Len())
+	assert.Equal(t, 0, mad.quantile.statistic.Size())
+}
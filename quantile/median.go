@@ -49,7 +49,7 @@ func (m *Median) Push(x float64) error {
 func (m *Median) Value() (float64, error) {
 	value, err := m.quantile.Value(0.5)
 	if err != nil {
-		return 0, errors.Wrap(err, "error retrieving quantile value")
+		return 0, fmt.Errorf("error retrieving quantile value: %w", err)
 	}
 	return value, nil
 }
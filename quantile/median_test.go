@@ -2,11 +2,13 @@ package quantile
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/K4Mobility/stream/quantile/skiplist"
 	testutil "github.com/K4Mobility/stream/util/test"
 )
 
@@ -119,6 +121,38 @@ func TestMedianValue(t *testing.T) {
 		assert.Equal(t, float64(3), value)
 	})
 
+	t.Run("pass: all Impls give identical medians", func(t *testing.T) {
+		impls := []Option{
+			ImplOption(AVL),
+			ImplOption(RedBlack),
+			ImplOption(SkipList, skiplist.MaxLevelOption(4), skiplist.SeedOption(1)),
+		}
+		medians := make([]*Median, len(impls))
+		for i, impl := range impls {
+			median, err := NewMedian(25, impl)
+			require.NoError(t, err)
+			medians[i] = median
+		}
+
+		rng := rand.New(rand.NewSource(1))
+		for i := 0; i < 1000; i++ {
+			// few distinct values, so that the window holds many duplicates
+			x := float64(rng.Intn(20))
+			for _, median := range medians {
+				err := median.Push(x)
+				require.NoError(t, err)
+			}
+
+			expected, err := medians[0].Value()
+			require.NoError(t, err)
+			for _, median := range medians[1:] {
+				value, err := median.Value()
+				require.NoError(t, err)
+				assert.Equal(t, expected, value)
+			}
+		}
+	})
+
 	t.Run("fail: if no values seen, return error", func(t *testing.T) {
 		median, err := NewMedian(3)
 		require.NoError(t, err)
@@ -0,0 +1,127 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream/util/ring"
+)
+
+// Mode keeps track of the most frequent value of a stream, with a frequency table
+// keyed by value. It is meant for streams whose values are effectively discrete
+// (e.g. integer-valued codes); values are only counted together if they are exactly
+// equal, so continuous data should be bucketed by the caller before being pushed.
+type Mode struct {
+	window int
+	counts map[float64]int
+	// holds the values in the window, if one is set
+	queue ring.Queue[float64]
+	mux   sync.RWMutex
+}
+
+// NewMode instantiates a Mode struct.
+func NewMode(window int) (*Mode, error) {
+	if window < 0 {
+		return nil, errors.Errorf("attempted to set negative window of %d", window)
+	}
+
+	return &Mode{
+		window: window,
+		counts: map[float64]int{},
+		queue:  ring.New[float64](uint64(window)),
+	}, nil
+}
+
+// NewGlobalMode instantiates a global Mode struct.
+// This is equivalent to calling NewMode(0).
+func NewGlobalMode() (*Mode, error) {
+	return NewMode(0)
+}
+
+// String returns a string representation of the metric.
+func (m *Mode) String() string {
+	name := "quantile.Mode"
+	window := fmt.Sprintf("window:%v", m.window)
+	return fmt.Sprintf("%s_{%s}", name, window)
+}
+
+// Push adds a number to the frequency table, and removes the
+// oldest value in the window from the table if it is full.
+func (m *Mode) Push(x float64) error {
+	if math.IsNaN(x) {
+		return errors.New("cannot push NaN to Mode")
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.window != 0 {
+		if m.queue.Len() == uint64(m.window) {
+			val, err := m.queue.Get()
+			if err != nil {
+				return errors.Wrap(err, "error popping item from queue")
+			}
+
+			old := val
+			m.counts[old]--
+			if m.counts[old] == 0 {
+				delete(m.counts, old)
+			}
+		}
+
+		err := m.queue.Put(x)
+		if err != nil {
+			return errors.Wrapf(err, "error pushing %f to queue", x)
+		}
+	}
+
+	m.counts[x]++
+	return nil
+}
+
+// Value returns the most frequent value; if several values are
+// equally frequent, the lowest of them is returned.
+func (m *Mode) Value() (float64, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	if len(m.counts) == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	var (
+		mode float64
+		max  int
+	)
+	for x, count := range m.counts {
+		if count > max || (count == max && x < mode) {
+			mode = x
+			max = count
+		}
+	}
+	return mode, nil
+}
+
+// Counts returns a copy of the frequency table, i.e. the number
+// of times each value was seen (in the window, if one is set).
+func (m *Mode) Counts() map[float64]int {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	counts := make(map[float64]int, len(m.counts))
+	for x, count := range m.counts {
+		counts[x] = count
+	}
+	return counts
+}
+
+// Clear resets the metric.
+func (m *Mode) Clear() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.queue.Reset()
+	m.counts = map[float64]int{}
+}
@@ -0,0 +1,137 @@
+package quantile
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewMode(t *testing.T) {
+	t.Run("pass: valid window is valid", func(t *testing.T) {
+		m, err := NewMode(3)
+		require.NoError(t, err)
+		assert.Equal(t, 3, m.window)
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewMode(-1)
+		testutil.ContainsError(t, err, "attempted to set negative window of -1")
+	})
+}
+
+func TestNewGlobalMode(t *testing.T) {
+	m, err := NewMode(0)
+	require.NoError(t, err)
+
+	globalM, err := NewGlobalMode()
+	require.NoError(t, err)
+
+	assert.Equal(t, m, globalM)
+}
+
+func TestModeString(t *testing.T) {
+	m, err := NewMode(3)
+	require.NoError(t, err)
+	assert.Equal(t, "quantile.Mode_{window:3}", m.String())
+}
+
+func TestModeValue(t *testing.T) {
+	t.Run("pass: returns the most frequent value", func(t *testing.T) {
+		m, err := NewGlobalMode()
+		require.NoError(t, err)
+		for _, x := range []float64{3, 1, 3, 2, 3, 1} {
+			err := m.Push(x)
+			require.NoError(t, err)
+		}
+
+		value, err := m.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 3., value)
+		assert.Equal(t, map[float64]int{1: 2, 2: 1, 3: 3}, m.Counts())
+	})
+
+	t.Run("pass: returns the lowest value on ties", func(t *testing.T) {
+		m, err := NewGlobalMode()
+		require.NoError(t, err)
+		for _, x := range []float64{5, 4, -2, 5, -2, 4} {
+			err := m.Push(x)
+			require.NoError(t, err)
+		}
+
+		value, err := m.Value()
+		require.NoError(t, err)
+		assert.Equal(t, -2., value)
+	})
+
+	t.Run("pass: evicts values from the window", func(t *testing.T) {
+		m, err := NewMode(3)
+		require.NoError(t, err)
+
+		for _, tt := range []struct {
+			x        float64
+			expected float64
+		}{
+			{7, 7},
+			{7, 7},
+			{1, 7},
+			// evicts a 7, leaving a tie between 1 and 7
+			{9, 1},
+			// evicts the other 7
+			{9, 9},
+		} {
+			err := m.Push(tt.x)
+			require.NoError(t, err)
+
+			value, err := m.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		}
+		assert.Equal(t, map[float64]int{1: 1, 9: 2}, m.Counts())
+	})
+
+	t.Run("fail: no values seen is an error", func(t *testing.T) {
+		m, err := NewGlobalMode()
+		require.NoError(t, err)
+
+		_, err = m.Value()
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+	})
+
+	t.Run("fail: NaN is invalid", func(t *testing.T) {
+		m, err := NewGlobalMode()
+		require.NoError(t, err)
+
+		err = m.Push(math.NaN())
+		assert.EqualError(t, err, "cannot push NaN to Mode")
+	})
+}
+
+func TestModeCounts(t *testing.T) {
+	m, err := NewGlobalMode()
+	require.NoError(t, err)
+	require.NoError(t, m.Push(1))
+
+	counts := m.Counts()
+	counts[1] = 100
+	assert.Equal(t, map[float64]int{1: 1}, m.Counts())
+}
+
+func TestModeClear(t *testing.T) {
+	m, err := NewMode(3)
+	require.NoError(t, err)
+	for _, x := range []float64{1, 2, 2, 3} {
+		err := m.Push(x)
+		require.NoError(t, err)
+	}
+
+	m.Clear()
+	assert.Equal(t, map[float64]int{}, m.Counts())
+	assert.Equal(t, uint64(0), m.queue.Len())
+
+	_, err = m.Value()
+	assert.ErrorIs(t, err, ErrorNoValuesSeen)
+}
@@ -6,15 +6,47 @@ type Node interface {
 }
 
 // Statistic is the interface required for any data structure that
-// can provide order statistics.
+// can provide order statistics. Implementations need not be safe for
+// concurrent use; metrics wrapping them are responsible for locking.
+//
+// Values are kept as a multiset: Add places a new value before any equal
+// values already present, and Remove deletes a single copy. Since equal values
+// are indistinguishable, Select, Rank and RankInclusive return the same results
+// for every implementation given the same sequence of Add and Remove calls.
 type Statistic interface {
 	Add(float64)
 	Remove(float64)
+	Delete(float64) bool
 	Size() int
 	Select(int) Node
 	Rank(float64) int
+	RankInclusive(float64) int
+	Min() (float64, bool)
+	Max() (float64, bool)
 	Clear()
 }
 
+// Iterable is an optional interface for an order.Statistic that can
+// visit all of its values in sorted order more efficiently than by calling
+// Select for each rank. InOrder should stop early if f returns false.
+type Iterable interface {
+	InOrder(f func(float64) bool)
+}
+
+// Ranger is an optional interface for an order.Statistic that can
+// answer queries about the values lying in a half-open interval [lo, hi).
+type Ranger interface {
+	RangeCount(lo float64, hi float64) int
+	RangeValues(lo float64, hi float64) []float64
+}
+
+// Neighbors is an optional interface for an order.Statistic that can find the
+// nearest values strictly below and above a given value. Floor and Ceil should
+// return false if there is no such value.
+type Neighbors interface {
+	Floor(val float64) (float64, bool)
+	Ceil(val float64) (float64, bool)
+}
+
 // Option is an optional argument which sets an optional field for creating an order.Statistic
 type Option func(Statistic) error
@@ -121,6 +121,51 @@ func (n *Node) remove(val float64) *Node {
 	return root.balance()
 }
 
+// removeAt removes the node with the kth smallest value in the subtree rooted at
+// the node; unlike remove, this locates the node by its rank rather than its value.
+func (n *Node) removeAt(k int) *Node {
+	if n == nil {
+		return n
+	}
+
+	root := n
+	size := root.left.Size()
+	if k < size {
+		root.left = root.left.removeAt(k)
+	} else if k > size {
+		root.right = root.right.removeAt(k - size - 1)
+	} else {
+		if root.left == nil {
+			return root.right
+		} else if root.right == nil {
+			return root.left
+		}
+		root = n.right.min()
+		root.right = n.right.removeMin()
+		root.left = n.left
+	}
+
+	root.size = root.left.Size() + root.right.Size() + 1
+	root.height = max(root.left.Height(), root.right.Height()) + 1
+	return root.balance()
+}
+
+// build recursively builds a perfectly balanced subtree from sorted
+// values, rooted at the midpoint of the values.
+func build(sorted []float64) *Node {
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	mid := len(sorted) / 2
+	n := NewNode(sorted[mid])
+	n.left = build(sorted[:mid])
+	n.right = build(sorted[mid+1:])
+	n.size = n.left.Size() + n.right.Size() + 1
+	n.height = max(n.left.Height(), n.right.Height()) + 1
+	return n
+}
+
 func (n *Node) min() *Node {
 	if n.left == nil {
 		return n
@@ -129,6 +174,14 @@ func (n *Node) min() *Node {
 	return n.left.min()
 }
 
+func (n *Node) max() *Node {
+	if n.right == nil {
+		return n
+	}
+
+	return n.right.max()
+}
+
 func (n *Node) removeMin() *Node {
 	if n.left == nil {
 		return n.right
@@ -168,6 +221,22 @@ func (n *Node) heightDiff() int {
 	return n.left.Height() - n.right.Height()
 }
 
+// balanced returns whether the subtree rooted at the node satisfies the AVL
+// invariants, i.e. the heights of the children of every node differ by at most 1,
+// and the height and size stored at every node are consistent with its children.
+func (n *Node) balanced() bool {
+	if n == nil {
+		return true
+	}
+
+	diff := n.heightDiff()
+	return diff >= -1 && diff <= 1 &&
+		n.height == max(n.left.Height(), n.right.Height())+1 &&
+		n.size == n.left.Size()+n.right.Size()+1 &&
+		n.left.balanced() &&
+		n.right.balanced()
+}
+
 func (n *Node) rotateLeft() *Node {
 	m := n.right
 	n.right = m.left
@@ -226,12 +295,85 @@ func (n *Node) Select(k int) order.Node {
 func (n *Node) Rank(val float64) int {
 	if n == nil {
 		return 0
-	} else if val < n.val {
-		return n.left.Rank(val)
 	} else if val > n.val {
 		return 1 + n.left.Size() + n.right.Rank(val)
 	}
-	return n.left.Size()
+	// values equal to the node's may also be stored in its left
+	// subtree, so they must be excluded from the count as well
+	return n.left.Rank(val)
+}
+
+// RankInclusive returns the number of nodes less than or equal to the value
+// that are contained in the subtree rooted at the node.
+func (n *Node) RankInclusive(val float64) int {
+	if n == nil {
+		return 0
+	} else if val >= n.val {
+		// values equal to the node's may also be stored in its right
+		// subtree after rotations, so it must be searched as well
+		return 1 + n.left.Size() + n.right.RankInclusive(val)
+	}
+	return n.left.RankInclusive(val)
+}
+
+// inOrder calls f on each value in the subtree rooted at the node in sorted order,
+// stopping early if f returns false; it returns whether the traversal completed.
+func (n *Node) inOrder(f func(float64) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.inOrder(f) && f(n.val) && n.right.inOrder(f)
+}
+
+// rangeValues appends the values in the subtree rooted at the node that lie
+// in the interval [lo, hi) to vals in sorted order, skipping any subtrees that
+// lie outside of the interval.
+func (n *Node) rangeValues(lo float64, hi float64, vals []float64) []float64 {
+	if n == nil {
+		return vals
+	}
+	if lo <= n.val {
+		vals = n.left.rangeValues(lo, hi, vals)
+	}
+	if lo <= n.val && n.val < hi {
+		vals = append(vals, n.val)
+	}
+	if n.val < hi {
+		vals = n.right.rangeValues(lo, hi, vals)
+	}
+	return vals
+}
+
+// floor returns the node with the largest value strictly less than val in the
+// subtree rooted at the node, or nil if there is none. It descends from the
+// node to a leaf once, keeping track of the best candidate seen so far.
+func (n *Node) floor(val float64) *Node {
+	var candidate *Node
+	for n != nil {
+		if n.val < val {
+			candidate = n
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return candidate
+}
+
+// ceil returns the node with the smallest value strictly greater than val in the
+// subtree rooted at the node, or nil if there is none. It descends from the
+// node to a leaf once, keeping track of the best candidate seen so far.
+func (n *Node) ceil(val float64) *Node {
+	var candidate *Node
+	for n != nil {
+		if n.val > val {
+			candidate = n
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return candidate
 }
 
 /*******************
@@ -1,10 +1,20 @@
 package avl
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/K4Mobility/stream/quantile/order"
 )
 
 type TreeSuite struct {
@@ -144,6 +154,90 @@ func (s *TreeSuite) TestRemove() {
 	})
 }
 
+func (s *TreeSuite) TestDelete() {
+	s.Run("pass: deleting a present value returns true", func() {
+		s.SetupTest()
+		s.True(s.tree.Delete(5))
+		s.Equal(7, s.tree.Size())
+		s.Nil(s.tree.Select(7))
+	})
+
+	s.Run("pass: deleting an absent value returns false", func() {
+		s.SetupTest()
+		s.False(s.tree.Delete(100))
+		s.False(s.tree.Delete(4.5))
+		s.Equal(8, s.tree.Size())
+	})
+
+	s.Run("pass: each duplicate is deleted once", func() {
+		s.SetupTest()
+		s.True(s.tree.Delete(1))
+		s.True(s.tree.Delete(1))
+		s.False(s.tree.Delete(1))
+		s.Equal(6, s.tree.Size())
+	})
+
+	s.Run("pass: deleting from an empty tree returns false", func() {
+		s.tree.Clear()
+		s.False(s.tree.Delete(1))
+		s.Equal(0, s.tree.Size())
+	})
+}
+
+func (s *TreeSuite) TestRemoveAt() {
+	s.Run("pass: removes the node of the given rank among duplicates", func() {
+		s.SetupTest()
+		s.tree.Add(5)
+		s.tree.Add(1)
+
+		s.tree.RemoveAt(1)
+		s.tree.RemoveAt(5)
+		s.tree.RemoveAt(7)
+		s.Equal(7, s.tree.Size())
+
+		vals := []float64{}
+		s.tree.InOrder(func(val float64) bool {
+			vals = append(vals, val)
+			return true
+		})
+		s.Equal([]float64{1, 1, 2, 3, 4, 5, 6}, vals)
+	})
+
+	s.Run("pass: out of range ranks are a no-op", func() {
+		s.SetupTest()
+		s.tree.RemoveAt(-1)
+		s.tree.RemoveAt(8)
+		s.Equal(8, s.tree.Size())
+
+		s.tree.Clear()
+		s.tree.RemoveAt(0)
+		s.Equal(0, s.tree.Size())
+	})
+
+	s.Run("pass: keeps the tree balanced and the remaining multiset intact", func() {
+		s.tree.Clear()
+		vals := []float64{}
+		for i := 0; i < 300; i++ {
+			val := float64((i * 37) % 50)
+			s.tree.Add(val)
+			vals = append(vals, val)
+		}
+		sort.Float64s(vals)
+
+		for i := 0; len(vals) > 0; i++ {
+			k := (i * 53) % len(vals)
+			s.tree.RemoveAt(k)
+			vals = append(vals[:k], vals[k+1:]...)
+
+			s.Equal(len(vals), s.tree.Size())
+			s.LessOrEqual(s.tree.Height(), int(1.45*math.Log2(float64(len(vals)+2))))
+			for j, val := range vals {
+				s.Equal(val, s.tree.Select(j).Value())
+			}
+		}
+	})
+}
+
 func (s *TreeSuite) TestRank() {
 	rank := s.tree.Rank(3)
 	s.Equal(3, rank)
@@ -153,6 +247,46 @@ func (s *TreeSuite) TestRank() {
 
 	rank = s.tree.Rank(-1)
 	s.Equal(0, rank)
+
+	// duplicates are not counted as strictly less than each other
+	rank = s.tree.Rank(1)
+	s.Equal(0, rank)
+
+	rank = s.tree.Rank(2)
+	s.Equal(2, rank)
+}
+
+func (s *TreeSuite) TestRankInclusive() {
+	s.Run("pass: counts duplicates of the value", func() {
+		// the values are 1, 1, 2, 3, 4, 5, 6, 7
+		for _, tt := range []struct {
+			val       float64
+			rank      int
+			inclusive int
+		}{
+			{-1, 0, 0},
+			{1, 0, 2},
+			{1.5, 2, 2},
+			{2, 2, 3},
+			{5.5, 6, 6},
+			{7, 7, 8},
+			{8, 8, 8},
+		} {
+			s.Equal(tt.rank, s.tree.Rank(tt.val), "Rank(%v)", tt.val)
+			s.Equal(tt.inclusive, s.tree.RankInclusive(tt.val), "RankInclusive(%v)", tt.val)
+		}
+	})
+
+	s.Run("pass: counts many duplicates", func() {
+		s.tree.Clear()
+		for i := 0; i < 50; i++ {
+			s.tree.Add(float64(i % 5))
+		}
+		for val := 0; val < 5; val++ {
+			s.Equal(10*val, s.tree.Rank(float64(val)))
+			s.Equal(10*(val+1), s.tree.RankInclusive(float64(val)))
+		}
+	})
 }
 
 func (s *TreeSuite) TestSelect() {
@@ -166,7 +300,365 @@ func (s *TreeSuite) TestSelect() {
 	s.Nil(node)
 }
 
+func (s *TreeSuite) TestRange() {
+	s.Implements((*order.Ranger)(nil), s.tree)
+
+	s.Run("pass: includes values equal to lo and excludes values equal to hi", func() {
+		s.Equal(4, s.tree.RangeCount(1, 4))
+		s.Equal([]float64{1, 1, 2, 3}, s.tree.RangeValues(1, 4))
+
+		s.Equal(3, s.tree.RangeCount(2, 5))
+		s.Equal([]float64{2, 3, 4}, s.tree.RangeValues(2, 5))
+
+		s.Equal(1, s.tree.RangeCount(7, 8))
+		s.Equal([]float64{7}, s.tree.RangeValues(7, 8))
+	})
+
+	s.Run("pass: handles bounds that are not in the tree", func() {
+		s.Equal(3, s.tree.RangeCount(1.5, 4.5))
+		s.Equal([]float64{2, 3, 4}, s.tree.RangeValues(1.5, 4.5))
+
+		s.Equal(8, s.tree.RangeCount(0, 100))
+		s.Equal([]float64{1, 1, 2, 3, 4, 5, 6, 7}, s.tree.RangeValues(0, 100))
+	})
+
+	s.Run("pass: empty interval returns nothing", func() {
+		s.Equal(0, s.tree.RangeCount(3, 3))
+		s.Equal([]float64{}, s.tree.RangeValues(3, 3))
+
+		s.Equal(0, s.tree.RangeCount(8, 100))
+		s.Equal([]float64{}, s.tree.RangeValues(8, 100))
+	})
+
+	s.Run("pass: lo > hi returns nothing", func() {
+		s.Equal(0, s.tree.RangeCount(5, 2))
+		s.Equal([]float64{}, s.tree.RangeValues(5, 2))
+	})
+}
+
+func (s *TreeSuite) TestMinMax() {
+	s.Run("pass: returns the extreme values", func() {
+		min, ok := s.tree.Min()
+		s.True(ok)
+		s.Equal(1., min)
+
+		max, ok := s.tree.Max()
+		s.True(ok)
+		s.Equal(7., max)
+	})
+
+	s.Run("pass: returns false for an empty tree", func() {
+		s.tree.Clear()
+
+		_, ok := s.tree.Min()
+		s.False(ok)
+
+		_, ok = s.tree.Max()
+		s.False(ok)
+	})
+}
+
+func (s *TreeSuite) TestFloorCeil() {
+	s.Implements((*order.Neighbors)(nil), s.tree)
+
+	s.Run("pass: values present in the tree are excluded", func() {
+		floor, ok := s.tree.Floor(4)
+		s.True(ok)
+		s.Equal(3., floor)
+
+		ceil, ok := s.tree.Ceil(4)
+		s.True(ok)
+		s.Equal(5., ceil)
+
+		// duplicates are skipped over as well
+		ceil, ok = s.tree.Ceil(1)
+		s.True(ok)
+		s.Equal(2., ceil)
+	})
+
+	s.Run("pass: values absent from the tree", func() {
+		floor, ok := s.tree.Floor(4.5)
+		s.True(ok)
+		s.Equal(4., floor)
+
+		ceil, ok := s.tree.Ceil(4.5)
+		s.True(ok)
+		s.Equal(5., ceil)
+
+		floor, ok = s.tree.Floor(100)
+		s.True(ok)
+		s.Equal(7., floor)
+
+		ceil, ok = s.tree.Ceil(-100)
+		s.True(ok)
+		s.Equal(1., ceil)
+	})
+
+	s.Run("pass: returns false past the boundaries", func() {
+		_, ok := s.tree.Floor(1)
+		s.False(ok)
+
+		_, ok = s.tree.Floor(0)
+		s.False(ok)
+
+		_, ok = s.tree.Ceil(7)
+		s.False(ok)
+
+		_, ok = s.tree.Ceil(8)
+		s.False(ok)
+	})
+
+	s.Run("pass: returns false for an empty tree", func() {
+		s.tree.Clear()
+
+		_, ok := s.tree.Floor(4)
+		s.False(ok)
+
+		_, ok = s.tree.Ceil(4)
+		s.False(ok)
+	})
+}
+
+func (s *TreeSuite) TestMerge() {
+	s.Run("pass: merged tree holds the values of both trees", func() {
+		other := &Tree{}
+		for _, val := range []float64{0, 1, 4, 4, 8, 9} {
+			other.Add(val)
+		}
+
+		s.tree.Merge(other)
+		expected := []float64{0, 1, 1, 1, 2, 3, 4, 4, 4, 5, 6, 7, 8, 9}
+		s.Equal(len(expected), s.tree.Size())
+		for k, val := range expected {
+			s.Equal(val, s.tree.Select(k).Value())
+		}
+		s.Equal(4, s.tree.Rank(2))
+		s.Equal(6, s.tree.Rank(4))
+		s.Equal(9, s.tree.Rank(5))
+		s.Equal(3, s.tree.root.Height())
+
+		// the other tree is left unchanged
+		s.Equal(6, other.Size())
+	})
+
+	s.Run("pass: merging a tree with itself doubles every value", func() {
+		s.SetupTest()
+		s.tree.Merge(s.tree)
+		expected := []float64{1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7}
+		s.Equal(len(expected), s.tree.Size())
+		for k, val := range expected {
+			s.Equal(val, s.tree.Select(k).Value())
+		}
+	})
+
+	s.Run("pass: merging an empty tree changes nothing", func() {
+		s.SetupTest()
+		s.tree.Merge(&Tree{})
+		s.Equal(8, s.tree.Size())
+
+		empty := &Tree{}
+		empty.Merge(s.tree)
+		s.Equal(8, empty.Size())
+		s.Equal(4., empty.Select(4).Value())
+	})
+}
+
+func (s *TreeSuite) TestInOrder() {
+	s.Implements((*order.Iterable)(nil), s.tree)
+
+	s.Run("pass: visits all values in sorted order", func() {
+		vals := []float64{}
+		s.tree.InOrder(func(val float64) bool {
+			vals = append(vals, val)
+			return true
+		})
+		s.Equal([]float64{1, 1, 2, 3, 4, 5, 6, 7}, vals)
+	})
+
+	s.Run("pass: stops early if the callback returns false", func() {
+		vals := []float64{}
+		s.tree.InOrder(func(val float64) bool {
+			vals = append(vals, val)
+			return len(vals) < 3
+		})
+		s.Equal([]float64{1, 1, 2}, vals)
+	})
+
+	s.Run("pass: visits nothing in an empty tree", func() {
+		s.tree.Clear()
+		s.tree.InOrder(func(val float64) bool {
+			s.Fail("callback should not be called")
+			return true
+		})
+	})
+}
+
 func (s *TreeSuite) TestClear() {
 	s.tree.Clear()
 	s.Equal(&Tree{}, s.tree)
 }
+
+func TestBuildBalanced(t *testing.T) {
+	t.Run("pass: builds a perfectly balanced tree supporting Select and Rank", func(t *testing.T) {
+		for n := 0; n <= 100; n++ {
+			sorted := make([]float64, n)
+			for i := range sorted {
+				sorted[i] = float64(i)
+			}
+
+			tree := BuildBalanced(sorted)
+			assert.Equal(t, n, tree.Size())
+			// Height counts edges rather than levels, hence the + 1
+			assert.Equal(t, int(math.Ceil(math.Log2(float64(n+1)))), tree.Height()+1, "n = %d", n)
+			for k, val := range sorted {
+				assert.Equal(t, val, tree.Select(k).Value())
+				assert.Equal(t, k, tree.Rank(val))
+			}
+		}
+	})
+
+	t.Run("pass: built tree supports further adds and removes", func(t *testing.T) {
+		tree := BuildBalanced([]float64{1, 2, 3, 4, 5, 6, 7})
+		tree.Add(4.5)
+		tree.Remove(2)
+		for k, val := range []float64{1, 3, 4, 4.5, 5, 6, 7} {
+			assert.Equal(t, val, tree.Select(k).Value())
+		}
+	})
+
+	t.Run("pass: unsorted values are sorted without modifying the input", func(t *testing.T) {
+		vals := []float64{3, 1, 2, 1}
+		tree := BuildBalanced(vals)
+		assert.Equal(t, []float64{3, 1, 2, 1}, vals)
+		for k, val := range []float64{1, 1, 2, 3} {
+			assert.Equal(t, val, tree.Select(k).Value())
+		}
+	})
+}
+
+// randomTree builds a tree with many duplicate values by adding them one at a time.
+func randomTree(n int) *Tree {
+	rng := rand.New(rand.NewSource(0))
+	tree := &Tree{}
+	for i := 0; i < n; i++ {
+		tree.Add(float64(rng.Intn(n / 2)))
+	}
+	return tree
+}
+
+// assertSameQueries asserts that the trees give the same Size, Select and Rank results.
+func assertSameQueries(t *testing.T, expected *Tree, actual *Tree) {
+	require.Equal(t, expected.Size(), actual.Size())
+	for k := 0; k < expected.Size(); k++ {
+		assert.Equal(t, expected.Select(k).Value(), actual.Select(k).Value())
+	}
+	for val := -1.0; val <= float64(expected.Size()); val += 0.5 {
+		assert.Equal(t, expected.Rank(val), actual.Rank(val))
+		assert.Equal(t, expected.RankInclusive(val), actual.RankInclusive(val))
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("pass: round trip preserves queries", func(t *testing.T) {
+		tree := randomTree(200)
+		data, err := json.Marshal(tree)
+		require.NoError(t, err)
+
+		decoded := &Tree{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+		assertSameQueries(t, tree, decoded)
+	})
+
+	t.Run("pass: values are encoded in sorted order", func(t *testing.T) {
+		data, err := json.Marshal(BuildBalanced([]float64{3, 1, 2, 1}))
+		require.NoError(t, err)
+		assert.Equal(t, "[1,1,2,3]", string(data))
+	})
+
+	t.Run("pass: decoding replaces existing values", func(t *testing.T) {
+		tree := BuildBalanced([]float64{1, 2, 3})
+		require.NoError(t, json.Unmarshal([]byte("[5,4]"), tree))
+		assertSameQueries(t, BuildBalanced([]float64{4, 5}), tree)
+	})
+
+	t.Run("pass: empty tree round trips", func(t *testing.T) {
+		data, err := json.Marshal(&Tree{})
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(data))
+
+		decoded := BuildBalanced([]float64{1})
+		require.NoError(t, json.Unmarshal(data, decoded))
+		assert.Equal(t, 0, decoded.Size())
+	})
+
+	t.Run("fail: non-finite values cannot be encoded", func(t *testing.T) {
+		_, err := json.Marshal(BuildBalanced([]float64{1, math.Inf(1)}))
+		assert.Error(t, err)
+	})
+
+	t.Run("fail: malformed data cannot be decoded", func(t *testing.T) {
+		err := json.Unmarshal([]byte("{}"), &Tree{})
+		assert.Error(t, err)
+	})
+}
+
+func TestGob(t *testing.T) {
+	t.Run("pass: round trip preserves queries", func(t *testing.T) {
+		tree := randomTree(200)
+		tree.Add(math.Inf(1))
+		tree.Add(math.Inf(-1))
+
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(tree))
+
+		decoded := &Tree{}
+		require.NoError(t, gob.NewDecoder(&buf).Decode(decoded))
+		assertSameQueries(t, tree, decoded)
+	})
+
+	t.Run("fail: malformed data cannot be decoded", func(t *testing.T) {
+		err := (&Tree{}).GobDecode([]byte("not gob"))
+		assert.Error(t, err)
+	})
+}
+
+func TestIsBalanced(t *testing.T) {
+	t.Run("pass: tree stays balanced under random adds and removes", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(0))
+		tree := &Tree{}
+		vals := []float64{}
+		for i := 0; i < 5000; i++ {
+			if len(vals) > 0 && rng.Intn(3) == 0 {
+				j := rng.Intn(len(vals))
+				tree.Remove(vals[j])
+				vals = append(vals[:j], vals[j+1:]...)
+			} else {
+				val := float64(rng.Intn(500))
+				tree.Add(val)
+				vals = append(vals, val)
+			}
+			require.True(t, tree.IsBalanced(), "after %d operations", i+1)
+		}
+
+		// an AVL tree of n nodes has height less than 1.44 * log2(n + 2)
+		assert.Less(t, float64(tree.Height()), 1.44*math.Log2(float64(tree.Size()+2)))
+	})
+
+	t.Run("pass: empty tree is balanced", func(t *testing.T) {
+		assert.True(t, (&Tree{}).IsBalanced())
+	})
+
+	t.Run("fail: unbalanced tree is reported", func(t *testing.T) {
+		// a chain of three nodes, with consistent heights and sizes
+		leaf := &Node{val: 3, height: 0, size: 1}
+		mid := &Node{val: 2, right: leaf, height: 1, size: 2}
+		tree := &Tree{root: &Node{val: 1, right: mid, height: 2, size: 3}}
+		assert.False(t, tree.IsBalanced())
+	})
+
+	t.Run("fail: inconsistent stored height is reported", func(t *testing.T) {
+		tree := BuildBalanced([]float64{1, 2, 3})
+		tree.root.height = 5
+		assert.False(t, tree.IsBalanced())
+	})
+}
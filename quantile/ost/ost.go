@@ -11,8 +11,12 @@ type Tree interface {
 	Size() int
 	Add(float64)
 	Remove(float64)
+	Delete(float64) bool
 	Select(int) order.Node
 	Rank(float64) int
+	RankInclusive(float64) int
+	Min() (float64, bool)
+	Max() (float64, bool)
 	String() string
 	Clear()
 }
@@ -25,5 +29,6 @@ type Node interface {
 	Value() float64
 	Select(int) order.Node
 	Rank(float64) int
+	RankInclusive(float64) int
 	TreeString() string
 }
@@ -2,6 +2,7 @@ package rb
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/pkg/errors"
 
@@ -73,6 +74,47 @@ func (n *Node) Value() float64 {
 	return n.val
 }
 
+// height returns the height of the subtree rooted at the node,
+// i.e. the number of edges on its longest path from the node to a leaf.
+func (n *Node) height() int {
+	if n == nil {
+		return -1
+	}
+
+	left, right := n.left.height(), n.right.height()
+	if left > right {
+		return left + 1
+	}
+	return right + 1
+}
+
+// blackHeight returns the black height of the subtree rooted at the node, or -1 if
+// the subtree is not a valid left-leaning red-black tree, i.e. if it has a red right
+// child, consecutive red nodes (the root itself may be red), unequal black heights,
+// or a size stored at a node that is inconsistent with its children.
+func (n *Node) blackHeight(isRoot bool) int {
+	if n == nil {
+		return 0
+	}
+
+	if n.right.Color() == Red ||
+		(n.Color() == Red && !isRoot && n.left.Color() == Red) ||
+		n.size != n.left.Size()+n.right.Size()+1 {
+		return -1
+	}
+
+	left := n.left.blackHeight(false)
+	right := n.right.blackHeight(false)
+	if left < 0 || left != right {
+		return -1
+	}
+
+	if n.Color() == Black {
+		return left + 1
+	}
+	return left
+}
+
 // Color returns the color of the node.
 // By default, nil nodes are black.
 func (n *Node) Color() Color {
@@ -101,38 +143,109 @@ func (n *Node) add(val float64) *Node {
 	return n.addBalance()
 }
 
+// remove removes a node with the given value from the subtree rooted at the node,
+// if there is one. With duplicate values, the rotations on the way down can lift a
+// node equal to the value above the one being removed; comparing values would then
+// remove the lifted node and drop its right subtree. Locating the node by its rank
+// (that of the first copy of the value) avoids this.
 func (n *Node) remove(val float64) *Node {
-	if !n.contains(val) {
+	k := n.Rank(val)
+	if k == n.Size() || n.Select(k).Value() != val {
 		return n
 	}
+	return n.removeAt(k)
+}
 
-	if val < n.val {
+// removeAt removes the node with the kth smallest value in the subtree rooted at
+// the node; unlike remove, this locates the node by its rank rather than its value.
+// The rotations on the way down preserve the set of values in the subtree, so the
+// rank is recomputed relative to the (possibly new) root after each of them.
+func (n *Node) removeAt(k int) *Node {
+	if k < n.left.Size() {
 		if n.left.Color() == Black && n.left.left.Color() == Black {
 			n = n.moveRedLeft()
 		}
-		n.left = n.left.remove(val)
+		n.left = n.left.removeAt(k)
 	} else {
 		if n.left.Color() == Red {
 			n = n.rotateRight()
 		}
-		if val == n.val && n.right == nil {
+		if k == n.left.Size() && n.right == nil {
 			return nil
 		}
 		if n.right.Color() == Black && n.right.left.Color() == Black {
 			n = n.moveRedRight()
 		}
-		if val == n.val {
+		if size := n.left.Size(); k == size {
 			x := n.right.min()
 			n.val = x.val
 			n.right = n.right.removeMin()
 		} else {
-			n.right = n.right.remove(val)
+			n.right = n.right.removeAt(k - size - 1)
 		}
 	}
 
 	return n.removeBalance()
 }
 
+// build recursively builds a left-leaning red-black subtree with the given
+// black height from sorted values. The subtree is rooted at a black node with
+// two black-rooted children (i.e. a 2-node) if the values fit, and otherwise at a
+// black node with a red left child and three black-rooted subtrees (i.e. a 3-node).
+// A subtree with black height b holds between 2^b - 1 and 3^b - 1 values, so
+// the number of values must lie in that range.
+func build(sorted []float64, black int) *Node {
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	var n *Node
+	if len(sorted)-1 <= 2*maxSize(black-1) {
+		mid := (len(sorted) - 1) / 2
+		n = NewNode(sorted[mid])
+		n.left = build(sorted[:mid], black-1)
+		n.right = build(sorted[mid+1:], black-1)
+	} else {
+		// split the remaining values as evenly as possible
+		// into the three subtrees of the 3-node
+		k, rem := (len(sorted)-2)/3, (len(sorted)-2)%3
+		a, b := k, k
+		if rem > 0 {
+			a++
+		}
+		if rem > 1 {
+			b++
+		}
+
+		red := NewNode(sorted[a])
+		red.left = build(sorted[:a], black-1)
+		red.right = build(sorted[a+1:a+1+b], black-1)
+		red.size = red.left.Size() + red.right.Size() + 1
+
+		n = NewNode(sorted[a+1+b])
+		n.left = red
+		n.right = build(sorted[a+2+b:], black-1)
+	}
+
+	n.color = Black
+	n.size = n.left.Size() + n.right.Size() + 1
+	return n
+}
+
+// maxSize returns the maximum number of values a left-leaning red-black
+// subtree with the given black height can hold, i.e. 3^black - 1,
+// saturating at math.MaxInt.
+func maxSize(black int) int {
+	size := 1
+	for i := 0; i < black; i++ {
+		if size > math.MaxInt/3 {
+			return math.MaxInt
+		}
+		size *= 3
+	}
+	return size - 1
+}
+
 func (n *Node) removeMin() *Node {
 	if n.left == nil {
 		return nil
@@ -152,17 +265,11 @@ func (n *Node) min() *Node {
 	return n.left.min()
 }
 
-func (n *Node) contains(val float64) bool {
-	for n != nil {
-		if val == n.val {
-			return true
-		} else if val < n.val {
-			n = n.left
-		} else {
-			n = n.right
-		}
+func (n *Node) max() *Node {
+	if n.right == nil {
+		return n
 	}
-	return false
+	return n.right.max()
 }
 
 /*****************
@@ -272,12 +379,85 @@ func (n *Node) Select(k int) order.Node {
 func (n *Node) Rank(val float64) int {
 	if n == nil {
 		return 0
-	} else if val < n.val {
-		return n.left.Rank(val)
 	} else if val > n.val {
 		return 1 + n.left.Size() + n.right.Rank(val)
 	}
-	return n.left.Size()
+	// values equal to the node's may also be stored in its left
+	// subtree, so they must be excluded from the count as well
+	return n.left.Rank(val)
+}
+
+// RankInclusive returns the number of nodes less than or equal to the value
+// that are contained in the subtree rooted at the node.
+func (n *Node) RankInclusive(val float64) int {
+	if n == nil {
+		return 0
+	} else if val >= n.val {
+		// values equal to the node's may also be stored in its right
+		// subtree after rotations, so it must be searched as well
+		return 1 + n.left.Size() + n.right.RankInclusive(val)
+	}
+	return n.left.RankInclusive(val)
+}
+
+// inOrder calls f on each value in the subtree rooted at the node in sorted order,
+// stopping early if f returns false; it returns whether the traversal completed.
+func (n *Node) inOrder(f func(float64) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.inOrder(f) && f(n.val) && n.right.inOrder(f)
+}
+
+// rangeValues appends the values in the subtree rooted at the node that lie
+// in the interval [lo, hi) to vals in sorted order, skipping any subtrees that
+// lie outside of the interval.
+func (n *Node) rangeValues(lo float64, hi float64, vals []float64) []float64 {
+	if n == nil {
+		return vals
+	}
+	if lo <= n.val {
+		vals = n.left.rangeValues(lo, hi, vals)
+	}
+	if lo <= n.val && n.val < hi {
+		vals = append(vals, n.val)
+	}
+	if n.val < hi {
+		vals = n.right.rangeValues(lo, hi, vals)
+	}
+	return vals
+}
+
+// floor returns the node with the largest value strictly less than val in the
+// subtree rooted at the node, or nil if there is none. It descends from the
+// node to a leaf once, keeping track of the best candidate seen so far.
+func (n *Node) floor(val float64) *Node {
+	var candidate *Node
+	for n != nil {
+		if n.val < val {
+			candidate = n
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return candidate
+}
+
+// ceil returns the node with the smallest value strictly greater than val in the
+// subtree rooted at the node, or nil if there is none. It descends from the
+// node to a leaf once, keeping track of the best candidate seen so far.
+func (n *Node) ceil(val float64) *Node {
+	var candidate *Node
+	for n != nil {
+		if n.val > val {
+			candidate = n
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return candidate
 }
 
 /*******************
@@ -1,27 +1,102 @@
 package rb
 
-import "github.com/K4Mobility/stream/quantile/order"
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/K4Mobility/stream/quantile/order"
+)
 
 // Tree implements a red-black tree data structure,
 // and also satisfies the st.Tree interface,
 // as well as the order.Statistic interface.
+// It is not safe for concurrent use; Quantile serializes access to it.
 type Tree struct {
 	root *Node
 }
 
+// BuildBalanced builds a balanced tree from a slice of values in O(n) time,
+// rather than the O(n log n) time taken by adding them one at a time. The
+// values should be sorted; if they are not, a sorted copy of them is used instead.
+func BuildBalanced(sorted []float64) *Tree {
+	if !sort.Float64sAreSorted(sorted) {
+		sorted = append([]float64{}, sorted...)
+		sort.Float64s(sorted)
+	}
+
+	// the largest black height whose perfectly balanced (all black)
+	// tree has no more nodes than there are values
+	black := int(math.Floor(math.Log2(float64(len(sorted) + 1))))
+	return &Tree{root: build(sorted, black)}
+}
+
+// Merge adds all of the values of other to the tree, preserving duplicates.
+// Rather than adding the values one at a time, it merges the sorted values of
+// both in O(n + m) time and rebuilds the tree, as in BuildBalanced.
+func (t *Tree) Merge(other order.Statistic) {
+	theirs := sortedValues(other)
+	merged := make([]float64, 0, t.Size()+len(theirs))
+	i := 0
+	t.InOrder(func(val float64) bool {
+		for ; i < len(theirs) && theirs[i] < val; i++ {
+			merged = append(merged, theirs[i])
+		}
+		merged = append(merged, val)
+		return true
+	})
+	merged = append(merged, theirs[i:]...)
+	t.root = BuildBalanced(merged).root
+}
+
 // Size returns the size of the tree.
 func (t *Tree) Size() int {
 	return t.root.Size()
 }
 
+// Height returns the height of the tree. Unlike in the AVL tree, heights are
+// not stored at the nodes, so this walks the whole tree in O(n) time.
+func (t *Tree) Height() int {
+	return t.root.height()
+}
+
+// IsBalanced returns whether the tree is a valid left-leaning red-black tree,
+// by walking the whole tree in O(n) time; it should always return true, and is
+// intended as a diagnostic.
+func (t *Tree) IsBalanced() bool {
+	return t.root.blackHeight(true) >= 0
+}
+
 // Add inserts a value into the tree.
 func (t *Tree) Add(val float64) {
 	t.root = t.root.add(val)
 }
 
-// Remove deletes a value from the tree.
+// Remove deletes a value from the tree, if it is present.
 func (t *Tree) Remove(val float64) {
+	t.Delete(val)
+}
+
+// Delete deletes a value from the tree, and returns whether
+// the value was present (and thus actually deleted).
+func (t *Tree) Delete(val float64) bool {
+	size := t.root.Size()
 	t.root = t.root.remove(val)
+	return t.root.Size() < size
+}
+
+// RemoveAt deletes the node with the kth smallest value in the tree, i.e. the
+// node returned by Select(k), if 0 <= k < Size(). Unlike Remove, this does not
+// need to search for the node by its value.
+func (t *Tree) RemoveAt(k int) {
+	if k < 0 || k >= t.root.Size() {
+		return
+	}
+	t.root = t.root.removeAt(k)
 }
 
 // Select returns the node with the kth smallest value in the tree.
@@ -34,6 +109,74 @@ func (t *Tree) Rank(val float64) int {
 	return t.root.Rank(val)
 }
 
+// RankInclusive returns the number of nodes less than or equal to the value,
+// i.e. Rank(val) plus the number of nodes equal to the value.
+func (t *Tree) RankInclusive(val float64) int {
+	return t.root.RankInclusive(val)
+}
+
+// Min returns the smallest value in the tree, or false if the tree is empty.
+func (t *Tree) Min() (float64, bool) {
+	if t.root == nil {
+		return 0, false
+	}
+	return t.root.min().val, true
+}
+
+// Max returns the largest value in the tree, or false if the tree is empty.
+func (t *Tree) Max() (float64, bool) {
+	if t.root == nil {
+		return 0, false
+	}
+	return t.root.max().val, true
+}
+
+// RangeCount returns the number of values in the tree that lie in
+// the interval [lo, hi), or 0 if lo > hi.
+func (t *Tree) RangeCount(lo float64, hi float64) int {
+	if lo > hi {
+		return 0
+	}
+	return t.root.Rank(hi) - t.root.Rank(lo)
+}
+
+// RangeValues returns the values in the tree that lie in the interval
+// [lo, hi) in sorted order, or an empty slice if lo > hi. This takes
+// O(k + log n) time, where k is the number of values returned.
+func (t *Tree) RangeValues(lo float64, hi float64) []float64 {
+	vals := []float64{}
+	if lo > hi {
+		return vals
+	}
+	return t.root.rangeValues(lo, hi, vals)
+}
+
+// Floor returns the largest value in the tree strictly less than val,
+// or false if there is no such value.
+func (t *Tree) Floor(val float64) (float64, bool) {
+	n := t.root.floor(val)
+	if n == nil {
+		return 0, false
+	}
+	return n.val, true
+}
+
+// Ceil returns the smallest value in the tree strictly greater than val,
+// or false if there is no such value.
+func (t *Tree) Ceil(val float64) (float64, bool) {
+	n := t.root.ceil(val)
+	if n == nil {
+		return 0, false
+	}
+	return n.val, true
+}
+
+// InOrder calls f on each value in the tree in sorted order, stopping early
+// if f returns false. This takes O(n) time to visit all n values.
+func (t *Tree) InOrder(f func(float64) bool) {
+	t.root.inOrder(f)
+}
+
 // String returns the string representation of the tree.
 func (t *Tree) String() string {
 	return t.root.TreeString()
@@ -43,3 +186,61 @@ func (t *Tree) String() string {
 func (t *Tree) Clear() {
 	*t = Tree{}
 }
+
+// MarshalJSON encodes the tree as a JSON array of its values in sorted order;
+// this fails if the tree holds any non-finite values, which JSON cannot represent.
+func (t *Tree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sortedValues(t))
+}
+
+// UnmarshalJSON decodes a JSON array of values into the tree, replacing its
+// contents; the tree is rebuilt from the values as in BuildBalanced.
+func (t *Tree) UnmarshalJSON(data []byte) error {
+	var vals []float64
+	err := json.Unmarshal(data, &vals)
+	if err != nil {
+		return errors.Wrap(err, "error decoding tree values")
+	}
+	t.root = BuildBalanced(vals).root
+	return nil
+}
+
+// GobEncode encodes the values of the tree in sorted order; unlike
+// MarshalJSON, this supports non-finite values.
+func (t *Tree) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(sortedValues(t))
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding tree values")
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes values encoded by GobEncode into the tree, replacing its
+// contents; the tree is rebuilt from the values as in BuildBalanced.
+func (t *Tree) GobDecode(data []byte) error {
+	var vals []float64
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&vals)
+	if err != nil {
+		return errors.Wrap(err, "error decoding tree values")
+	}
+	t.root = BuildBalanced(vals).root
+	return nil
+}
+
+// sortedValues returns the values of the order.Statistic in sorted order.
+func sortedValues(s order.Statistic) []float64 {
+	vals := make([]float64, 0, s.Size())
+	if it, ok := s.(order.Iterable); ok {
+		it.InOrder(func(val float64) bool {
+			vals = append(vals, val)
+			return true
+		})
+		return vals
+	}
+
+	for k := 0; k < s.Size(); k++ {
+		vals = append(vals, s.Select(k).Value())
+	}
+	return vals
+}
@@ -1,10 +1,20 @@
 package rb
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/K4Mobility/stream/quantile/order"
 )
 
 type TreeSuite struct {
@@ -146,6 +156,120 @@ func (s *TreeSuite) TestRemove() {
 			s.tree.String(),
 		)
 	})
+
+	s.Run("pass: removing duplicates keeps the tree balanced", func() {
+		tree := &Tree{}
+		var vals []float64
+		for i := 0; i < 60; i++ {
+			val := float64(i * 7 % 3)
+			vals = append(vals, val)
+			tree.Add(val)
+		}
+
+		sort.Float64s(vals)
+		for i := 0; i < 60; i++ {
+			// remove from alternating ends of the remaining values
+			var val float64
+			if i%2 == 0 {
+				val, vals = vals[0], vals[1:]
+			} else {
+				val, vals = vals[len(vals)-1], vals[:len(vals)-1]
+			}
+			s.True(tree.Delete(val))
+			checkLLRB(s.T(), tree.root)
+
+			var got []float64
+			tree.InOrder(func(val float64) bool {
+				got = append(got, val)
+				return true
+			})
+			s.Equal(vals, append([]float64{}, got...))
+		}
+	})
+}
+
+func (s *TreeSuite) TestDelete() {
+	s.Run("pass: deleting a present value returns true", func() {
+		s.SetupTest()
+		s.True(s.tree.Delete(5))
+		s.Equal(7, s.tree.Size())
+		s.Nil(s.tree.Select(7))
+	})
+
+	s.Run("pass: deleting an absent value returns false", func() {
+		s.SetupTest()
+		s.False(s.tree.Delete(100))
+		s.False(s.tree.Delete(4.5))
+		s.Equal(8, s.tree.Size())
+	})
+
+	s.Run("pass: each duplicate is deleted once", func() {
+		s.SetupTest()
+		s.True(s.tree.Delete(1))
+		s.True(s.tree.Delete(1))
+		s.False(s.tree.Delete(1))
+		s.Equal(6, s.tree.Size())
+	})
+
+	s.Run("pass: deleting from an empty tree returns false", func() {
+		s.tree.Clear()
+		s.False(s.tree.Delete(1))
+		s.Equal(0, s.tree.Size())
+	})
+}
+
+func (s *TreeSuite) TestRemoveAt() {
+	s.Run("pass: removes the node of the given rank among duplicates", func() {
+		s.SetupTest()
+		s.tree.Add(5)
+		s.tree.Add(1)
+
+		s.tree.RemoveAt(1)
+		s.tree.RemoveAt(5)
+		s.tree.RemoveAt(7)
+		s.Equal(7, s.tree.Size())
+
+		vals := []float64{}
+		s.tree.InOrder(func(val float64) bool {
+			vals = append(vals, val)
+			return true
+		})
+		s.Equal([]float64{1, 1, 2, 3, 4, 5, 6}, vals)
+	})
+
+	s.Run("pass: out of range ranks are a no-op", func() {
+		s.SetupTest()
+		s.tree.RemoveAt(-1)
+		s.tree.RemoveAt(8)
+		s.Equal(8, s.tree.Size())
+
+		s.tree.Clear()
+		s.tree.RemoveAt(0)
+		s.Equal(0, s.tree.Size())
+	})
+
+	s.Run("pass: keeps the tree balanced and the remaining multiset intact", func() {
+		s.tree.Clear()
+		vals := []float64{}
+		for i := 0; i < 300; i++ {
+			val := float64((i * 37) % 50)
+			s.tree.Add(val)
+			vals = append(vals, val)
+		}
+		sort.Float64s(vals)
+
+		for i := 0; len(vals) > 0; i++ {
+			k := (i * 53) % len(vals)
+			s.tree.RemoveAt(k)
+			vals = append(vals[:k], vals[k+1:]...)
+
+			s.Equal(len(vals), s.tree.Size())
+			checkLLRB(s.T(), s.tree.root)
+			for j, val := range vals {
+				s.Equal(val, s.tree.Select(j).Value())
+			}
+		}
+	})
 }
 
 func (s *TreeSuite) TestRank() {
@@ -157,6 +281,46 @@ func (s *TreeSuite) TestRank() {
 
 	rank = s.tree.Rank(-1)
 	s.Equal(0, rank)
+
+	// duplicates are not counted as strictly less than each other
+	rank = s.tree.Rank(1)
+	s.Equal(0, rank)
+
+	rank = s.tree.Rank(2)
+	s.Equal(2, rank)
+}
+
+func (s *TreeSuite) TestRankInclusive() {
+	s.Run("pass: counts duplicates of the value", func() {
+		// the values are 1, 1, 2, 3, 4, 5, 6, 7
+		for _, tt := range []struct {
+			val       float64
+			rank      int
+			inclusive int
+		}{
+			{-1, 0, 0},
+			{1, 0, 2},
+			{1.5, 2, 2},
+			{2, 2, 3},
+			{5.5, 6, 6},
+			{7, 7, 8},
+			{8, 8, 8},
+		} {
+			s.Equal(tt.rank, s.tree.Rank(tt.val), "Rank(%v)", tt.val)
+			s.Equal(tt.inclusive, s.tree.RankInclusive(tt.val), "RankInclusive(%v)", tt.val)
+		}
+	})
+
+	s.Run("pass: counts many duplicates", func() {
+		s.tree.Clear()
+		for i := 0; i < 50; i++ {
+			s.tree.Add(float64(i % 5))
+		}
+		for val := 0; val < 5; val++ {
+			s.Equal(10*val, s.tree.Rank(float64(val)))
+			s.Equal(10*(val+1), s.tree.RankInclusive(float64(val)))
+		}
+	})
 }
 
 func (s *TreeSuite) TestSelect() {
@@ -170,7 +334,407 @@ func (s *TreeSuite) TestSelect() {
 	s.Nil(node)
 }
 
+func (s *TreeSuite) TestRange() {
+	s.Implements((*order.Ranger)(nil), s.tree)
+
+	s.Run("pass: includes values equal to lo and excludes values equal to hi", func() {
+		s.Equal(4, s.tree.RangeCount(1, 4))
+		s.Equal([]float64{1, 1, 2, 3}, s.tree.RangeValues(1, 4))
+
+		s.Equal(3, s.tree.RangeCount(2, 5))
+		s.Equal([]float64{2, 3, 4}, s.tree.RangeValues(2, 5))
+
+		s.Equal(1, s.tree.RangeCount(7, 8))
+		s.Equal([]float64{7}, s.tree.RangeValues(7, 8))
+	})
+
+	s.Run("pass: handles bounds that are not in the tree", func() {
+		s.Equal(3, s.tree.RangeCount(1.5, 4.5))
+		s.Equal([]float64{2, 3, 4}, s.tree.RangeValues(1.5, 4.5))
+
+		s.Equal(8, s.tree.RangeCount(0, 100))
+		s.Equal([]float64{1, 1, 2, 3, 4, 5, 6, 7}, s.tree.RangeValues(0, 100))
+	})
+
+	s.Run("pass: empty interval returns nothing", func() {
+		s.Equal(0, s.tree.RangeCount(3, 3))
+		s.Equal([]float64{}, s.tree.RangeValues(3, 3))
+
+		s.Equal(0, s.tree.RangeCount(8, 100))
+		s.Equal([]float64{}, s.tree.RangeValues(8, 100))
+	})
+
+	s.Run("pass: lo > hi returns nothing", func() {
+		s.Equal(0, s.tree.RangeCount(5, 2))
+		s.Equal([]float64{}, s.tree.RangeValues(5, 2))
+	})
+}
+
+func (s *TreeSuite) TestMinMax() {
+	s.Run("pass: returns the extreme values", func() {
+		min, ok := s.tree.Min()
+		s.True(ok)
+		s.Equal(1., min)
+
+		max, ok := s.tree.Max()
+		s.True(ok)
+		s.Equal(7., max)
+	})
+
+	s.Run("pass: returns false for an empty tree", func() {
+		s.tree.Clear()
+
+		_, ok := s.tree.Min()
+		s.False(ok)
+
+		_, ok = s.tree.Max()
+		s.False(ok)
+	})
+}
+
+func (s *TreeSuite) TestFloorCeil() {
+	s.Implements((*order.Neighbors)(nil), s.tree)
+
+	s.Run("pass: values present in the tree are excluded", func() {
+		floor, ok := s.tree.Floor(4)
+		s.True(ok)
+		s.Equal(3., floor)
+
+		ceil, ok := s.tree.Ceil(4)
+		s.True(ok)
+		s.Equal(5., ceil)
+
+		// duplicates are skipped over as well
+		ceil, ok = s.tree.Ceil(1)
+		s.True(ok)
+		s.Equal(2., ceil)
+	})
+
+	s.Run("pass: values absent from the tree", func() {
+		floor, ok := s.tree.Floor(4.5)
+		s.True(ok)
+		s.Equal(4., floor)
+
+		ceil, ok := s.tree.Ceil(4.5)
+		s.True(ok)
+		s.Equal(5., ceil)
+
+		floor, ok = s.tree.Floor(100)
+		s.True(ok)
+		s.Equal(7., floor)
+
+		ceil, ok = s.tree.Ceil(-100)
+		s.True(ok)
+		s.Equal(1., ceil)
+	})
+
+	s.Run("pass: returns false past the boundaries", func() {
+		_, ok := s.tree.Floor(1)
+		s.False(ok)
+
+		_, ok = s.tree.Floor(0)
+		s.False(ok)
+
+		_, ok = s.tree.Ceil(7)
+		s.False(ok)
+
+		_, ok = s.tree.Ceil(8)
+		s.False(ok)
+	})
+
+	s.Run("pass: returns false for an empty tree", func() {
+		s.tree.Clear()
+
+		_, ok := s.tree.Floor(4)
+		s.False(ok)
+
+		_, ok = s.tree.Ceil(4)
+		s.False(ok)
+	})
+}
+
+func (s *TreeSuite) TestMerge() {
+	s.Run("pass: merged tree holds the values of both trees", func() {
+		other := &Tree{}
+		for _, val := range []float64{0, 1, 4, 4, 8, 9} {
+			other.Add(val)
+		}
+
+		s.tree.Merge(other)
+		expected := []float64{0, 1, 1, 1, 2, 3, 4, 4, 4, 5, 6, 7, 8, 9}
+		s.Equal(len(expected), s.tree.Size())
+		for k, val := range expected {
+			s.Equal(val, s.tree.Select(k).Value())
+		}
+		s.Equal(4, s.tree.Rank(2))
+		s.Equal(6, s.tree.Rank(4))
+		s.Equal(9, s.tree.Rank(5))
+		checkLLRB(s.T(), s.tree.root)
+
+		// the other tree is left unchanged
+		s.Equal(6, other.Size())
+	})
+
+	s.Run("pass: merging a tree with itself doubles every value", func() {
+		s.SetupTest()
+		s.tree.Merge(s.tree)
+		expected := []float64{1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7}
+		s.Equal(len(expected), s.tree.Size())
+		for k, val := range expected {
+			s.Equal(val, s.tree.Select(k).Value())
+		}
+	})
+
+	s.Run("pass: merging an empty tree changes nothing", func() {
+		s.SetupTest()
+		s.tree.Merge(&Tree{})
+		s.Equal(8, s.tree.Size())
+
+		empty := &Tree{}
+		empty.Merge(s.tree)
+		s.Equal(8, empty.Size())
+		s.Equal(4., empty.Select(4).Value())
+	})
+}
+
+func (s *TreeSuite) TestInOrder() {
+	s.Implements((*order.Iterable)(nil), s.tree)
+
+	s.Run("pass: visits all values in sorted order", func() {
+		vals := []float64{}
+		s.tree.InOrder(func(val float64) bool {
+			vals = append(vals, val)
+			return true
+		})
+		s.Equal([]float64{1, 1, 2, 3, 4, 5, 6, 7}, vals)
+	})
+
+	s.Run("pass: stops early if the callback returns false", func() {
+		vals := []float64{}
+		s.tree.InOrder(func(val float64) bool {
+			vals = append(vals, val)
+			return len(vals) < 3
+		})
+		s.Equal([]float64{1, 1, 2}, vals)
+	})
+
+	s.Run("pass: visits nothing in an empty tree", func() {
+		s.tree.Clear()
+		s.tree.InOrder(func(val float64) bool {
+			s.Fail("callback should not be called")
+			return true
+		})
+	})
+}
+
 func (s *TreeSuite) TestClear() {
 	s.tree.Clear()
 	s.Equal(&Tree{}, s.tree)
 }
+
+// checkLLRB returns the black height of the tree rooted at the node, and fails
+// the test if the tree is not a valid left-leaning red-black tree. The color of
+// the root itself is not checked, since Add and Remove may leave the root red.
+func checkLLRB(t *testing.T, root *Node) int {
+	return checkSubtree(t, root, true)
+}
+
+func checkSubtree(t *testing.T, n *Node, isRoot bool) int {
+	if n == nil {
+		return 0
+	}
+
+	assert.NotEqual(t, Red, n.right.Color(), "red right child of %v", n.val)
+	if n.Color() == Red && !isRoot {
+		assert.NotEqual(t, Red, n.left.Color(), "consecutive red nodes at %v", n.val)
+	}
+	assert.Equal(t, n.left.Size()+n.right.Size()+1, n.Size())
+
+	left := checkSubtree(t, n.left, false)
+	right := checkSubtree(t, n.right, false)
+	assert.Equal(t, left, right, "unequal black heights below %v", n.val)
+	if n.Color() == Black {
+		return left + 1
+	}
+	return left
+}
+
+func TestBuildBalanced(t *testing.T) {
+	t.Run("pass: builds a valid red-black tree supporting Select and Rank", func(t *testing.T) {
+		for n := 0; n <= 100; n++ {
+			sorted := make([]float64, n)
+			for i := range sorted {
+				sorted[i] = float64(i)
+			}
+
+			tree := BuildBalanced(sorted)
+			assert.Equal(t, n, tree.Size())
+			assert.Equal(t, Black, tree.root.Color())
+			assert.Equal(t, int(math.Floor(math.Log2(float64(n+1)))), checkLLRB(t, tree.root), "n = %d", n)
+			for k, val := range sorted {
+				assert.Equal(t, val, tree.Select(k).Value())
+				assert.Equal(t, k, tree.Rank(val))
+			}
+		}
+	})
+
+	t.Run("pass: built tree supports further adds and removes", func(t *testing.T) {
+		tree := BuildBalanced([]float64{1, 2, 3, 4, 5, 6, 7, 8})
+		tree.Add(4.5)
+		tree.Remove(2)
+		tree.Remove(7)
+		checkLLRB(t, tree.root)
+		for k, val := range []float64{1, 3, 4, 4.5, 5, 6, 8} {
+			assert.Equal(t, val, tree.Select(k).Value())
+		}
+	})
+
+	t.Run("pass: unsorted values are sorted without modifying the input", func(t *testing.T) {
+		vals := []float64{3, 1, 2, 1}
+		tree := BuildBalanced(vals)
+		assert.Equal(t, []float64{3, 1, 2, 1}, vals)
+		for k, val := range []float64{1, 1, 2, 3} {
+			assert.Equal(t, val, tree.Select(k).Value())
+		}
+	})
+}
+
+// randomTree builds a tree with many duplicate values by adding them one at a time.
+func randomTree(n int) *Tree {
+	rng := rand.New(rand.NewSource(0))
+	tree := &Tree{}
+	for i := 0; i < n; i++ {
+		tree.Add(float64(rng.Intn(n / 2)))
+	}
+	return tree
+}
+
+// assertSameQueries asserts that the trees give the same Size, Select and Rank results.
+func assertSameQueries(t *testing.T, expected *Tree, actual *Tree) {
+	require.Equal(t, expected.Size(), actual.Size())
+	for k := 0; k < expected.Size(); k++ {
+		assert.Equal(t, expected.Select(k).Value(), actual.Select(k).Value())
+	}
+	for val := -1.0; val <= float64(expected.Size()); val += 0.5 {
+		assert.Equal(t, expected.Rank(val), actual.Rank(val))
+		assert.Equal(t, expected.RankInclusive(val), actual.RankInclusive(val))
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("pass: round trip preserves queries", func(t *testing.T) {
+		tree := randomTree(200)
+		data, err := json.Marshal(tree)
+		require.NoError(t, err)
+
+		decoded := &Tree{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+		checkLLRB(t, decoded.root)
+		assertSameQueries(t, tree, decoded)
+	})
+
+	t.Run("pass: values are encoded in sorted order", func(t *testing.T) {
+		data, err := json.Marshal(BuildBalanced([]float64{3, 1, 2, 1}))
+		require.NoError(t, err)
+		assert.Equal(t, "[1,1,2,3]", string(data))
+	})
+
+	t.Run("pass: decoding replaces existing values", func(t *testing.T) {
+		tree := BuildBalanced([]float64{1, 2, 3})
+		require.NoError(t, json.Unmarshal([]byte("[5,4]"), tree))
+		assertSameQueries(t, BuildBalanced([]float64{4, 5}), tree)
+	})
+
+	t.Run("pass: empty tree round trips", func(t *testing.T) {
+		data, err := json.Marshal(&Tree{})
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(data))
+
+		decoded := BuildBalanced([]float64{1})
+		require.NoError(t, json.Unmarshal(data, decoded))
+		assert.Equal(t, 0, decoded.Size())
+	})
+
+	t.Run("fail: non-finite values cannot be encoded", func(t *testing.T) {
+		_, err := json.Marshal(BuildBalanced([]float64{1, math.Inf(1)}))
+		assert.Error(t, err)
+	})
+
+	t.Run("fail: malformed data cannot be decoded", func(t *testing.T) {
+		err := json.Unmarshal([]byte("{}"), &Tree{})
+		assert.Error(t, err)
+	})
+}
+
+func TestGob(t *testing.T) {
+	t.Run("pass: round trip preserves queries", func(t *testing.T) {
+		tree := randomTree(200)
+		tree.Add(math.Inf(1))
+		tree.Add(math.Inf(-1))
+
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(tree))
+
+		decoded := &Tree{}
+		require.NoError(t, gob.NewDecoder(&buf).Decode(decoded))
+		checkLLRB(t, decoded.root)
+		assertSameQueries(t, tree, decoded)
+	})
+
+	t.Run("fail: malformed data cannot be decoded", func(t *testing.T) {
+		err := (&Tree{}).GobDecode([]byte("not gob"))
+		assert.Error(t, err)
+	})
+}
+
+func TestHeight(t *testing.T) {
+	assert.Equal(t, -1, (&Tree{}).Height())
+	assert.Equal(t, 0, BuildBalanced([]float64{1}).Height())
+	assert.Equal(t, 2, BuildBalanced([]float64{1, 2, 3, 4, 5, 6, 7}).Height())
+}
+
+func TestIsBalanced(t *testing.T) {
+	t.Run("pass: tree stays balanced under random adds and removes", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(0))
+		tree := &Tree{}
+		vals := []float64{}
+		for i := 0; i < 5000; i++ {
+			if len(vals) > 0 && rng.Intn(3) == 0 {
+				j := rng.Intn(len(vals))
+				tree.Remove(vals[j])
+				vals = append(vals[:j], vals[j+1:]...)
+			} else {
+				val := float64(rng.Intn(500))
+				tree.Add(val)
+				vals = append(vals, val)
+			}
+			require.True(t, tree.IsBalanced(), "after %d operations", i+1)
+		}
+
+		// a red-black tree of n nodes has height at most 2 * log2(n + 1)
+		assert.LessOrEqual(t, float64(tree.Height()), 2*math.Log2(float64(tree.Size()+1)))
+	})
+
+	t.Run("pass: empty tree is balanced", func(t *testing.T) {
+		assert.True(t, (&Tree{}).IsBalanced())
+	})
+
+	t.Run("fail: unequal black heights are reported", func(t *testing.T) {
+		leaf := &Node{val: 1, color: Black, size: 1}
+		tree := &Tree{root: &Node{val: 2, left: leaf, color: Black, size: 2}}
+		assert.False(t, tree.IsBalanced())
+	})
+
+	t.Run("fail: red right child is reported", func(t *testing.T) {
+		tree := BuildBalanced([]float64{1, 2, 3})
+		tree.root.right.color = Red
+		assert.False(t, tree.IsBalanced())
+	})
+
+	t.Run("fail: consecutive red nodes are reported", func(t *testing.T) {
+		leaf := &Node{val: 1, color: Red, size: 1}
+		mid := &Node{val: 2, left: leaf, color: Red, size: 2}
+		tree := &Tree{root: &Node{val: 3, left: mid, color: Black, size: 3}}
+		assert.False(t, tree.IsBalanced())
+	})
+}
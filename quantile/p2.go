@@ -0,0 +1,155 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// P2 keeps track of a fixed quantile of a stream with the P² algorithm of Jain and
+// Chlamtac, using five markers and constant memory. The markers track the minimum,
+// the φ/2-, φ- and (1+φ)/2-quantiles, and the maximum; after each value, the middle
+// markers whose positions have drifted from their desired positions are moved by one,
+// with their heights adjusted by piecewise-parabolic interpolation. Until five values
+// have been seen, the value is computed exactly, matching Quantile with Linear
+// interpolation.
+type P2 struct {
+	phi       float64
+	heights   [5]float64
+	positions [5]float64
+	desired   [5]float64
+	// increments of the desired positions for each new value
+	increments [5]float64
+	count      int
+	mux        sync.Mutex
+}
+
+// NewP2 instantiates a P2 struct for the φ-quantile.
+func NewP2(phi float64) (*P2, error) {
+	if phi <= 0 || phi >= 1 {
+		return nil, errors.Errorf("quantile %f not in (0, 1)", phi)
+	}
+
+	p := &P2{
+		phi:        phi,
+		increments: [5]float64{0, phi / 2, phi, (1 + phi) / 2, 1},
+	}
+	p.reset()
+	return p, nil
+}
+
+func (p *P2) reset() {
+	p.heights = [5]float64{}
+	p.positions = [5]float64{0, 1, 2, 3, 4}
+	p.desired = [5]float64{0, 2 * p.phi, 4 * p.phi, 2 + 2*p.phi, 4}
+	p.count = 0
+}
+
+// String returns a string representation of the metric.
+func (p *P2) String() string {
+	name := "quantile.P2"
+	quantile := fmt.Sprintf("quantile:%v", p.phi)
+	return fmt.Sprintf("%s_{%s}", name, quantile)
+}
+
+// Push adds a number for calculating the quantile.
+func (p *P2) Push(x float64) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.count < 5 {
+		p.heights[p.count] = x
+		p.count++
+		if p.count == 5 {
+			sort.Float64s(p.heights[:])
+		}
+		return nil
+	}
+	p.count++
+
+	// find the cell the value falls into, extending the extremes if needed
+	var k int
+	switch {
+	case x < p.heights[0]:
+		p.heights[0] = x
+		k = 0
+	case x >= p.heights[4]:
+		p.heights[4] = x
+		k = 3
+	default:
+		for x >= p.heights[k+1] {
+			k++
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		p.positions[i]++
+	}
+	for i := range p.desired {
+		p.desired[i] += p.increments[i]
+	}
+
+	for i := 1; i < 4; i++ {
+		d := p.desired[i] - p.positions[i]
+		if (d >= 1 && p.positions[i+1]-p.positions[i] > 1) ||
+			(d <= -1 && p.positions[i-1]-p.positions[i] < -1) {
+			s := math.Copysign(1, d)
+			height := p.parabolic(i, s)
+			if p.heights[i-1] < height && height < p.heights[i+1] {
+				p.heights[i] = height
+			} else {
+				p.heights[i] = p.linear(i, s)
+			}
+			p.positions[i] += s
+		}
+	}
+
+	return nil
+}
+
+// parabolic returns the adjusted height of marker i when moved by s
+// using the piecewise-parabolic formula.
+func (p *P2) parabolic(i int, s float64) float64 {
+	q, n := p.heights, p.positions
+	return q[i] + s/(n[i+1]-n[i-1])*
+		((n[i]-n[i-1]+s)*(q[i+1]-q[i])/(n[i+1]-n[i])+
+			(n[i+1]-n[i]-s)*(q[i]-q[i-1])/(n[i]-n[i-1]))
+}
+
+// linear returns the adjusted height of marker i when moved by s
+// using linear interpolation towards its neighbour.
+func (p *P2) linear(i int, s float64) float64 {
+	j := i + int(s)
+	return p.heights[i] + s*(p.heights[j]-p.heights[i])/(p.positions[j]-p.positions[i])
+}
+
+// Value returns the estimate of the quantile.
+func (p *P2) Value() (float64, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.count == 0 {
+		return 0, ErrorNoValuesSeen
+	} else if p.count >= 5 {
+		return p.heights[2], nil
+	}
+
+	vals := make([]float64, p.count)
+	copy(vals, p.heights[:p.count])
+	sort.Float64s(vals)
+
+	idx := p.phi * float64(p.count-1)
+	lo := int(math.Floor(idx))
+	hi := int(math.Ceil(idx))
+	return vals[lo] + (idx-float64(lo))*(vals[hi]-vals[lo]), nil
+}
+
+// Clear resets the metric.
+func (p *P2) Clear() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.reset()
+}
@@ -0,0 +1,124 @@
+package quantile
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewP2(t *testing.T) {
+	t.Run("pass: quantile in (0, 1) is valid", func(t *testing.T) {
+		p, err := NewP2(0.9)
+		require.NoError(t, err)
+		assert.Equal(t, 0.9, p.phi)
+		assert.Equal(t, [5]float64{0, 0.45, 0.9, 0.95, 1}, p.increments)
+	})
+
+	t.Run("fail: quantile outside (0, 1) is invalid", func(t *testing.T) {
+		for _, phi := range []float64{-0.1, 0, 1, 1.5} {
+			_, err := NewP2(phi)
+			testutil.ContainsError(t, err, "not in (0, 1)")
+		}
+	})
+}
+
+func TestP2String(t *testing.T) {
+	p, err := NewP2(0.9)
+	require.NoError(t, err)
+	assert.Equal(t, "quantile.P2_{quantile:0.9}", p.String())
+}
+
+func TestP2Value(t *testing.T) {
+	t.Run("pass: is exact for fewer than five values", func(t *testing.T) {
+		p, err := NewP2(0.5)
+		require.NoError(t, err)
+
+		for _, tt := range []struct {
+			x        float64
+			expected float64
+		}{
+			{3, 3},
+			{1, 2},
+			{8, 3},
+			{2, 2.5},
+		} {
+			err := p.Push(tt.x)
+			require.NoError(t, err)
+
+			value, err := p.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, tt.expected, value)
+		}
+	})
+
+	t.Run("pass: approximates quantiles of normal samples", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		xs := make([]float64, 100000)
+		for i := range xs {
+			xs[i] = rng.NormFloat64()
+		}
+
+		sorted := make([]float64, len(xs))
+		copy(sorted, xs)
+		sort.Float64s(sorted)
+
+		for _, phi := range []float64{0.5, 0.9} {
+			p, err := NewP2(phi)
+			require.NoError(t, err)
+			for _, x := range xs {
+				err := p.Push(x)
+				require.NoError(t, err)
+			}
+
+			value, err := p.Value()
+			require.NoError(t, err)
+			exact := sorted[int(phi*float64(len(sorted)-1))]
+			assert.InDelta(t, exact, value, 0.01, "quantile %v", phi)
+		}
+	})
+
+	t.Run("pass: markers stay ordered", func(t *testing.T) {
+		p, err := NewP2(0.25)
+		require.NoError(t, err)
+
+		rng := rand.New(rand.NewSource(2))
+		for i := 0; i < 10000; i++ {
+			err := p.Push(rng.ExpFloat64())
+			require.NoError(t, err)
+			if i >= 4 {
+				assert.True(t, sort.Float64sAreSorted(p.heights[:]))
+				assert.True(t, sort.Float64sAreSorted(p.positions[:]))
+			}
+		}
+		assert.Equal(t, float64(9999), p.positions[4])
+	})
+
+	t.Run("fail: no values seen is an error", func(t *testing.T) {
+		p, err := NewP2(0.5)
+		require.NoError(t, err)
+
+		_, err = p.Value()
+		assert.ErrorIs(t, err, ErrorNoValuesSeen)
+	})
+}
+
+func TestP2Clear(t *testing.T) {
+	p, err := NewP2(0.5)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, p.Push(float64(i)))
+	}
+
+	p.Clear()
+	assert.Equal(t, 0, p.count)
+	assert.Equal(t, [5]float64{0, 1, 2, 3, 4}, p.positions)
+	assert.Equal(t, [5]float64{0, 1, 2, 3, 4}, p.desired)
+
+	_, err = p.Value()
+	assert.ErrorIs(t, err, ErrorNoValuesSeen)
+}
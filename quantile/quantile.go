@@ -7,7 +7,7 @@ import (
 	"sync"
 
 	"github.com/K4Mobility/stream/quantile/order"
-	"github.com/Workiva/go-datastructures/queue"
+	"github.com/K4Mobility/stream/util/ring"
 	"github.com/pkg/errors"
 )
 
@@ -15,7 +15,7 @@ import (
 type Quantile struct {
 	window        int
 	interpolation Interpolation
-	queue         *queue.RingBuffer
+	queue         ring.Queue[float64]
 	statistic     order.Statistic
 	mux           sync.RWMutex
 }
@@ -34,7 +34,7 @@ func New(window int, options ...Option) (*Quantile, error) {
 	quantile := &Quantile{
 		window:        window,
 		interpolation: Linear,
-		queue:         queue.NewRingBuffer(uint64(window)),
+		queue:         ring.New[float64](uint64(window)),
 		statistic:     avl,
 	}
 
@@ -76,7 +76,7 @@ func (q *Quantile) Push(x float64) error {
 				return errors.Wrap(err, "error popping item from queue")
 			}
 
-			y := val.(float64)
+			y := val
 			q.statistic.Remove(y)
 		}
 
@@ -98,47 +98,81 @@ func (q *Quantile) Value(quantile float64) (float64, error) {
 
 	q.mux.RLock()
 	defer q.mux.RUnlock()
+	return q.value(quantile)
+}
 
+// value returns the value of the quantile, but does not lock.
+func (q *Quantile) value(quantile float64) (float64, error) {
 	size := int(q.statistic.Size())
 	if size == 0 {
-		return 0, errors.New("no values seen yet")
+		return 0, ErrorNoValuesSeen
+	}
+
+	return q.rangeValue(quantile, 0, size), nil
+}
+
+// PercentileRank returns the fraction of the values seen (or in the window,
+// if one is set) that are less than or equal to v, i.e. the inverse of Value.
+func (q *Quantile) PercentileRank(v float64) (float64, error) {
+	if math.IsNaN(v) {
+		return 0, errors.New("cannot compute the percentile rank of NaN")
 	}
 
+	q.mux.RLock()
+	defer q.mux.RUnlock()
+	return q.percentileRank(v)
+}
+
+// percentileRank returns the percentile rank of v, but does not lock.
+func (q *Quantile) percentileRank(v float64) (float64, error) {
+	size := q.statistic.Size()
+	if size == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	rank := q.statistic.RankInclusive(v)
+	return float64(rank) / float64(size), nil
+}
+
+// rangeValue returns the value of the quantile restricted to the size
+// elements starting at rank offset, but does not lock. The range must
+// be nonempty and lie within the bounds of the order statistic.
+func (q *Quantile) rangeValue(quantile float64, offset int, size int) float64 {
 	idxRaw := quantile * float64(size-1)
 	idxTrunc := math.Trunc(idxRaw)
 	idx := int(idxTrunc)
 	// if the estimated index is actually an integer,
 	// no interpolation needed
 	if idxRaw == idxTrunc {
-		return q.statistic.Select(idx).Value(), nil
+		return q.statistic.Select(offset + idx).Value()
 	}
 
 	delta := idxRaw - idxTrunc
 	switch q.interpolation {
 	case Linear:
-		lo := q.statistic.Select(idx).Value()
-		hi := q.statistic.Select(idx + 1).Value()
-		return (1-delta)*lo + delta*hi, nil
+		lo := q.statistic.Select(offset + idx).Value()
+		hi := q.statistic.Select(offset + idx + 1).Value()
+		return (1-delta)*lo + delta*hi
 	case Lower:
-		return q.statistic.Select(idx).Value(), nil
+		return q.statistic.Select(offset + idx).Value()
 	case Higher:
-		return q.statistic.Select(idx + 1).Value(), nil
+		return q.statistic.Select(offset + idx + 1).Value()
 	case Nearest:
 		switch {
 		case delta == 0.5:
 			if idx%2 == 0 {
-				return q.statistic.Select(idx).Value(), nil
+				return q.statistic.Select(offset + idx).Value()
 			}
-			return q.statistic.Select(idx + 1).Value(), nil
+			return q.statistic.Select(offset + idx + 1).Value()
 		case delta < 0.5:
-			return q.statistic.Select(idx).Value(), nil
+			return q.statistic.Select(offset + idx).Value()
 		default:
-			return q.statistic.Select(idx + 1).Value(), nil
+			return q.statistic.Select(offset + idx + 1).Value()
 		}
 	default:
-		lo := q.statistic.Select(idx).Value()
-		hi := q.statistic.Select(idx + 1).Value()
-		return (lo + hi) / 2., nil
+		lo := q.statistic.Select(offset + idx).Value()
+		hi := q.statistic.Select(offset + idx + 1).Value()
+		return (lo + hi) / 2.
 	}
 }
 
@@ -146,8 +180,7 @@ func (q *Quantile) Value(quantile float64) (float64, error) {
 func (q *Quantile) Clear() {
 	q.mux.Lock()
 	defer q.mux.Unlock()
-	q.queue.Dispose()
-	q.queue = queue.NewRingBuffer(uint64(q.window))
+	q.queue.Reset()
 	q.statistic.Clear()
 }
 
@@ -160,3 +193,7 @@ func (q *Quantile) RLock() {
 func (q *Quantile) RUnlock() {
 	q.mux.RUnlock()
 }
+
+// ErrorNoValuesSeen is returned (possibly wrapped) by metrics that have not
+// seen any values yet, and can be matched with errors.Is.
+var ErrorNoValuesSeen = errors.New("no values seen yet")
@@ -1,12 +1,17 @@
 package quantile
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/K4Mobility/stream"
 	"github.com/K4Mobility/stream/quantile/skiplist"
 	testutil "github.com/K4Mobility/stream/util/test"
 )
@@ -60,8 +65,7 @@ func TestQuantilePush(t *testing.T) {
 
 		assert.Equal(t, uint64(3), quantile.queue.Len())
 		for i := 2.; i < 5; i++ {
-			val, err := quantile.queue.Get()
-			y := val.(float64)
+			y, err := quantile.queue.Get()
 			require.NoError(t, err)
 			testutil.Approx(t, i, y)
 		}
@@ -243,6 +247,136 @@ func TestQuantileValue(t *testing.T) {
 	})
 }
 
+func TestQuantileValueMatchesNumPy(t *testing.T) {
+	// expected values are numpy.quantile(xs, phi, method=...) for each method
+	xs := []float64{7, 1, 10, 4, 3}
+	for _, tt := range []struct {
+		interpolation Interpolation
+		expected      map[float64]float64
+	}{
+		{Linear, map[float64]float64{0.1: 1.8, 0.3: 3.2, 0.5: 4, 0.75: 7, 0.9: 8.8}},
+		{Lower, map[float64]float64{0.1: 1, 0.3: 3, 0.5: 4, 0.9: 7}},
+		{Higher, map[float64]float64{0.1: 3, 0.3: 4, 0.5: 4, 0.9: 10}},
+		{Nearest, map[float64]float64{0.1: 1, 0.3: 3, 0.5: 4, 0.625: 4, 0.875: 10, 0.9: 10}},
+		{Midpoint, map[float64]float64{0.1: 2, 0.3: 3.5, 0.5: 4, 0.9: 8.5}},
+	} {
+		t.Run(fmt.Sprintf("pass: interpolation %d matches NumPy", tt.interpolation), func(t *testing.T) {
+			quantile, err := New(0, InterpolationOption(tt.interpolation))
+			require.NoError(t, err)
+			for _, x := range xs {
+				require.NoError(t, quantile.Push(x))
+			}
+
+			for phi, expected := range tt.expected {
+				val, err := quantile.Value(phi)
+				require.NoError(t, err)
+				testutil.Approx(t, expected, val, "phi = %v", phi)
+			}
+		})
+	}
+
+	t.Run("pass: linear interpolation is the default", func(t *testing.T) {
+		quantile, err := New(0)
+		require.NoError(t, err)
+		assert.Equal(t, Linear, quantile.interpolation)
+	})
+}
+
+func TestQuantileValueLowerMatchesSortedReference(t *testing.T) {
+	xs := []float64{7, -2, 9, 4, 4, 0, 13, -8, 5, 1, 6, 3}
+	quantiles := []float64{0.1, 0.25, 0.5, 0.75, 0.9}
+
+	for _, impl := range []Impl{AVL, RedBlack, SkipList} {
+		t.Run(fmt.Sprintf("pass: Lower selects rank floor(phi*(n-1)) over window for Impl %d", impl), func(t *testing.T) {
+			window := 5
+			quantile, err := New(window, ImplOption(impl), InterpolationOption(Lower))
+			require.NoError(t, err)
+
+			for i, x := range xs {
+				err := quantile.Push(x)
+				require.NoError(t, err)
+
+				start := i + 1 - window
+				if start < 0 {
+					start = 0
+				}
+				sorted := append([]float64{}, xs[start:i+1]...)
+				sort.Float64s(sorted)
+
+				for _, phi := range quantiles {
+					value, err := quantile.Value(phi)
+					require.NoError(t, err)
+					expected := sorted[int(math.Floor(phi*float64(len(sorted)-1)))]
+					assert.Equal(t, expected, value, "phi %v differs after push %d", phi, i)
+				}
+			}
+		})
+	}
+}
+
+func TestQuantilePercentileRank(t *testing.T) {
+	t.Run("fail: no values seen", func(t *testing.T) {
+		quantile, err := NewGlobalQuantile()
+		require.NoError(t, err)
+
+		_, err = quantile.PercentileRank(1)
+		assert.True(t, errors.Is(err, ErrorNoValuesSeen))
+	})
+
+	t.Run("fail: NaN has no percentile rank", func(t *testing.T) {
+		quantile, err := NewGlobalQuantile()
+		require.NoError(t, err)
+		err = quantile.Push(1)
+		require.NoError(t, err)
+
+		_, err = quantile.PercentileRank(math.NaN())
+		assert.EqualError(t, err, "cannot compute the percentile rank of NaN")
+	})
+
+	for _, impl := range []Impl{AVL, RedBlack, SkipList} {
+		t.Run(fmt.Sprintf("pass: known median has a percentile rank of about 0.5 for %v", impl), func(t *testing.T) {
+			quantile, err := New(101, ImplOption(impl))
+			require.NoError(t, err)
+			for i := 0; i < 1000; i++ {
+				err := quantile.Push(float64(i % 101))
+				require.NoError(t, err)
+			}
+
+			median, err := quantile.Value(0.5)
+			require.NoError(t, err)
+			assert.Equal(t, 50., median)
+
+			rank, err := quantile.PercentileRank(median)
+			require.NoError(t, err)
+			assert.InDelta(t, 0.5, rank, 0.01)
+		})
+	}
+
+	t.Run("pass: counts values less than or equal to v, within [0, 1]", func(t *testing.T) {
+		quantile, err := New(4)
+		require.NoError(t, err)
+		for _, x := range []float64{10, 1, 2, 2, 3} {
+			err := quantile.Push(x)
+			require.NoError(t, err)
+		}
+
+		for v, expected := range map[float64]float64{
+			math.Inf(-1): 0,
+			0:            0,
+			1:            0.25,
+			2:            0.75,
+			2.5:          0.75,
+			3:            1,
+			10:           1,
+			math.Inf(1):  1,
+		} {
+			rank, err := quantile.PercentileRank(v)
+			require.NoError(t, err)
+			assert.Equal(t, expected, rank, "percentile rank of %v", v)
+		}
+	})
+}
+
 func TestQuantileClear(t *testing.T) {
 	quantile, err := New(3)
 	require.NoError(t, err)
@@ -276,7 +410,9 @@ func TestQuantileRLock(t *testing.T) {
 		done <- true
 	}()
 
-	val, err := quantile.Value(0.5)
+	// value must be used here rather than Value, since acquiring the read
+	// lock again can deadlock once the pusher is waiting for the write lock
+	val, err := quantile.value(0.5)
 	require.NoError(t, err)
 	testutil.Approx(t, 1., val)
 
@@ -289,3 +425,50 @@ func TestQuantileRLock(t *testing.T) {
 	require.NoError(t, err)
 	testutil.Approx(t, 2., val)
 }
+
+func TestQuantileConcurrentPushAndValue(t *testing.T) {
+	for _, impl := range []Impl{AVL, RedBlack, SkipList} {
+		t.Run(fmt.Sprintf("pass: concurrent pushes and reads are serialized for Impl %d", impl), func(t *testing.T) {
+			quantile, err := New(10, ImplOption(impl))
+			require.NoError(t, err)
+
+			err = quantile.Push(0)
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			for p := 0; p < 4; p++ {
+				wg.Add(1)
+				go func(p int) {
+					defer wg.Done()
+					for i := 0; i < 1000; i++ {
+						assert.NoError(t, quantile.Push(float64(p*1000+i)))
+					}
+				}(p)
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					_, err := quantile.Value(0.9)
+					assert.NoError(t, err)
+				}
+			}()
+
+			wg.Wait()
+			assert.Equal(t, 10, quantile.statistic.Size())
+		})
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	median, err := NewGlobalMedian()
+	require.NoError(t, err)
+	iqr, err := NewIQR(3)
+	require.NoError(t, err)
+
+	for _, metric := range []stream.SimpleMetric{median, iqr, NewGlobalHeapMedian()} {
+		_, err := metric.Value()
+		assert.True(t, errors.Is(err, ErrorNoValuesSeen), metric.String())
+	}
+}
@@ -0,0 +1,105 @@
+package quantile
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultRobustZScoreConstant is the standard consistency constant for the
+// modified z-score. It is the 0.75 quantile of the standard normal distribution,
+// which makes MAD / 0.6745 a consistent estimator of the standard deviation for
+// normally distributed data; scaling by it keeps modified z-scores comparable to
+// ordinary z-scores.
+const DefaultRobustZScoreConstant = 0.6745
+
+// RobustZScore keeps track of the modified z-score of the most recently pushed value,
+// i.e. constant * (x - median) / MAD, where MAD is the median absolute deviation
+// from the median.
+type RobustZScore struct {
+	constant float64
+	quantile *Quantile
+	last     float64
+	mux      sync.RWMutex
+}
+
+// NewRobustZScore instantiates a RobustZScore struct. The constant scales the
+// resulting score; DefaultRobustZScoreConstant is the conventional choice.
+func NewRobustZScore(constant float64, window int, options ...Option) (*RobustZScore, error) {
+	if constant <= 0 {
+		return nil, errors.Errorf("%f is a nonpositive constant", constant)
+	}
+
+	quantile, err := New(window, append(options, InterpolationOption(Midpoint))...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Quantile")
+	}
+
+	return &RobustZScore{
+		constant: constant,
+		quantile: quantile,
+	}, nil
+}
+
+// NewGlobalRobustZScore instantiates a global RobustZScore struct.
+// This is equivalent to calling NewRobustZScore(constant, 0, options...).
+func NewGlobalRobustZScore(constant float64, options ...Option) (*RobustZScore, error) {
+	return NewRobustZScore(constant, 0, options...)
+}
+
+// String returns a string representation of the metric.
+func (z *RobustZScore) String() string {
+	name := "quantile.RobustZScore"
+	params := []string{
+		fmt.Sprintf("constant:%v", z.constant),
+		fmt.Sprintf("quantile:%v", z.quantile.String()),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a number for calculating the modified z-score.
+func (z *RobustZScore) Push(x float64) error {
+	z.mux.Lock()
+	defer z.mux.Unlock()
+
+	err := z.quantile.Push(x)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing %f to Quantile", x)
+	}
+
+	z.last = x
+	return nil
+}
+
+// Value returns the modified z-score of the most recently pushed value.
+// Calculating the MAD requires a second pass over the tracked values, so
+// this takes O(n log n) time, where n is the number of values tracked.
+func (z *RobustZScore) Value() (float64, error) {
+	z.mux.RLock()
+	defer z.mux.RUnlock()
+
+	z.quantile.RLock()
+	defer z.quantile.RUnlock()
+
+	median, err := z.quantile.value(0.5)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving median: %w", err)
+	}
+
+	mad := medianAbsDev(z.quantile, median)
+	if mad == 0 {
+		return 0, errors.New("MAD is zero")
+	}
+
+	return z.constant * (z.last - median) / mad, nil
+}
+
+// Clear resets the metric.
+func (z *RobustZScore) Clear() {
+	z.mux.Lock()
+	defer z.mux.Unlock()
+	z.quantile.Clear()
+	z.last = 0
+}
@@ -0,0 +1,146 @@
+package quantile
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewRobustZScore(t *testing.T) {
+	t.Run("pass: valid constant and window are valid", func(t *testing.T) {
+		z, err := NewRobustZScore(DefaultRobustZScoreConstant, 5)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultRobustZScoreConstant, z.constant)
+		assert.Equal(t, 5, z.quantile.window)
+		assert.Equal(t, Midpoint, z.quantile.interpolation)
+	})
+
+	t.Run("fail: nonpositive constant is invalid", func(t *testing.T) {
+		_, err := NewRobustZScore(0, 5)
+		testutil.ContainsError(t, err, fmt.Sprintf("%f is a nonpositive constant", 0.))
+	})
+
+	t.Run("fail: negative window is invalid", func(t *testing.T) {
+		_, err := NewRobustZScore(DefaultRobustZScoreConstant, -1)
+		testutil.ContainsError(t, err, "error creating Quantile")
+	})
+}
+
+func TestNewGlobalRobustZScore(t *testing.T) {
+	z, err := NewRobustZScore(DefaultRobustZScoreConstant, 0)
+	require.NoError(t, err)
+
+	globalZ, err := NewGlobalRobustZScore(DefaultRobustZScoreConstant)
+	require.NoError(t, err)
+
+	assert.Equal(t, z, globalZ)
+}
+
+func TestRobustZScoreString(t *testing.T) {
+	expectedString := fmt.Sprintf(
+		"quantile.RobustZScore_{constant:0.6745,quantile:quantile.Quantile_{window:3,interpolation:%d}}",
+		Midpoint,
+	)
+	z, err := NewRobustZScore(DefaultRobustZScoreConstant, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedString, z.String())
+}
+
+func TestRobustZScorePush(t *testing.T) {
+	t.Run("pass: successfully pushes values", func(t *testing.T) {
+		z, err := NewRobustZScore(DefaultRobustZScoreConstant, 3)
+		require.NoError(t, err)
+		for i := 0.; i < 5; i++ {
+			err := z.Push(i)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 4., z.last)
+		assert.Equal(t, 3, z.quantile.statistic.Size())
+	})
+
+	t.Run("fail: if queue insertion fails, return error", func(t *testing.T) {
+		z, err := NewRobustZScore(DefaultRobustZScoreConstant, 3)
+		require.NoError(t, err)
+
+		// dispose the queue to simulate an error when we try to insert into the queue
+		z.quantile.queue.Dispose()
+		val := 3.
+		err = z.Push(val)
+		testutil.ContainsError(t, err, fmt.Sprintf("error pushing %f to Quantile", val))
+	})
+}
+
+func TestRobustZScoreValue(t *testing.T) {
+	t.Run("pass: returns modified z-score of last value", func(t *testing.T) {
+		z, err := NewRobustZScore(DefaultRobustZScoreConstant, 5)
+		require.NoError(t, err)
+
+		// the first value gets evicted from the window
+		for _, x := range []float64{-50, 1, 2, 3, 4, 100} {
+			err = z.Push(x)
+			require.NoError(t, err)
+		}
+
+		// median = 3, deviations = {2, 1, 0, 1, 97}, MAD = 1
+		value, err := z.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 0.6745*97, value)
+	})
+
+	t.Run("pass: constant scales the score", func(t *testing.T) {
+		z, err := NewRobustZScore(1, 4)
+		require.NoError(t, err)
+
+		for _, x := range []float64{1, 2, 4, 8} {
+			err = z.Push(x)
+			require.NoError(t, err)
+		}
+
+		// median = 3, deviations = {2, 1, 1, 5}, MAD = 1.5
+		value, err := z.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, 5./1.5, value)
+	})
+
+	t.Run("fail: if MAD is zero, return error", func(t *testing.T) {
+		z, err := NewRobustZScore(DefaultRobustZScoreConstant, 3)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			err = z.Push(1)
+			require.NoError(t, err)
+		}
+
+		_, err = z.Value()
+		testutil.ContainsError(t, err, "MAD is zero")
+	})
+
+	t.Run("fail: if no values seen, return error", func(t *testing.T) {
+		z, err := NewRobustZScore(DefaultRobustZScoreConstant, 3)
+		require.NoError(t, err)
+
+		_, err = z.Value()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+}
+
+func TestRobustZScoreClear(t *testing.T) {
+	z, err := NewRobustZScore(DefaultRobustZScoreConstant, 3)
+	require.NoError(t, err)
+
+	for i := 0.; i < 10; i++ {
+		err = z.Push(i * i)
+		require.NoError(t, err)
+	}
+
+	z.Clear()
+	assert.Equal(t, uint64(0), z.quantile.queue.Len())
+	assert.Equal(t, 0, z.quantile.statistic.Size())
+	assert.Equal(t, 0., z.last)
+}
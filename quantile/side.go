@@ -0,0 +1,42 @@
+package quantile
+
+// Side represents an enum that enumerates the tails of a
+// distribution that a conditional quantile can be restricted to.
+// In particular, if τ is the threshold quantile and n is the number
+// of elements, then the tail boundary lies at the raw index
+// i' = τ * (n - 1), following the same convention as Interpolation.
+type Side int
+
+const (
+	// Left restricts the values to the lower tail, i.e.
+	// the elements with index at most floor(i').
+	Left Side = iota
+	// Right restricts the values to the upper tail, i.e.
+	// the elements with index at least ceil(i').
+	Right
+)
+
+// Valid returns whether or not the Side value is a valid value.
+func (s Side) Valid() bool {
+	switch s {
+	case Left, Right:
+		return true
+	default:
+		return false
+	}
+}
+
+// tail returns the rank offset and the number of elements of the
+// tail of n elements that lies past the threshold quantile.
+func (s Side) tail(threshold float64, n int) (int, int) {
+	idxRaw := threshold * float64(n-1)
+	if s == Left {
+		return 0, int(idxRaw) + 1
+	}
+
+	offset := int(idxRaw)
+	if float64(offset) != idxRaw {
+		offset++
+	}
+	return offset, n - offset
+}
@@ -57,3 +57,10 @@ func RandOption(r *rand.Rand) order.Option {
 		return nil
 	}
 }
+
+// SeedOption creates an option that seeds the rand source for the skip list,
+// so that skip lists created with the same seed have reproducible structure.
+// This is equivalent to calling RandOption(rand.New(rand.NewSource(seed))).
+func SeedOption(seed int64) order.Option {
+	return RandOption(rand.New(rand.NewSource(seed)))
+}
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/K4Mobility/stream/quantile/order"
 	"github.com/K4Mobility/stream/quantile/ost/rb"
 	testutil "github.com/K4Mobility/stream/util/test"
 	"github.com/stretchr/testify/assert"
@@ -95,3 +96,91 @@ func TestRandOption(t *testing.T) {
 		assert.Equal(t, rand, skiplist.rand)
 	})
 }
+
+func TestSeedOption(t *testing.T) {
+	t.Run("fail: non-skiplist is invalid", func(t *testing.T) {
+		err := SeedOption(1)(&rb.Tree{})
+		testutil.ContainsError(t, err, "attempted to set rand source on a non-skiplist")
+	})
+
+	t.Run("pass: skip lists with the same seed have the same structure", func(t *testing.T) {
+		skiplist1, err := New(SeedOption(42))
+		require.NoError(t, err)
+
+		skiplist2, err := New(SeedOption(42))
+		require.NoError(t, err)
+
+		for i := 0; i < 100; i++ {
+			x := float64((i * 37) % 101)
+			skiplist1.Add(x)
+			skiplist2.Add(x)
+		}
+
+		assert.Equal(t, skiplist1.String(), skiplist2.String())
+	})
+}
+
+func TestMaxLevelOptionOrderStatistics(t *testing.T) {
+	for _, maxLevel := range []int{1, 2} {
+		t.Run(fmt.Sprintf("pass: max level %d yields correct Select and Rank", maxLevel), func(t *testing.T) {
+			skiplist, err := New(MaxLevelOption(maxLevel), SeedOption(7))
+			require.NoError(t, err)
+
+			for i := 0; i < 200; i++ {
+				skiplist.Add(float64((i * 37) % 101))
+			}
+
+			// every value 0 <= x < 101 appears once or twice, in sorted order
+			var sorted []float64
+			for x := 0; x < 101; x++ {
+				for i := 0; i < 200; i++ {
+					if (i*37)%101 == x {
+						sorted = append(sorted, float64(x))
+					}
+				}
+			}
+
+			for i, x := range sorted {
+				assert.Equal(t, x, skiplist.Select(i).Value())
+			}
+			for x := 0; x < 101; x++ {
+				rank := 0
+				for _, y := range sorted {
+					if y < float64(x) {
+						rank++
+					}
+				}
+				assert.Equal(t, rank, skiplist.Rank(float64(x)))
+			}
+
+			for node := skiplist.head.next[0]; node != nil; node = node.next[0] {
+				assert.LessOrEqual(t, len(node.next), maxLevel)
+			}
+		})
+	}
+}
+
+func TestProbabilityOptionLevels(t *testing.T) {
+	// the fraction of nodes with at least two levels is p in expectation
+	fraction := func(t *testing.T, options ...order.Option) float64 {
+		skiplist, err := New(append(options, SeedOption(11))...)
+		require.NoError(t, err)
+
+		n := 10000
+		for i := 0; i < n; i++ {
+			skiplist.Add(float64(i))
+		}
+
+		promoted := 0
+		for node := skiplist.head.next[0]; node != nil; node = node.next[0] {
+			if len(node.next) >= 2 {
+				promoted++
+			}
+		}
+		return float64(promoted) / float64(n)
+	}
+
+	assert.InDelta(t, DefaultProbability, fraction(t), 0.02)
+	assert.InDelta(t, 0.5, fraction(t, ProbabilityOption(0.5)), 0.02)
+	assert.InDelta(t, 0.1, fraction(t, ProbabilityOption(0.1)), 0.02)
+}
@@ -31,10 +31,6 @@ func (n *Node) Value() float64 {
 	return n.val
 }
 
-func (n *Node) equals(m *Node) bool {
-	return (n == nil && m == nil) || (n != nil && m != nil && n.val == m.val)
-}
-
 func (n *Node) string() string {
 	if n == nil {
 		return "tail"
@@ -47,6 +43,7 @@ func (n *Node) string() string {
 
 // SkipList implements a skip list data structure,
 // and also satisfies the order.Statistic interface.
+// It is not safe for concurrent use; Quantile serializes access to it.
 type SkipList struct {
 	head     *Node
 	maxLevel int
@@ -129,7 +126,9 @@ func (s *SkipList) Add(val float64) {
 		prevs[i].width[i]++
 	}
 	for i := 1; i < level; i++ {
-		for curr := node; !curr.equals(node.next[i]); curr = curr.next[i-1] {
+		// nodes must be compared by identity rather than by value here,
+		// since duplicate values would otherwise end the walk early
+		for curr := node; curr != node.next[i]; curr = curr.next[i-1] {
 			node.width[i] += curr.width[i-1]
 		}
 		prevs[i].width[i] -= node.width[i]
@@ -138,24 +137,32 @@ func (s *SkipList) Add(val float64) {
 	s.length++
 }
 
-// Remove deletes a value from the skip list.
+// Remove deletes a value from the skip list, if it is present.
 func (s *SkipList) Remove(val float64) {
+	s.Delete(val)
+}
+
+// Delete deletes a value from the skip list, and returns whether
+// the value was present (and thus actually deleted).
+func (s *SkipList) Delete(val float64) bool {
 	prevs := s.getPrevs(val)
 	// if node with value is found, then set all predecessors to point
 	// to the nodes in node.next, and update widths
-	if node := prevs[0].next[0]; node != nil && node.val == val {
-		for i := range prevs {
-			if i < len(node.next) {
-				prevs[i].next[i] = node.next[i]
-				prevs[i].width[i] += node.width[i] - 1
-			} else {
-				prevs[i].width[i]--
-			}
-		}
-		s.length--
-	} else {
+	node := prevs[0].next[0]
+	if node == nil || node.val != val {
+		return false
+	}
 
+	for i := range prevs {
+		if i < len(node.next) {
+			prevs[i].next[i] = node.next[i]
+			prevs[i].width[i] += node.width[i] - 1
+		} else {
+			prevs[i].width[i]--
+		}
 	}
+	s.length--
+	return true
 }
 
 // Select returns the node with the kth smallest value in the skip list.
@@ -191,6 +198,55 @@ func (s *SkipList) Rank(val float64) int {
 	return rank
 }
 
+// RankInclusive returns the number of nodes less than or equal to the given value,
+// i.e. Rank(val) plus the number of nodes equal to the value.
+func (s *SkipList) RankInclusive(val float64) int {
+	rank := 0
+	node := s.head
+	for i := s.maxLevel - 1; i >= 0; i-- {
+		for node.next[i] != nil && node.next[i].val <= val {
+			rank += node.width[i]
+			node = node.next[i]
+		}
+	}
+
+	return rank
+}
+
+// Min returns the smallest value in the skip list, or false if the skip list is empty.
+func (s *SkipList) Min() (float64, bool) {
+	node := s.head.next[0]
+	if node == nil {
+		return 0, false
+	}
+	return node.val, true
+}
+
+// Max returns the largest value in the skip list, or false if the skip list is empty.
+func (s *SkipList) Max() (float64, bool) {
+	if s.length == 0 {
+		return 0, false
+	}
+
+	node := s.head
+	for i := s.maxLevel - 1; i >= 0; i-- {
+		for node.next[i] != nil {
+			node = node.next[i]
+		}
+	}
+	return node.val, true
+}
+
+// InOrder calls f on each value in the skip list in sorted order, stopping
+// early if f returns false. This takes O(n) time to visit all n values.
+func (s *SkipList) InOrder(f func(float64) bool) {
+	for node := s.head.next[0]; node != nil; node = node.next[0] {
+		if !f(node.val) {
+			return
+		}
+	}
+}
+
 // String returns the string representation of the skip list.
 func (s *SkipList) String() string {
 	result := ""
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/K4Mobility/stream/quantile/order"
 	testutil "github.com/K4Mobility/stream/util/test"
 )
 
@@ -95,6 +96,25 @@ func (s *SkipListSuite) TestAdd() {
 	)
 }
 
+func (s *SkipListSuite) TestAddDuplicates() {
+	// widths must stay consistent regardless of the levels chosen for
+	// duplicate values, so check Select against every seed in a range
+	for seed := int64(0); seed < 50; seed++ {
+		skiplist, err := New(SeedOption(seed))
+		s.Require().NoError(err)
+
+		vals := []float64{4, 4, 0, 4, 9, 4, 0, 7, 7, 4}
+		for _, val := range vals {
+			skiplist.Add(val)
+		}
+
+		sorted := []float64{0, 0, 4, 4, 4, 4, 4, 7, 7, 9}
+		for k, val := range sorted {
+			s.Equal(val, skiplist.Select(k).Value(), "seed %d, rank %d", seed, k)
+		}
+	}
+}
+
 func (s *SkipListSuite) TestRemove() {
 	s.Run("pass: successfully removes values", func() {
 		s.SetupTest()
@@ -203,6 +223,36 @@ func (s *SkipListSuite) TestRemove() {
 	})
 }
 
+func (s *SkipListSuite) TestDelete() {
+	s.Run("pass: deleting a present value returns true", func() {
+		s.SetupTest()
+		s.True(s.skiplist.Delete(5))
+		s.Equal(7, s.skiplist.Size())
+		s.Nil(s.skiplist.Select(7))
+	})
+
+	s.Run("pass: deleting an absent value returns false", func() {
+		s.SetupTest()
+		s.False(s.skiplist.Delete(100))
+		s.False(s.skiplist.Delete(4.5))
+		s.Equal(8, s.skiplist.Size())
+	})
+
+	s.Run("pass: each duplicate is deleted once", func() {
+		s.SetupTest()
+		s.True(s.skiplist.Delete(1))
+		s.True(s.skiplist.Delete(1))
+		s.False(s.skiplist.Delete(1))
+		s.Equal(6, s.skiplist.Size())
+	})
+
+	s.Run("pass: deleting from an empty skip list returns false", func() {
+		s.skiplist.Clear()
+		s.False(s.skiplist.Delete(1))
+		s.Equal(0, s.skiplist.Size())
+	})
+}
+
 func (s *SkipListSuite) TestRank() {
 	rank := s.skiplist.Rank(3)
 	s.Equal(3, rank)
@@ -214,6 +264,39 @@ func (s *SkipListSuite) TestRank() {
 	s.Equal(0, rank)
 }
 
+func (s *SkipListSuite) TestRankInclusive() {
+	s.Run("pass: counts duplicates of the value", func() {
+		// the values are 1, 1, 2, 3, 4, 5, 6, 7
+		for _, tt := range []struct {
+			val       float64
+			rank      int
+			inclusive int
+		}{
+			{-1, 0, 0},
+			{1, 0, 2},
+			{1.5, 2, 2},
+			{2, 2, 3},
+			{5.5, 6, 6},
+			{7, 7, 8},
+			{8, 8, 8},
+		} {
+			s.Equal(tt.rank, s.skiplist.Rank(tt.val), "Rank(%v)", tt.val)
+			s.Equal(tt.inclusive, s.skiplist.RankInclusive(tt.val), "RankInclusive(%v)", tt.val)
+		}
+	})
+
+	s.Run("pass: counts many duplicates", func() {
+		s.skiplist.Clear()
+		for i := 0; i < 50; i++ {
+			s.skiplist.Add(float64(i % 5))
+		}
+		for val := 0; val < 5; val++ {
+			s.Equal(10*val, s.skiplist.Rank(float64(val)))
+			s.Equal(10*(val+1), s.skiplist.RankInclusive(float64(val)))
+		}
+	})
+}
+
 func (s *SkipListSuite) TestSelect() {
 	node := s.skiplist.Select(5)
 	s.Equal(float64(5), node.Value())
@@ -225,6 +308,58 @@ func (s *SkipListSuite) TestSelect() {
 	s.Nil(node)
 }
 
+func (s *SkipListSuite) TestMinMax() {
+	s.Run("pass: returns the extreme values", func() {
+		min, ok := s.skiplist.Min()
+		s.True(ok)
+		s.Equal(1., min)
+
+		max, ok := s.skiplist.Max()
+		s.True(ok)
+		s.Equal(7., max)
+	})
+
+	s.Run("pass: returns false for an empty skip list", func() {
+		s.skiplist.Clear()
+
+		_, ok := s.skiplist.Min()
+		s.False(ok)
+
+		_, ok = s.skiplist.Max()
+		s.False(ok)
+	})
+}
+
+func (s *SkipListSuite) TestInOrder() {
+	s.Implements((*order.Iterable)(nil), s.skiplist)
+
+	s.Run("pass: visits all values in sorted order", func() {
+		vals := []float64{}
+		s.skiplist.InOrder(func(val float64) bool {
+			vals = append(vals, val)
+			return true
+		})
+		s.Equal([]float64{1, 1, 2, 3, 4, 5, 6, 7}, vals)
+	})
+
+	s.Run("pass: stops early if the callback returns false", func() {
+		vals := []float64{}
+		s.skiplist.InOrder(func(val float64) bool {
+			vals = append(vals, val)
+			return len(vals) < 3
+		})
+		s.Equal([]float64{1, 1, 2}, vals)
+	})
+
+	s.Run("pass: visits nothing in an empty skip list", func() {
+		s.skiplist.Clear()
+		s.skiplist.InOrder(func(val float64) bool {
+			s.Fail("callback should not be called")
+			return true
+		})
+	})
+}
+
 func (s *SkipListSuite) TestClear() {
 	s.skiplist.Clear()
 	s.Equal(0, s.skiplist.length)
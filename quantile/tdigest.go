@@ -0,0 +1,192 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultTDigestCompression is a compression parameter that offers a reasonable
+// tradeoff between accuracy and memory for most streams.
+const DefaultTDigestCompression = 100.
+
+// TDigest keeps track of approximate quantiles of a stream in bounded memory using
+// a merging t-digest, as described in the following paper:
+// T. Dunning, O. Ertl, Computing extremely accurate quantiles using t-digests,
+// arXiv:1902.04023 (2019).
+//
+// Values are summarized into weighted centroids, whose sizes are bounded by the k1
+// scale function, so that centroids near the tails are small and quantiles there are
+// estimated very accurately. With a compression of δ, at most about πδ/2 centroids are
+// kept; for DefaultTDigestCompression, quantile estimates are typically within 0.5% of
+// the exact quantile in rank, and far closer near the tails. Since values cannot be
+// removed from the centroids, TDigest only tracks the global quantiles of a stream.
+type TDigest struct {
+	compression float64
+	mux         sync.Mutex
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min         float64
+	max         float64
+}
+
+// centroid is a weighted mean of values summarized by a TDigest.
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// NewTDigest instantiates a TDigest struct with the provided compression;
+// larger compressions are more accurate, at the cost of more memory.
+func NewTDigest(compression float64) (*TDigest, error) {
+	if compression <= 0 {
+		return nil, errors.Errorf("%f is a nonpositive compression", compression)
+	}
+
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}, nil
+}
+
+// String returns a string representation of the metric.
+func (t *TDigest) String() string {
+	name := "quantile.TDigest"
+	compression := fmt.Sprintf("compression:%v", t.compression)
+	return fmt.Sprintf("%s_{%s}", name, compression)
+}
+
+// Push adds a number for calculating the quantiles. Non-finite values are rejected,
+// since they would corrupt the means of the centroids they are merged into.
+func (t *TDigest) Push(x float64) error {
+	if math.IsInf(x, 0) || math.IsNaN(x) {
+		return errors.Errorf("cannot push %v", x)
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.buffer = append(t.buffer, centroid{mean: x, weight: 1})
+	t.count++
+	t.min = math.Min(t.min, x)
+	t.max = math.Max(t.max, x)
+
+	if len(t.buffer) >= t.bufferSize() {
+		t.merge()
+	}
+	return nil
+}
+
+// Value returns the approximate value of the quantile.
+func (t *TDigest) Value(quantile float64) (float64, error) {
+	if quantile <= 0 || quantile >= 1 {
+		return 0, errors.Errorf("quantile %f not in (0, 1)", quantile)
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.count == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	t.merge()
+	return t.value(quantile), nil
+}
+
+// Count returns the number of values seen.
+func (t *TDigest) Count() int {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return int(t.count)
+}
+
+// Clear resets the metric.
+func (t *TDigest) Clear() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.centroids = nil
+	t.buffer = nil
+	t.count = 0
+	t.min = math.Inf(1)
+	t.max = math.Inf(-1)
+}
+
+// bufferSize returns the number of values buffered before they are merged into the centroids.
+func (t *TDigest) bufferSize() int {
+	return int(math.Ceil(5 * t.compression))
+}
+
+// k returns the value of the k1 scale function at quantile q.
+func (t *TDigest) k(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// kInv returns the quantile at which the k1 scale function takes the value k.
+func (t *TDigest) kInv(k float64) float64 {
+	k = math.Max(-t.compression/4, math.Min(t.compression/4, k))
+	return (math.Sin(k*2*math.Pi/t.compression) + 1) / 2
+}
+
+// merge merges the buffered values into the centroids, but does not lock.
+func (t *TDigest) merge() {
+	if len(t.buffer) == 0 {
+		return
+	}
+
+	all := append(t.centroids, t.buffer...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]centroid, 0, len(t.centroids)+1)
+	current := all[0]
+	soFar := 0.
+	limit := t.kInv(t.k(0) + 1)
+	for _, c := range all[1:] {
+		if (soFar+current.weight+c.weight)/t.count <= limit {
+			current.weight += c.weight
+			current.mean += (c.mean - current.mean) * c.weight / current.weight
+			continue
+		}
+
+		merged = append(merged, current)
+		soFar += current.weight
+		limit = t.kInv(t.k(soFar/t.count) + 1)
+		current = c
+	}
+	merged = append(merged, current)
+
+	t.centroids = merged
+	t.buffer = t.buffer[:0]
+}
+
+// value returns the approximate value of the quantile, by interpolating between
+// the centers of adjacent centroids, but does not lock. The buffer must be merged.
+func (t *TDigest) value(quantile float64) float64 {
+	if len(t.centroids) == 1 {
+		return t.centroids[0].mean
+	}
+
+	index := quantile * t.count
+	first := t.centroids[0]
+	if index < first.weight/2 {
+		return t.min + (first.mean-t.min)*index/(first.weight/2)
+	}
+
+	center := first.weight / 2
+	for i := 1; i < len(t.centroids); i++ {
+		prev, next := t.centroids[i-1], t.centroids[i]
+		nextCenter := center + (prev.weight+next.weight)/2
+		if index < nextCenter {
+			return prev.mean + (next.mean-prev.mean)*(index-center)/(nextCenter-center)
+		}
+		center = nextCenter
+	}
+
+	last := t.centroids[len(t.centroids)-1]
+	return last.mean + (t.max-last.mean)*(index-center)/(last.weight/2)
+}
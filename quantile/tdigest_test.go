@@ -0,0 +1,182 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewTDigest(t *testing.T) {
+	t.Run("pass: valid TDigest is valid", func(t *testing.T) {
+		digest, err := NewTDigest(DefaultTDigestCompression)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultTDigestCompression, digest.compression)
+		assert.Equal(t, 0., digest.count)
+		assert.Equal(t, math.Inf(1), digest.min)
+		assert.Equal(t, math.Inf(-1), digest.max)
+	})
+
+	t.Run("fail: nonpositive compression is invalid", func(t *testing.T) {
+		_, err := NewTDigest(0)
+		testutil.ContainsError(t, err, fmt.Sprintf("%f is a nonpositive compression", 0.))
+	})
+}
+
+func TestTDigestString(t *testing.T) {
+	digest, err := NewTDigest(50)
+	require.NoError(t, err)
+	assert.Equal(t, "quantile.TDigest_{compression:50}", digest.String())
+}
+
+func TestTDigestPush(t *testing.T) {
+	t.Run("pass: memory stays bounded", func(t *testing.T) {
+		digest, err := NewTDigest(50)
+		require.NoError(t, err)
+
+		for i := 0; i < 100000; i++ {
+			err := digest.Push(float64(i))
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 100000, digest.Count())
+		assert.LessOrEqual(t, len(digest.centroids), int(math.Ceil(math.Pi*50/2)))
+		assert.Less(t, len(digest.buffer), digest.bufferSize())
+	})
+
+	t.Run("fail: NaN returns error", func(t *testing.T) {
+		digest, err := NewTDigest(50)
+		require.NoError(t, err)
+
+		err = digest.Push(math.NaN())
+		testutil.ContainsError(t, err, "cannot push NaN")
+		assert.Equal(t, 0, digest.Count())
+	})
+
+	t.Run("fail: infinite values return error", func(t *testing.T) {
+		digest, err := NewTDigest(50)
+		require.NoError(t, err)
+
+		for _, x := range []float64{1, 2, 3, 4} {
+			err := digest.Push(x)
+			require.NoError(t, err)
+		}
+
+		err = digest.Push(math.Inf(1))
+		testutil.ContainsError(t, err, "cannot push +Inf")
+		err = digest.Push(math.Inf(-1))
+		testutil.ContainsError(t, err, "cannot push -Inf")
+		assert.Equal(t, 4, digest.Count())
+
+		// the rejected values do not skew the estimates
+		value, err := digest.Value(0.5)
+		require.NoError(t, err)
+		testutil.Approx(t, 2.5, value)
+	})
+}
+
+func TestTDigestValue(t *testing.T) {
+	t.Run("pass: estimates quantiles of a normal distribution within bound", func(t *testing.T) {
+		digest, err := NewTDigest(DefaultTDigestCompression)
+		require.NoError(t, err)
+
+		r := rand.New(rand.NewSource(42))
+		n := 100000
+		xs := make([]float64, n)
+		for i := range xs {
+			xs[i] = r.NormFloat64()
+			err := digest.Push(xs[i])
+			require.NoError(t, err)
+		}
+		sort.Float64s(xs)
+
+		// the error is measured in rank, i.e. the fraction of values
+		// lying between the estimate and the exact quantile
+		bounds := map[float64]float64{
+			0.001: 0.0002,
+			0.01:  0.001,
+			0.1:   0.005,
+			0.25:  0.005,
+			0.5:   0.005,
+			0.75:  0.005,
+			0.9:   0.005,
+			0.99:  0.001,
+			0.999: 0.0002,
+		}
+		for q, bound := range bounds {
+			value, err := digest.Value(q)
+			require.NoError(t, err)
+
+			rank := float64(sort.SearchFloat64s(xs, value)) / float64(n)
+			assert.InDelta(t, q, rank, bound, "quantile %v estimated as %v", q, value)
+		}
+	})
+
+	t.Run("pass: single value returns that value", func(t *testing.T) {
+		digest, err := NewTDigest(DefaultTDigestCompression)
+		require.NoError(t, err)
+
+		err = digest.Push(3)
+		require.NoError(t, err)
+
+		value, err := digest.Value(0.9)
+		require.NoError(t, err)
+		assert.Equal(t, 3., value)
+	})
+
+	t.Run("pass: estimates stay within the range of values", func(t *testing.T) {
+		digest, err := NewTDigest(10)
+		require.NoError(t, err)
+
+		for _, x := range []float64{5, 1, 9, 3, 7} {
+			err := digest.Push(x)
+			require.NoError(t, err)
+		}
+
+		for _, q := range []float64{0.01, 0.5, 0.99} {
+			value, err := digest.Value(q)
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, value, 1.)
+			assert.LessOrEqual(t, value, 9.)
+		}
+	})
+
+	t.Run("fail: quantile outside (0, 1) is invalid", func(t *testing.T) {
+		digest, err := NewTDigest(DefaultTDigestCompression)
+		require.NoError(t, err)
+
+		_, err = digest.Value(1)
+		testutil.ContainsError(t, err, fmt.Sprintf("quantile %f not in (0, 1)", 1.))
+	})
+
+	t.Run("fail: if no values seen, return error", func(t *testing.T) {
+		digest, err := NewTDigest(DefaultTDigestCompression)
+		require.NoError(t, err)
+
+		_, err = digest.Value(0.5)
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+}
+
+func TestTDigestClear(t *testing.T) {
+	digest, err := NewTDigest(10)
+	require.NoError(t, err)
+
+	for i := 0.; i < 100; i++ {
+		err := digest.Push(i)
+		require.NoError(t, err)
+	}
+
+	digest.Clear()
+	assert.Equal(t, 0, digest.Count())
+	assert.Empty(t, digest.centroids)
+	assert.Empty(t, digest.buffer)
+	assert.Equal(t, math.Inf(1), digest.min)
+	assert.Equal(t, math.Inf(-1), digest.max)
+}
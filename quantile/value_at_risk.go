@@ -0,0 +1,72 @@
+package quantile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ValueAtRisk keeps track of the value at risk of a stream of returns using order
+// statistics, i.e. the alpha quantile of the returns, negated so that losses are
+// expressed as positive values.
+type ValueAtRisk struct {
+	alpha    float64
+	quantile *Quantile
+}
+
+// NewValueAtRisk instantiates a ValueAtRisk struct.
+func NewValueAtRisk(alpha float64, window int, impl Impl) (*ValueAtRisk, error) {
+	if alpha <= 0 || alpha >= 1 {
+		return nil, errors.Errorf("alpha %f not in (0, 1)", alpha)
+	}
+
+	quantile, err := New(window, ImplOption(impl))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Quantile")
+	}
+
+	return &ValueAtRisk{
+		alpha:    alpha,
+		quantile: quantile,
+	}, nil
+}
+
+// NewGlobalValueAtRisk instantiates a global ValueAtRisk struct.
+// This is equivalent to calling NewValueAtRisk(alpha, 0, impl).
+func NewGlobalValueAtRisk(alpha float64, impl Impl) (*ValueAtRisk, error) {
+	return NewValueAtRisk(alpha, 0, impl)
+}
+
+// String returns a string representation of the metric.
+func (v *ValueAtRisk) String() string {
+	name := "quantile.ValueAtRisk"
+	params := []string{
+		fmt.Sprintf("alpha:%v", v.alpha),
+		fmt.Sprintf("quantile:%v", v.quantile.String()),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a number for calculating the value at risk.
+func (v *ValueAtRisk) Push(x float64) error {
+	err := v.quantile.Push(x)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing %f to Quantile", x)
+	}
+	return nil
+}
+
+// Value returns the value of the value at risk.
+func (v *ValueAtRisk) Value() (float64, error) {
+	value, err := v.quantile.Value(v.alpha)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving quantile: %w", err)
+	}
+	return -value, nil
+}
+
+// Clear resets the metric.
+func (v *ValueAtRisk) Clear() {
+	v.quantile.Clear()
+}
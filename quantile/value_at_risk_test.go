@@ -0,0 +1,134 @@
+package quantile
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/K4Mobility/stream/quantile/ost/rb"
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewValueAtRisk(t *testing.T) {
+	t.Run("pass: valid ValueAtRisk is valid", func(t *testing.T) {
+		v, err := NewValueAtRisk(0.05, 3, RedBlack)
+		require.NoError(t, err)
+		assert.Equal(t, 0.05, v.alpha)
+		assert.Equal(t, 3, v.quantile.window)
+		_, ok := v.quantile.statistic.(*rb.Tree)
+		assert.True(t, ok)
+	})
+
+	t.Run("fail: alpha outside (0, 1) is invalid", func(t *testing.T) {
+		_, err := NewValueAtRisk(0, 3, AVL)
+		testutil.ContainsError(t, err, fmt.Sprintf("alpha %f not in (0, 1)", 0.))
+
+		_, err = NewValueAtRisk(1, 3, AVL)
+		testutil.ContainsError(t, err, fmt.Sprintf("alpha %f not in (0, 1)", 1.))
+	})
+
+	t.Run("fail: invalid Impl is invalid", func(t *testing.T) {
+		_, err := NewValueAtRisk(0.05, 3, -1)
+		testutil.ContainsError(t, err, "error creating Quantile")
+	})
+}
+
+func TestNewGlobalValueAtRisk(t *testing.T) {
+	v, err := NewValueAtRisk(0.05, 0, AVL)
+	require.NoError(t, err)
+
+	globalV, err := NewGlobalValueAtRisk(0.05, AVL)
+	require.NoError(t, err)
+
+	assert.Equal(t, v, globalV)
+}
+
+func TestValueAtRiskString(t *testing.T) {
+	expectedString := fmt.Sprintf(
+		"quantile.ValueAtRisk_{alpha:0.05,quantile:quantile.Quantile_{window:3,interpolation:%d}}",
+		Linear,
+	)
+	v, err := NewValueAtRisk(0.05, 3, AVL)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedString, v.String())
+}
+
+func TestValueAtRiskPush(t *testing.T) {
+	t.Run("pass: successfully pushes values", func(t *testing.T) {
+		v, err := NewValueAtRisk(0.5, 3, AVL)
+		require.NoError(t, err)
+		for i := 0.; i < 5; i++ {
+			err := v.Push(i)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, v.quantile.statistic.Size())
+	})
+
+	t.Run("fail: if queue insertion fails, return error", func(t *testing.T) {
+		v, err := NewValueAtRisk(0.5, 3, AVL)
+		require.NoError(t, err)
+
+		// dispose the queue to simulate an error when we try to insert into the queue
+		v.quantile.queue.Dispose()
+		val := 3.
+		err = v.Push(val)
+		testutil.ContainsError(t, err, fmt.Sprintf("error pushing %f to Quantile", val))
+	})
+}
+
+func TestValueAtRiskValue(t *testing.T) {
+	for _, impl := range []Impl{AVL, RedBlack, SkipList} {
+		t.Run(fmt.Sprintf("pass: returns negated alpha quantile for Impl %d", impl), func(t *testing.T) {
+			v, err := NewGlobalValueAtRisk(0.2, impl)
+			require.NoError(t, err)
+			for _, x := range []float64{5, -3, 8, 1, -7, 2, 9, 4, 6, 3} {
+				err = v.Push(x)
+				require.NoError(t, err)
+			}
+
+			// 0.2 quantile interpolates between -3 and 1
+			value, err := v.Value()
+			require.NoError(t, err)
+			testutil.Approx(t, -0.2, value)
+		})
+	}
+
+	t.Run("pass: returns negated alpha quantile over window", func(t *testing.T) {
+		v, err := NewValueAtRisk(0.5, 5, AVL)
+		require.NoError(t, err)
+		for i := 0.; i < 10; i++ {
+			err = v.Push(i)
+			require.NoError(t, err)
+		}
+
+		value, err := v.Value()
+		require.NoError(t, err)
+		testutil.Approx(t, -7, value)
+	})
+
+	t.Run("fail: if no values seen, return error", func(t *testing.T) {
+		v, err := NewValueAtRisk(0.5, 3, AVL)
+		require.NoError(t, err)
+
+		_, err = v.Value()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+}
+
+func TestValueAtRiskClear(t *testing.T) {
+	v, err := NewValueAtRisk(0.5, 3, AVL)
+	require.NoError(t, err)
+
+	for i := 0.; i < 10; i++ {
+		err = v.Push(i * i)
+		require.NoError(t, err)
+	}
+
+	v.Clear()
+	assert.Equal(t, uint64(0), v.quantile.queue.Len())
+	assert.Equal(t, 0, v.quantile.statistic.Size())
+}
@@ -0,0 +1,94 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ReadOption is an optional argument for PushAll, which sets
+// how values are read from the io.Reader.
+type ReadOption func(*readConfig)
+
+type readConfig struct {
+	binary bool
+}
+
+// BinaryOption creates an option that reads values as consecutive little-endian
+// IEEE 754 float64s (i.e. 8 bytes each), rather than as text.
+func BinaryOption() ReadOption {
+	return func(c *readConfig) {
+		c.binary = true
+	}
+}
+
+// PushAll reads values from the io.Reader and pushes each of them to the metric, in order.
+// By default the values are read as text, one number per line (blank lines are skipped);
+// see BinaryOption for reading binary values instead. It stops at the first malformed
+// value or failed push, and returns the number of values pushed along with the error.
+func PushAll(m Metric, r io.Reader, options ...ReadOption) (int, error) {
+	config := &readConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	if config.binary {
+		return pushBinary(m, r)
+	}
+	return pushText(m, r)
+}
+
+func pushText(m Metric, r io.Reader) (int, error) {
+	count := 0
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		token := strings.TrimSpace(scanner.Text())
+		if token == "" {
+			continue
+		}
+
+		x, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return count, errors.Errorf("malformed value %q on line %d", token, line)
+		}
+
+		err = m.Push(x)
+		if err != nil {
+			return count, errors.Wrapf(err, "error pushing %f to metric", x)
+		}
+		count++
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return count, errors.Wrap(err, "error reading values")
+	}
+	return count, nil
+}
+
+func pushBinary(m Metric, r io.Reader) (int, error) {
+	count := 0
+	buf := make([]byte, 8)
+	for {
+		_, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			return count, nil
+		} else if err == io.ErrUnexpectedEOF {
+			return count, errors.Errorf("truncated value after %d values", count)
+		} else if err != nil {
+			return count, errors.Wrap(err, "error reading values")
+		}
+
+		x := math.Float64frombits(binary.LittleEndian.Uint64(buf))
+		err = m.Push(x)
+		if err != nil {
+			return count, errors.Wrapf(err, "error pushing %f to metric", x)
+		}
+		count++
+	}
+}
@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMetric records the values pushed to it, failing once it holds limit values if limit > 0.
+type mockMetric struct {
+	xs    []float64
+	limit int
+}
+
+func (m *mockMetric) Push(x float64) error {
+	if m.limit > 0 && len(m.xs) == m.limit {
+		return errors.New("metric is full")
+	}
+	m.xs = append(m.xs, x)
+	return nil
+}
+
+func (m *mockMetric) String() string { return "mockMetric" }
+
+func (m *mockMetric) Clear() { m.xs = nil }
+
+func binaryValues(t *testing.T, xs ...float64) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	err := binary.Write(buf, binary.LittleEndian, xs)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestPushAll(t *testing.T) {
+	t.Run("pass: pushes one value per line of text", func(t *testing.T) {
+		m := &mockMetric{}
+		n, err := PushAll(m, strings.NewReader("1\n-2.5\n\n  3e2 \n+Inf\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+		assert.Equal(t, []float64{1, -2.5, 300, math.Inf(1)}, m.xs)
+	})
+
+	t.Run("pass: pushes binary little-endian values", func(t *testing.T) {
+		m := &mockMetric{}
+		n, err := PushAll(m, binaryValues(t, 1, -2.5, 300), BinaryOption())
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.Equal(t, []float64{1, -2.5, 300}, m.xs)
+	})
+
+	t.Run("pass: empty reader pushes nothing", func(t *testing.T) {
+		m := &mockMetric{}
+		n, err := PushAll(m, strings.NewReader(""))
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		n, err = PushAll(m, &bytes.Buffer{}, BinaryOption())
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.Empty(t, m.xs)
+	})
+
+	t.Run("fail: stops at the first malformed line", func(t *testing.T) {
+		m := &mockMetric{}
+		n, err := PushAll(m, strings.NewReader("1\n2\nthree\n4\n"))
+		assert.EqualError(t, err, `malformed value "three" on line 3`)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []float64{1, 2}, m.xs)
+	})
+
+	t.Run("fail: stops at a truncated binary value", func(t *testing.T) {
+		m := &mockMetric{}
+		buf := binaryValues(t, 1, 2)
+		buf.Write([]byte{0, 1, 2})
+
+		n, err := PushAll(m, buf, BinaryOption())
+		assert.EqualError(t, err, "truncated value after 2 values")
+		assert.Equal(t, 2, n)
+	})
+
+	t.Run("fail: stops at the first failed push", func(t *testing.T) {
+		m := &mockMetric{limit: 2}
+		n, err := PushAll(m, strings.NewReader("1\n2\n3\n"))
+		assert.EqualError(t, err, "error pushing 3.000000 to metric: metric is full")
+		assert.Equal(t, 2, n)
+
+		m = &mockMetric{limit: 1}
+		n, err = PushAll(m, binaryValues(t, 1, 2), BinaryOption())
+		assert.EqualError(t, err, "error pushing 2.000000 to metric: metric is full")
+		assert.Equal(t, 1, n)
+	})
+}
@@ -5,14 +5,27 @@ import (
 	"sync"
 )
 
-// Reservoir is a struct for performing reservoir sampling on a stream.
+// Reservoir is a struct for performing reservoir sampling on a stream,
+// i.e. keeping a uniform random sample of up to Size values (using Algorithm R).
+// By default it uses the global source of random numbers; use NewReservoir to
+// provide a source instead, e.g. to obtain reproducible samples.
 type Reservoir struct {
 	Size   int
 	sample []interface{}
 	count  int
+	rand   *rand.Rand
 	mux    sync.Mutex
 }
 
+// NewReservoir instantiates a Reservoir struct which keeps a sample of
+// up to size values, using src as its source of random numbers.
+func NewReservoir(size int, src rand.Source) *Reservoir {
+	return &Reservoir{
+		Size: size,
+		rand: rand.New(src),
+	}
+}
+
 // Push consumes a value to perform reservoir sampling.
 func (r *Reservoir) Push(x interface{}) {
 	r.mux.Lock()
@@ -21,7 +34,7 @@ func (r *Reservoir) Push(x interface{}) {
 	r.count++
 	if r.count <= r.Size {
 		r.sample = append(r.sample, x)
-	} else if index := rand.Intn(r.count); index < r.Size {
+	} else if index := r.intn(r.count); index < r.Size {
 		r.sample[index] = x
 	}
 }
@@ -35,3 +48,12 @@ func (r *Reservoir) Sample() []interface{} {
 	copy(sample, r.sample)
 	return sample
 }
+
+// intn returns a random integer in [0, n) from the source
+// of the reservoir, or from the global source if it has none.
+func (r *Reservoir) intn(n int) int {
+	if r.rand == nil {
+		return rand.Intn(n)
+	}
+	return r.rand.Intn(n)
+}
@@ -33,3 +33,48 @@ func TestSample(t *testing.T) {
 
 	assert.Equal(t, []interface{}{6, 7, 4}, r.sample)
 }
+
+func TestNewReservoir(t *testing.T) {
+	t.Run("pass: same source gives the same sample", func(t *testing.T) {
+		r1 := NewReservoir(3, rand.NewSource(42))
+		r2 := NewReservoir(3, rand.NewSource(42))
+		for i := 0; i < 100; i++ {
+			r1.Push(float64(i))
+			r2.Push(float64(i))
+		}
+
+		assert.Equal(t, 3, r1.Size)
+		assert.Len(t, r1.Sample(), 3)
+		assert.Equal(t, r1.Sample(), r2.Sample())
+	})
+
+	t.Run("pass: keeps every value while there are fewer than Size", func(t *testing.T) {
+		r := NewReservoir(5, rand.NewSource(1))
+		for i := 0; i < 3; i++ {
+			r.Push(float64(i))
+		}
+		assert.Equal(t, []interface{}{0., 1., 2.}, r.Sample())
+	})
+}
+
+func TestReservoirInclusionProbability(t *testing.T) {
+	size, n, runs := 5, 20, 20000
+	src := rand.NewSource(1)
+
+	counts := make([]int, n)
+	for run := 0; run < runs; run++ {
+		r := NewReservoir(size, src)
+		for i := 0; i < n; i++ {
+			r.Push(i)
+		}
+		for _, x := range r.Sample() {
+			counts[x.(int)]++
+		}
+	}
+
+	// each value should be included with probability size / n, and the
+	// standard error of each estimate is about 0.003 here
+	for i, count := range counts {
+		assert.InDelta(t, float64(size)/float64(n), float64(count)/float64(runs), 0.015, "value %d", i)
+	}
+}
@@ -0,0 +1,115 @@
+package signal
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gammazero/deque"
+	"github.com/pkg/errors"
+)
+
+// CrossingCount keeps track of the number of times a stream crosses a reference
+// level, in either direction. A crossing occurs whenever two consecutive values lie
+// on different sides of the level; values equal to the level are considered to be
+// above it.
+type CrossingCount struct {
+	level  float64
+	window int
+	mux    sync.Mutex
+	// Used if window > 0; tracks which side of the level each value lies on
+	sides *deque.Deque[bool]
+	// Used if window == 0
+	last      bool
+	count     int
+	crossings int
+}
+
+// NewCrossingCount instantiates a CrossingCount struct.
+func NewCrossingCount(level float64, window int) (*CrossingCount, error) {
+	if window < 0 {
+		return nil, errors.Errorf("%d is a negative window", window)
+	}
+
+	return &CrossingCount{
+		level:  level,
+		window: window,
+		sides:  deque.New[bool](),
+	}, nil
+}
+
+// NewGlobalCrossingCount instantiates a global CrossingCount struct.
+// This is equivalent to calling NewCrossingCount(level, 0).
+func NewGlobalCrossingCount(level float64) *CrossingCount {
+	return &CrossingCount{
+		level:  level,
+		window: 0,
+		sides:  deque.New[bool](),
+	}
+}
+
+// String returns a string representation of the metric.
+func (c *CrossingCount) String() string {
+	name := "signal.CrossingCount"
+	params := []string{
+		fmt.Sprintf("level:%v", c.level),
+		fmt.Sprintf("window:%v", c.window),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Push adds a number for counting crossings.
+func (c *CrossingCount) Push(x float64) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	above := x >= c.level
+	if c.window != 0 {
+		if c.sides.Len() == c.window {
+			// evicting the oldest value also drops the crossing
+			// between it and the value that followed it, if any
+			oldest := c.sides.PopFront()
+			if c.sides.Len() > 0 && c.sides.Front() != oldest {
+				c.crossings--
+			}
+			c.count--
+		}
+
+		if c.sides.Len() > 0 && c.sides.Back() != above {
+			c.crossings++
+		}
+		c.sides.PushBack(above)
+	} else if c.count > 0 && c.last != above {
+		c.crossings++
+	}
+
+	c.last = above
+	c.count++
+	return nil
+}
+
+// Value returns the number of crossings.
+func (c *CrossingCount) Value() (float64, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.count == 0 {
+		return 0, ErrorNoValuesSeen
+	}
+
+	return float64(c.crossings), nil
+}
+
+// Clear resets the metric.
+func (c *CrossingCount) Clear() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.last = false
+	c.count = 0
+	c.crossings = 0
+	c.sides = deque.New[bool]()
+}
+
+// ErrorNoValuesSeen is returned by metrics that have not seen any values yet,
+// and can be matched with errors.Is.
+var ErrorNoValuesSeen = errors.New("no values seen yet")
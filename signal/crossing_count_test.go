@@ -0,0 +1,114 @@
+package signal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewCrossingCount(t *testing.T) {
+	t.Run("pass: valid CrossingCount is valid", func(t *testing.T) {
+		c, err := NewCrossingCount(1.5, 3)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1.5, c.level)
+		assert.Equal(t, 3, c.window)
+		assert.Equal(t, 0, c.sides.Len())
+		assert.Equal(t, 0, c.count)
+		assert.Equal(t, 0, c.crossings)
+	})
+
+	t.Run("fail: negative window returns error", func(t *testing.T) {
+		_, err := NewCrossingCount(0, -1)
+		testutil.ContainsError(t, err, "-1 is a negative window")
+	})
+}
+
+func TestNewGlobalCrossingCount(t *testing.T) {
+	c, err := NewCrossingCount(1.5, 0)
+	require.NoError(t, err)
+
+	globalC := NewGlobalCrossingCount(1.5)
+	assert.Equal(t, c, globalC)
+}
+
+func TestCrossingCountString(t *testing.T) {
+	expectedString := "signal.CrossingCount_{level:1.5,window:3}"
+	c, err := NewCrossingCount(1.5, 3)
+	require.NoError(t, err)
+	assert.Equal(t, expectedString, c.String())
+}
+
+func TestCrossingCountValue(t *testing.T) {
+	vals := []float64{1, -1, 2, 3, -4, -5, 6}
+
+	t.Run("pass: counts crossings over a window", func(t *testing.T) {
+		c, err := NewCrossingCount(0, 3)
+		require.NoError(t, err)
+
+		expected := []float64{0, 1, 2, 1, 1, 1, 1}
+		for i, x := range vals {
+			err := c.Push(x)
+			require.NoError(t, err)
+
+			value, err := c.Value()
+			require.NoError(t, err)
+			assert.Equal(t, expected[i], value, "value differs after push %d", i)
+		}
+		assert.Equal(t, 3, c.sides.Len())
+	})
+
+	t.Run("pass: counts crossings globally", func(t *testing.T) {
+		c := NewGlobalCrossingCount(0)
+
+		expected := []float64{0, 1, 2, 2, 3, 3, 4}
+		for i, x := range vals {
+			err := c.Push(x)
+			require.NoError(t, err)
+
+			value, err := c.Value()
+			require.NoError(t, err)
+			assert.Equal(t, expected[i], value, "value differs after push %d", i)
+		}
+		assert.Equal(t, 0, c.sides.Len())
+	})
+
+	t.Run("pass: values equal to the level count as above", func(t *testing.T) {
+		c := NewGlobalCrossingCount(2)
+		for _, x := range []float64{3, 2, 2, 1} {
+			err := c.Push(x)
+			require.NoError(t, err)
+		}
+
+		value, err := c.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 1., value)
+	})
+
+	t.Run("fail: if no values seen, return error", func(t *testing.T) {
+		c, err := NewCrossingCount(0, 3)
+		require.NoError(t, err)
+
+		_, err = c.Value()
+		testutil.ContainsError(t, err, "no values seen yet")
+	})
+}
+
+func TestCrossingCountClear(t *testing.T) {
+	c, err := NewCrossingCount(0, 3)
+	require.NoError(t, err)
+
+	for _, x := range []float64{1, -1, 1, -1} {
+		err := c.Push(x)
+		require.NoError(t, err)
+	}
+
+	c.Clear()
+	assert.Equal(t, 0, c.sides.Len())
+	assert.Equal(t, 0, c.count)
+	assert.Equal(t, 0, c.crossings)
+	assert.False(t, c.last)
+}
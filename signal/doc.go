@@ -0,0 +1,3 @@
+// Package signal provides a library of data structures/algorithms
+// for calculating online signal features from a stream of data.
+package signal
@@ -0,0 +1,168 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultSeed is the default seed for the hash functions of a CountMin sketch.
+const DefaultSeed uint64 = 0
+
+// Option is an optional argument for configuring a CountMin sketch.
+type Option func(*CountMin)
+
+// SeedOption creates an option that sets the seed from which the hash
+// functions of a CountMin sketch are derived. Sketches created with the
+// same seed (and the same epsilon and delta) hash keys identically.
+func SeedOption(seed uint64) Option {
+	return func(c *CountMin) {
+		c.seed = seed
+	}
+}
+
+// CountMin keeps track of approximate frequencies of keys in a stream with a
+// count-min sketch. Given an error factor epsilon and a failure probability delta,
+// each estimate is never less than the true count, and exceeds it by at most
+// epsilon * total with probability at least 1 - delta, where total is the sum
+// of all counts added.
+type CountMin struct {
+	epsilon float64
+	delta   float64
+	seed    uint64
+	width   int
+	depth   int
+	mux     sync.RWMutex
+	// one seed per row; row i hashes keys with seeds[i]
+	seeds  []uint64
+	counts [][]uint64
+	total  uint64
+}
+
+// NewCountMin instantiates a CountMin struct, with a width of ceil(e / epsilon)
+// and a depth of ceil(ln(1 / delta)).
+func NewCountMin(epsilon float64, delta float64, options ...Option) (*CountMin, error) {
+	if epsilon <= 0 || epsilon >= 1 {
+		return nil, errors.Errorf("epsilon %f not in (0, 1)", epsilon)
+	} else if delta <= 0 || delta >= 1 {
+		return nil, errors.Errorf("delta %f not in (0, 1)", delta)
+	}
+
+	c := &CountMin{
+		epsilon: epsilon,
+		delta:   delta,
+		seed:    DefaultSeed,
+		width:   int(math.Ceil(math.E / epsilon)),
+		depth:   int(math.Ceil(math.Log(1 / delta))),
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	c.seeds = make([]uint64, c.depth)
+	state := c.seed
+	for i := range c.seeds {
+		state += 0x9e3779b97f4a7c15
+		c.seeds[i] = mix(state)
+	}
+
+	c.counts = make([][]uint64, c.depth)
+	for i := range c.counts {
+		c.counts[i] = make([]uint64, c.width)
+	}
+
+	return c, nil
+}
+
+// String returns a string representation of the metric.
+func (c *CountMin) String() string {
+	name := "sketch.CountMin"
+	params := []string{
+		fmt.Sprintf("epsilon:%v", c.epsilon),
+		fmt.Sprintf("delta:%v", c.delta),
+		fmt.Sprintf("seed:%v", c.seed),
+	}
+	return fmt.Sprintf("%s_{%s}", name, strings.Join(params, ","))
+}
+
+// Width returns the number of counters in each row of the sketch.
+func (c *CountMin) Width() int {
+	return c.width
+}
+
+// Depth returns the number of rows (i.e. hash functions) of the sketch.
+func (c *CountMin) Depth() int {
+	return c.depth
+}
+
+// Add adds count occurrences of key to the sketch.
+func (c *CountMin) Add(key []byte, count uint64) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for i, row := range c.counts {
+		row[c.index(i, key)] += count
+	}
+	c.total += count
+}
+
+// Estimate returns the estimated number of occurrences of key.
+func (c *CountMin) Estimate(key []byte) uint64 {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	estimate := uint64(math.MaxUint64)
+	for i, row := range c.counts {
+		if count := row[c.index(i, key)]; count < estimate {
+			estimate = count
+		}
+	}
+	return estimate
+}
+
+// Total returns the sum of all counts added to the sketch.
+func (c *CountMin) Total() uint64 {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.total
+}
+
+// Clear resets the sketch; the hash functions are kept.
+func (c *CountMin) Clear() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, row := range c.counts {
+		for j := range row {
+			row[j] = 0
+		}
+	}
+	c.total = 0
+}
+
+// index returns the column that key hashes to in row i.
+func (c *CountMin) index(i int, key []byte) int {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], c.seeds[i])
+
+	h := fnv.New64a()
+	// writes to a hash.Hash never return an error
+	_, _ = h.Write(buf[:])
+	_, _ = h.Write(key)
+
+	return int(mix(h.Sum64()) % uint64(c.width))
+}
+
+// mix is the finalizer of the SplitMix64 generator; it spreads the bits of x
+// so that taking the result modulo the width is close to uniform.
+func mix(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,125 @@
+package sketch
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/K4Mobility/stream/util/test"
+)
+
+func TestNewCountMin(t *testing.T) {
+	t.Run("pass: sizes width and depth from epsilon and delta", func(t *testing.T) {
+		c, err := NewCountMin(0.01, 0.01)
+		require.NoError(t, err)
+		assert.Equal(t, 272, c.Width())
+		assert.Equal(t, 5, c.Depth())
+		assert.Equal(t, DefaultSeed, c.seed)
+	})
+
+	t.Run("pass: SeedOption sets the seed", func(t *testing.T) {
+		c, err := NewCountMin(0.01, 0.01, SeedOption(7))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), c.seed)
+	})
+
+	t.Run("fail: epsilon outside (0, 1) is invalid", func(t *testing.T) {
+		_, err := NewCountMin(0, 0.01)
+		testutil.ContainsError(t, err, "epsilon 0.000000 not in (0, 1)")
+
+		_, err = NewCountMin(1, 0.01)
+		testutil.ContainsError(t, err, "epsilon 1.000000 not in (0, 1)")
+	})
+
+	t.Run("fail: delta outside (0, 1) is invalid", func(t *testing.T) {
+		_, err := NewCountMin(0.01, 0)
+		testutil.ContainsError(t, err, "delta 0.000000 not in (0, 1)")
+
+		_, err = NewCountMin(0.01, 1)
+		testutil.ContainsError(t, err, "delta 1.000000 not in (0, 1)")
+	})
+}
+
+func TestCountMinString(t *testing.T) {
+	c, err := NewCountMin(0.01, 0.05, SeedOption(3))
+	require.NoError(t, err)
+	assert.Equal(t, "sketch.CountMin_{epsilon:0.01,delta:0.05,seed:3}", c.String())
+}
+
+func TestCountMinSeed(t *testing.T) {
+	c1, err := NewCountMin(0.01, 0.01, SeedOption(42))
+	require.NoError(t, err)
+	c2, err := NewCountMin(0.01, 0.01, SeedOption(42))
+	require.NoError(t, err)
+	c3, err := NewCountMin(0.01, 0.01, SeedOption(43))
+	require.NoError(t, err)
+
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		c1.Add(key, uint64(i))
+		c2.Add(key, uint64(i))
+		c3.Add(key, uint64(i))
+	}
+
+	assert.Equal(t, c1.counts, c2.counts)
+	assert.NotEqual(t, c1.counts, c3.counts)
+}
+
+func TestCountMinEstimate(t *testing.T) {
+	t.Run("pass: single key is exact", func(t *testing.T) {
+		c, err := NewCountMin(0.01, 0.01)
+		require.NoError(t, err)
+
+		c.Add([]byte("a"), 3)
+		c.Add([]byte("a"), 4)
+		assert.Equal(t, uint64(7), c.Estimate([]byte("a")))
+		assert.Equal(t, uint64(7), c.Total())
+	})
+
+	t.Run("pass: unseen key on an empty sketch is zero", func(t *testing.T) {
+		c, err := NewCountMin(0.01, 0.01)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), c.Estimate([]byte("a")))
+	})
+
+	t.Run("pass: estimates are bounded across many keys", func(t *testing.T) {
+		epsilon, delta := 0.001, 0.01
+		c, err := NewCountMin(epsilon, delta, SeedOption(1))
+		require.NoError(t, err)
+
+		rng := rand.New(rand.NewSource(1))
+		zipf := rand.NewZipf(rng, 1.1, 1, 99999)
+		truth := map[string]uint64{}
+		for i := 0; i < 200000; i++ {
+			key := fmt.Sprintf("key-%d", zipf.Uint64())
+			count := uint64(rng.Intn(5) + 1)
+			truth[key] += count
+			c.Add([]byte(key), count)
+		}
+
+		bound := epsilon * float64(c.Total())
+		failures := 0
+		for key, count := range truth {
+			estimate := c.Estimate([]byte(key))
+			require.GreaterOrEqual(t, estimate, count, "underestimated %s", key)
+			if float64(estimate-count) > bound {
+				failures++
+			}
+		}
+
+		assert.LessOrEqual(t, float64(failures), delta*float64(len(truth)))
+	})
+}
+
+func TestCountMinClear(t *testing.T) {
+	c, err := NewCountMin(0.1, 0.1)
+	require.NoError(t, err)
+
+	c.Add([]byte("a"), 5)
+	c.Clear()
+	assert.Equal(t, uint64(0), c.Estimate([]byte("a")))
+	assert.Equal(t, uint64(0), c.Total())
+}
@@ -0,0 +1,3 @@
+// Package sketch provides a library of data structures/algorithms
+// for calculating approximate summaries of categorical data from a stream.
+package sketch
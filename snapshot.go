@@ -0,0 +1,21 @@
+package stream
+
+// SnapshotAll reads the value of every provided metric. Unlike
+// an aggregate metric's Values method, a failure to read one metric
+// does not prevent the others from being read; values are returned
+// for every metric that succeeded, and errors are returned for every
+// metric that failed, each keyed by the same name as in the input map.
+func SnapshotAll(metrics map[string]Valuable) (map[string]float64, map[string]error) {
+	values := map[string]float64{}
+	errs := map[string]error{}
+	for name, metric := range metrics {
+		val, err := metric.Value()
+		if err != nil {
+			errs[name] = err
+		} else {
+			values[name] = val
+		}
+	}
+
+	return values, errs
+}
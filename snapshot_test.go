@@ -0,0 +1,47 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockValuable struct {
+	val float64
+	err error
+}
+
+func (m *mockValuable) Value() (float64, error) {
+	return m.val, m.err
+}
+
+func TestSnapshotAll(t *testing.T) {
+	t.Run("pass: returns values for all metrics", func(t *testing.T) {
+		values, errs := SnapshotAll(map[string]Valuable{
+			"a": &mockValuable{val: 1},
+			"b": &mockValuable{val: 2},
+		})
+
+		assert.Equal(t, map[string]float64{"a": 1, "b": 2}, values)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("pass: failing metrics do not prevent others from being read", func(t *testing.T) {
+		err := errors.New("no values seen yet")
+		values, errs := SnapshotAll(map[string]Valuable{
+			"a": &mockValuable{val: 1},
+			"b": &mockValuable{err: err},
+			"c": &mockValuable{val: 3},
+		})
+
+		assert.Equal(t, map[string]float64{"a": 1, "c": 3}, values)
+		assert.Equal(t, map[string]error{"b": err}, errs)
+	})
+
+	t.Run("pass: no metrics returns empty maps", func(t *testing.T) {
+		values, errs := SnapshotAll(map[string]Valuable{})
+		assert.Empty(t, values)
+		assert.Empty(t, errs)
+	})
+}
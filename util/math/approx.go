@@ -0,0 +1,18 @@
+package math
+
+import "math"
+
+// ApproxEqual returns whether two floats agree to the given number of decimal
+// places, i.e. whether they differ by at most half a unit in the last place
+// kept (0.5 * 10^-digits); a difference of exactly half a unit counts as equal.
+// Infinities are only equal to themselves, and NaN is equal to NaN, so that
+// states holding non-finite values can still be compared.
+func ApproxEqual(x float64, y float64, digits int) bool {
+	switch {
+	case math.IsNaN(x) || math.IsNaN(y):
+		return math.IsNaN(x) && math.IsNaN(y)
+	case math.IsInf(x, 0) || math.IsInf(y, 0):
+		return x == y
+	}
+	return math.Abs(x-y) <= 0.5*math.Pow10(-digits)
+}
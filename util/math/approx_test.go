@@ -0,0 +1,43 @@
+package math
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApproxEqual(t *testing.T) {
+	t.Run("pass: values within half a unit are equal", func(t *testing.T) {
+		assert.True(t, ApproxEqual(1, 1, 9))
+		assert.True(t, ApproxEqual(1, 1+1e-10, 9))
+		assert.True(t, ApproxEqual(-3.25, -3.2500000004, 9))
+		assert.True(t, ApproxEqual(1.23, 1.2, 1))
+	})
+
+	t.Run("pass: a difference of exactly half a unit is equal", func(t *testing.T) {
+		assert.True(t, ApproxEqual(1, 1.5, 0))
+		assert.True(t, ApproxEqual(0, 5e-10, 9))
+		assert.True(t, ApproxEqual(0, 5, -1))
+	})
+
+	t.Run("fail: a difference beyond half a unit is not equal", func(t *testing.T) {
+		assert.False(t, ApproxEqual(1, 1.5000001, 0))
+		assert.False(t, ApproxEqual(0, 6e-10, 9))
+		assert.False(t, ApproxEqual(0, 6, -1))
+		assert.False(t, ApproxEqual(1, 1+1e-9, 9))
+	})
+
+	t.Run("pass: more digits are stricter", func(t *testing.T) {
+		assert.True(t, ApproxEqual(1, 1.001, 2))
+		assert.False(t, ApproxEqual(1, 1.001, 3))
+	})
+
+	t.Run("pass: non-finite values are only equal to themselves", func(t *testing.T) {
+		assert.True(t, ApproxEqual(math.NaN(), math.NaN(), 9))
+		assert.True(t, ApproxEqual(math.Inf(1), math.Inf(1), 9))
+		assert.False(t, ApproxEqual(math.Inf(1), math.Inf(-1), 9))
+		assert.False(t, ApproxEqual(math.Inf(1), math.MaxFloat64, 9))
+		assert.False(t, ApproxEqual(math.NaN(), 0, 9))
+	})
+}
@@ -1,5 +1,7 @@
 package math
 
+import "math/big"
+
 var factorials = []int{1, 1, 2, 6, 24, 120, 720, 5040}
 
 func factorial(n int) int {
@@ -20,7 +22,12 @@ func Sign(n int) int {
 	return -1
 }
 
-// Binom returns the binomial coefficient.
+// MaxBinom is the largest n for which Binom(n, k) is exact, since
+// factorials of anything larger overflow a 64-bit int.
+const MaxBinom = 20
+
+// Binom returns the binomial coefficient. This overflows for
+// n > MaxBinom, so use BinomBig or BinomFloat for larger n.
 func Binom(n, k int) int {
 	if k == 0 || k == n {
 		return 1
@@ -28,3 +35,19 @@ func Binom(n, k int) int {
 
 	return factorial(n) / (factorial(k) * factorial(n-k))
 }
+
+// BinomBig returns the binomial coefficient as a big.Int, which cannot overflow.
+func BinomBig(n, k int) *big.Int {
+	return new(big.Int).Binomial(int64(n), int64(k))
+}
+
+// BinomFloat returns the binomial coefficient as a float64, using
+// BinomBig rather than Binom when n is too large for Binom to be exact.
+func BinomFloat(n, k int) float64 {
+	if n <= MaxBinom {
+		return float64(Binom(n, k))
+	}
+
+	f, _ := new(big.Float).SetInt(BinomBig(n, k)).Float64()
+	return f
+}
@@ -21,3 +21,17 @@ func TestBinom(t *testing.T) {
 	assert.Equal(t, 20, Binom(20, 1))
 	assert.Equal(t, 1, Binom(1500, 0))
 }
+
+func TestBinomBig(t *testing.T) {
+	assert.Equal(t, "10", BinomBig(5, 2).String())
+	assert.Equal(t, "155117520", BinomBig(30, 15).String())
+	assert.Equal(t, "100891344545564193334812497256", BinomBig(100, 50).String())
+}
+
+func TestBinomFloat(t *testing.T) {
+	assert.Equal(t, 10., BinomFloat(5, 2))
+	assert.Equal(t, 184756., BinomFloat(MaxBinom, MaxBinom/2))
+	// these overflow Binom
+	assert.Equal(t, 155117520., BinomFloat(30, 15))
+	assert.InEpsilon(t, 1.0089134454556419e29, BinomFloat(100, 50), 1e-15)
+}
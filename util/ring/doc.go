@@ -0,0 +1,3 @@
+// Package ring provides a typed, fixed-size FIFO queue for the
+// rolling windows kept by the Cores.
+package ring
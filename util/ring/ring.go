@@ -0,0 +1,108 @@
+package ring
+
+import "github.com/pkg/errors"
+
+var (
+	// ErrorDisposed is returned by Put and Get on a disposed Buffer.
+	ErrorDisposed = errors.New("ring: disposed")
+	// ErrorFull is returned by Put on a Buffer that is at capacity.
+	ErrorFull = errors.New("ring: full")
+	// ErrorEmpty is returned by Get on a Buffer with no items.
+	ErrorEmpty = errors.New("ring: empty")
+)
+
+// Queue is the interface for the FIFO queue backing a rolling window.
+type Queue[T any] interface {
+	Put(T) error
+	Get() (T, error)
+	Len() uint64
+	Do(func(T))
+	Dispose()
+	Reset()
+}
+
+// Buffer is a fixed-size FIFO queue that stores its items in a preallocated slice,
+// so that (unlike a queue of interface{} values) putting an item does not allocate.
+// Rather than blocking, Put errors when the Buffer is full and Get errors when it is
+// empty. It is not safe for concurrent use; the Cores using it hold their own locks.
+type Buffer[T any] struct {
+	items    []T
+	head     int
+	size     int
+	disposed bool
+}
+
+// New instantiates a Buffer holding at most size items.
+func New[T any](size uint64) *Buffer[T] {
+	return &Buffer[T]{items: make([]T, size)}
+}
+
+// Put adds an item to the back of the Buffer.
+func (b *Buffer[T]) Put(item T) error {
+	if b.disposed {
+		return ErrorDisposed
+	} else if b.size == len(b.items) {
+		return ErrorFull
+	}
+
+	b.items[(b.head+b.size)%len(b.items)] = item
+	b.size++
+	return nil
+}
+
+// Get removes and returns the item at the front of the Buffer.
+func (b *Buffer[T]) Get() (T, error) {
+	var zero T
+	if b.disposed {
+		return zero, ErrorDisposed
+	} else if b.size == 0 {
+		return zero, ErrorEmpty
+	}
+
+	item := b.items[b.head]
+	// clear the slot, so that the Buffer does not keep e.g. slices alive
+	b.items[b.head] = zero
+	b.head = (b.head + 1) % len(b.items)
+	b.size--
+	return item, nil
+}
+
+// Do calls f on each item in the Buffer, from front to back, without removing
+// them; this only reads the Buffer, so it can be called under a read lock.
+// Unlike Put and Get, it still reads a disposed Buffer.
+func (b *Buffer[T]) Do(f func(T)) {
+	for i := 0; i < b.size; i++ {
+		f(b.items[(b.head+i)%len(b.items)])
+	}
+}
+
+// Len returns the number of items in the Buffer.
+func (b *Buffer[T]) Len() uint64 {
+	return uint64(b.size)
+}
+
+// Cap returns the maximum number of items the Buffer can hold.
+func (b *Buffer[T]) Cap() uint64 {
+	return uint64(len(b.items))
+}
+
+// Dispose disposes of the Buffer, so that calling Put or Get on it returns an error.
+func (b *Buffer[T]) Dispose() {
+	b.disposed = true
+}
+
+// IsDisposed returns whether the Buffer has been disposed.
+func (b *Buffer[T]) IsDisposed() bool {
+	return b.disposed
+}
+
+// Reset empties the Buffer, making it usable again if it has been disposed.
+func (b *Buffer[T]) Reset() {
+	var zero T
+	for i := range b.items {
+		b.items[i] = zero
+	}
+	b.head = 0
+	b.size = 0
+	b.disposed = false
+}
@@ -0,0 +1,152 @@
+package ring
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuffer(t *testing.T) {
+	t.Run("pass: items are returned in FIFO order across wraparound", func(t *testing.T) {
+		b := New[float64](3)
+		assert.Equal(t, uint64(3), b.Cap())
+
+		// slide a full window over the values many times the capacity,
+		// so that the head wraps around the backing slice repeatedly
+		for i := 0.; i < 3; i++ {
+			require.NoError(t, b.Put(i))
+		}
+		for i := 3.; i < 20; i++ {
+			x, err := b.Get()
+			require.NoError(t, err)
+			assert.Equal(t, i-3, x)
+			require.NoError(t, b.Put(i))
+			assert.Equal(t, uint64(3), b.Len())
+		}
+
+		for i := 17.; i < 20; i++ {
+			x, err := b.Get()
+			require.NoError(t, err)
+			assert.Equal(t, i, x)
+		}
+		assert.Equal(t, uint64(0), b.Len())
+	})
+
+	t.Run("pass: iterates from front to back across wraparound", func(t *testing.T) {
+		b := New[float64](3)
+		for i := 0.; i < 5; i++ {
+			if b.Len() == 3 {
+				_, err := b.Get()
+				require.NoError(t, err)
+			}
+			require.NoError(t, b.Put(i))
+		}
+
+		xs := []float64{}
+		b.Do(func(x float64) {
+			xs = append(xs, x)
+		})
+		assert.Equal(t, []float64{2, 3, 4}, xs)
+		// iterating leaves the contents intact
+		assert.Equal(t, uint64(3), b.Len())
+
+		// a disposed buffer can still be read
+		b.Dispose()
+		ys := []float64{}
+		b.Do(func(x float64) {
+			ys = append(ys, x)
+		})
+		assert.Equal(t, xs, ys)
+
+		b.Reset()
+		b.Do(func(float64) {
+			t.Fatal("empty buffer should not be iterated")
+		})
+	})
+
+	t.Run("pass: evicted slots are cleared", func(t *testing.T) {
+		b := New[[]float64](2)
+		require.NoError(t, b.Put([]float64{1, 2}))
+		require.NoError(t, b.Put([]float64{3, 4}))
+
+		xs, err := b.Get()
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1, 2}, xs)
+		assert.Nil(t, b.items[0])
+		assert.Equal(t, []float64{3, 4}, b.items[1])
+	})
+
+	t.Run("pass: reset empties a disposed buffer", func(t *testing.T) {
+		b := New[float64](2)
+		require.NoError(t, b.Put(1))
+		b.Dispose()
+		assert.True(t, b.IsDisposed())
+
+		b.Reset()
+		assert.False(t, b.IsDisposed())
+		assert.Equal(t, uint64(0), b.Len())
+		require.NoError(t, b.Put(2))
+		x, err := b.Get()
+		require.NoError(t, err)
+		assert.Equal(t, 2., x)
+	})
+
+	t.Run("fail: full buffer cannot be put to", func(t *testing.T) {
+		b := New[float64](1)
+		require.NoError(t, b.Put(1))
+		assert.ErrorIs(t, b.Put(2), ErrorFull)
+
+		assert.ErrorIs(t, New[float64](0).Put(1), ErrorFull)
+	})
+
+	t.Run("fail: empty buffer cannot be got from", func(t *testing.T) {
+		_, err := New[float64](1).Get()
+		assert.ErrorIs(t, err, ErrorEmpty)
+	})
+
+	t.Run("fail: disposed buffer cannot be used", func(t *testing.T) {
+		b := New[float64](2)
+		require.NoError(t, b.Put(1))
+		b.Dispose()
+
+		assert.ErrorIs(t, b.Put(2), ErrorDisposed)
+		_, err := b.Get()
+		assert.ErrorIs(t, err, ErrorDisposed)
+	})
+}
+
+// BenchmarkWindowPush compares sliding a full window of float64 values against
+// a window of interface{} values, which allocates to box each value.
+func BenchmarkWindowPush(b *testing.B) {
+	for _, window := range []uint64{8, 1024} {
+		b.Run(fmt.Sprintf("float64 [%d]", window), func(b *testing.B) {
+			q := New[float64](window)
+			for i := uint64(0); i < window; i++ {
+				require.NoError(b, q.Put(float64(i)))
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				x, _ := q.Get()
+				_ = q.Put(x + 1)
+			}
+		})
+
+		b.Run(fmt.Sprintf("interface{} [%d]", window), func(b *testing.B) {
+			q := New[interface{}](window)
+			for i := uint64(0); i < window; i++ {
+				require.NoError(b, q.Put(float64(i)))
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				x, _ := q.Get()
+				_ = q.Put(x.(float64) + 1)
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package test
 
 import (
+	"fmt"
 	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	mathutil "github.com/K4Mobility/stream/util/math"
 )
 
 var precision = 9
@@ -31,3 +34,12 @@ func ApproxSlice(t *testing.T, xs []float64, ys []float64) {
 		Approx(t, xs[i], ys[i], "elements differ at index %d", i)
 	}
 }
+
+// ApproxDigits asserts that two floats are approximately equal to each other,
+// i.e. that they differ by at most half a unit in the last of the given number
+// of decimal places (see mathutil.ApproxEqual).
+func ApproxDigits(t *testing.T, x float64, y float64, digits int, msgAndArgs ...interface{}) {
+	if !mathutil.ApproxEqual(x, y, digits) {
+		assert.Fail(t, fmt.Sprintf("%v and %v differ in the first %d decimal places", x, y, digits), msgAndArgs...)
+	}
+}